@@ -0,0 +1,128 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package applyset makes resources members of an ApplySet,
+// the set of objects that kubectl prunes together, per
+// https://git.k8s.io/enhancements/keps/sig-cli/3659-kubectl-apply-prune
+package applyset
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+const (
+	// LabelId identifies the parent of an ApplySet.
+	LabelId = "applyset.kubernetes.io/id"
+	// LabelPartOf holds the id of the ApplySet of a member.
+	LabelPartOf = "applyset.kubernetes.io/part-of"
+	// AnnotationTooling names the tool managing the ApplySet.
+	AnnotationTooling = "applyset.kubernetes.io/tooling"
+	// AnnotationContainsGroupKinds lists the kinds of the members.
+	AnnotationContainsGroupKinds = "applyset.kubernetes.io/contains-group-kinds"
+	// AnnotationAdditionalNamespaces lists the namespaces of the
+	// members, other than the namespace of the parent.
+	AnnotationAdditionalNamespaces = "applyset.kubernetes.io/additional-namespaces"
+)
+
+// Tooling is the tooling of the ApplySets kustomize makes.
+// They're kubectl's, as kubectl refuses to apply or prune
+// an ApplySet whose tooling names another tool; kubectl
+// compares only the name, not the version.
+const Tooling = "kubectl/v1.27.0"
+
+// defaultNamespace holds the parent of members in several namespaces.
+const defaultNamespace = "default"
+
+// Parent is the object recording an ApplySet.
+type Parent struct {
+	// Kind is Secret or ConfigMap.
+	Kind string
+	Name string
+	// Namespace of the parent; if empty, the namespace
+	// of the members if they share one, else "default".
+	Namespace string
+}
+
+// Id returns the id of the ApplySet of the parent, the
+// hash of the parent's name, namespace, kind and group.
+func (p Parent) Id() string {
+	sum := sha256.Sum256([]byte(
+		strings.Join([]string{p.Name, p.Namespace, p.Kind, ""}, ".")))
+	return "applyset-" + base64.RawURLEncoding.EncodeToString(sum[:]) + "-v1"
+}
+
+// Stamp labels the resources as members of the ApplySet
+// of the parent, and appends the parent to them.
+func Stamp(m resmap.ResMap, rf *resource.Factory, p Parent) error {
+	if p.Kind != "Secret" && p.Kind != "ConfigMap" {
+		return fmt.Errorf(
+			"the parent of an applyset must be a Secret or ConfigMap, not %s", p.Kind)
+	}
+	if p.Name == "" {
+		return fmt.Errorf("the parent of an applyset must have a name")
+	}
+	namespaces := make(map[string]bool)
+	groupKinds := make(map[string]bool)
+	for _, r := range m.Resources() {
+		gvk := r.GetGvk()
+		gk := gvk.Kind
+		if gvk.Group != "" {
+			gk += "." + gvk.Group
+		}
+		groupKinds[gk] = true
+		if ns := r.GetNamespace(); ns != "" && gvk.IsNamespaceableKind() {
+			namespaces[ns] = true
+		}
+	}
+	if p.Namespace == "" {
+		p.Namespace = defaultNamespace
+		if len(namespaces) == 1 {
+			for ns := range namespaces {
+				p.Namespace = ns
+			}
+		}
+	}
+	delete(namespaces, p.Namespace)
+	id := p.Id()
+	for _, r := range m.Resources() {
+		labels := r.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[LabelPartOf] = id
+		r.SetLabels(labels)
+	}
+	annotations := map[string]interface{}{
+		AnnotationTooling:            Tooling,
+		AnnotationContainsGroupKinds: strings.Join(sortedKeys(groupKinds), ","),
+	}
+	if len(namespaces) > 0 {
+		annotations[AnnotationAdditionalNamespaces] = strings.Join(sortedKeys(namespaces), ",")
+	}
+	return m.Append(rf.FromMap(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       p.Kind,
+		"metadata": map[string]interface{}{
+			"name":        p.Name,
+			"namespace":   p.Namespace,
+			"labels":      map[string]interface{}{LabelId: id},
+			"annotations": annotations,
+		},
+	}))
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package applyset
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	resmaptest_test "sigs.k8s.io/kustomize/api/testutils/resmaptest"
+)
+
+var rf = resource.NewFactory(
+	kunstruct.NewKunstructuredFactoryImpl())
+
+func TestId(t *testing.T) {
+	testCases := map[string]struct {
+		parent   Parent
+		expected string
+	}{
+		"secret": {
+			parent:   Parent{Kind: "Secret", Name: "my-set", Namespace: "prod"},
+			expected: "applyset-33jb7s1fLYA5Aiyr0Q3erXIf_pqlOUTv9GxRUYFRzNA-v1",
+		},
+		"kindMatters": {
+			parent:   Parent{Kind: "ConfigMap", Name: "my-set", Namespace: "prod"},
+			expected: "applyset-v6VHwH8Ycvb6h070cMT9Ezo0Wza9Gnn_PWK7B9XLF1k-v1",
+		},
+	}
+	for n, tc := range testCases {
+		if id := tc.parent.Id(); id != tc.expected {
+			t.Errorf("%s: expected %s, got %s", n, tc.expected, id)
+		}
+	}
+}
+
+// kubectlParent returns the parent of the ApplySet in m, failing
+// as kubectl apply --applyset does on a parent it can't manage.
+func kubectlParent(m resmap.ResMap) (*resource.Resource, error) {
+	for _, r := range m.Resources() {
+		id, ok := r.GetLabels()[LabelId]
+		if !ok {
+			continue
+		}
+		p := Parent{Kind: r.GetKind(), Name: r.GetName(), Namespace: r.GetNamespace()}
+		if id != p.Id() {
+			return nil, fmt.Errorf("parent has incorrect value for label %s", LabelId)
+		}
+		tooling := r.GetAnnotations()[AnnotationTooling]
+		if name := strings.Split(tooling, "/")[0]; name != "kubectl" {
+			return nil, fmt.Errorf("parent is already managed by tooling %q", name)
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("no parent")
+}
+
+// kubectlPrunes tells whether kubectl, pruning the ApplySet
+// of the parent, lists the object as a member, per the kinds
+// and namespaces the parent annotates.
+func kubectlPrunes(parent, r *resource.Resource) bool {
+	if r.GetLabels()[LabelPartOf] != parent.GetLabels()[LabelId] {
+		return false
+	}
+	gvk := r.GetGvk()
+	gk := gvk.Kind
+	if gvk.Group != "" {
+		gk += "." + gvk.Group
+	}
+	a := parent.GetAnnotations()
+	if !contains(a[AnnotationContainsGroupKinds], gk) {
+		return false
+	}
+	return !gvk.IsNamespaceableKind() ||
+		r.GetNamespace() == parent.GetNamespace() ||
+		contains(a[AnnotationAdditionalNamespaces], r.GetNamespace())
+}
+
+func contains(list, s string) bool {
+	for _, e := range strings.Split(list, ",") {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func makeMembers(t *testing.T) resmap.ResMap {
+	return resmaptest_test.NewRmBuilder(t, rf).
+		AddWithNsAndName("prod", "app", map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+		}).
+		AddWithNsAndName("common", "shared", map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+		}).
+		AddWithName("reader", map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+		}).ResMap()
+}
+
+// kubectl takes over an ApplySet kustomize makes,
+// and prunes the members dropped from it.
+func TestStampForKubectl(t *testing.T) {
+	p := Parent{Kind: "Secret", Name: "my-set", Namespace: "prod"}
+	old := makeMembers(t)
+	if err := Stamp(old, rf, p); err != nil {
+		t.Fatal(err)
+	}
+	oldParent, err := kubectlParent(old)
+	if err != nil {
+		t.Fatalf("kubectl refuses the parent: %v", err)
+	}
+	for _, r := range old.Resources() {
+		if r != oldParent && !kubectlPrunes(oldParent, r) {
+			t.Errorf("kubectl doesn't list %s as a member", r.CurId())
+		}
+	}
+
+	m := makeMembers(t)
+	if err = m.Remove(m.Resources()[1].CurId()); err != nil {
+		t.Fatal(err)
+	}
+	if err = Stamp(m, rf, p); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := kubectlParent(m)
+	if err != nil {
+		t.Fatalf("kubectl refuses the parent: %v", err)
+	}
+	// kubectl prunes the members of the kinds and
+	// namespaces the live, old, parent annotates.
+	dropped := old.Resources()[1]
+	if !kubectlPrunes(oldParent, dropped) {
+		t.Errorf("kubectl doesn't prune %s", dropped.CurId())
+	}
+	if parent.GetLabels()[LabelId] != oldParent.GetLabels()[LabelId] {
+		t.Errorf("the id of the ApplySet changed")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package buildcache lets builds reuse what earlier
+// builds made from inputs that haven't changed since.
+//
+// As a build reads its inputs, a Recorder lists them
+// in a Manifest: the files read, by content, the remote
+// bases fetched, by ref, the plugins run, by content
+// or image digest, and the helm charts inflated, by
+// version.  What's made is put in a Cache with the
+// manifest of its inputs, and is reused as long as the
+// manifest holds.
+//
+// Files that plugins read on their own, rather than
+// through the loader they're given, aren't recorded,
+// so changing only them calls for a build without
+// the cache.
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+// Cache holds what builds made, by key, with the
+// manifest of the inputs it was made from.
+// It's safe for concurrent use.
+type Cache struct {
+	// dir is where outputs are persisted,
+	// if anywhere.
+	dir string
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	manifest Manifest
+	value    interface{}
+}
+
+// persisted is the form of an output in the
+// cache's directory.
+type persisted struct {
+	Manifest Manifest `json:"manifest"`
+	Data     []byte   `json:"data"`
+}
+
+// New returns an empty Cache persisting outputs under
+// dir, so that later processes can reuse them, or
+// keeping them in memory only, if dir is empty.
+func New(dir string) *Cache {
+	return &Cache{dir: dir, entries: map[string]entry{}}
+}
+
+// Get returns the value put under the key, and the
+// manifest of its inputs, if the manifest still holds
+// for files read from fSys.
+func (c *Cache) Get(
+	key string, fSys filesys.FileSystem) (interface{}, Manifest, bool) {
+	c.mu.Lock()
+	e, found := c.entries[key]
+	c.mu.Unlock()
+	if !found || !e.manifest.Unchanged(fSys) {
+		return nil, Manifest{}, false
+	}
+	return e.value, e.manifest, true
+}
+
+// Put puts the value under the key, with the
+// manifest of the inputs it was made from.
+// The value is kept in memory only.
+func (c *Cache) Put(key string, m Manifest, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry{manifest: m, value: v}
+}
+
+// Read returns the output written under the key,
+// if the manifest of its inputs still holds for
+// files read from fSys.
+func (c *Cache) Read(key string, fSys filesys.FileSystem) ([]byte, bool) {
+	v, _, found := c.Get(key, fSys)
+	if data, ok := v.([]byte); found && ok {
+		return data, true
+	}
+	if c.dir == "" {
+		return nil, false
+	}
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var p persisted
+	if err = json.Unmarshal(b, &p); err != nil {
+		return nil, false
+	}
+	if !p.Manifest.Unchanged(fSys) {
+		return nil, false
+	}
+	c.Put(key, p.Manifest, p.Data)
+	return p.Data, true
+}
+
+// Write writes the output under the key, with
+// the manifest of the inputs it was made from.
+// An output depending on anything volatile isn't
+// persisted, as it would never be reused.
+func (c *Cache) Write(key string, m Manifest, data []byte) error {
+	c.Put(key, m, data)
+	if c.dir == "" || len(m.Volatile) > 0 {
+		return nil
+	}
+	b, err := json.Marshal(persisted{Manifest: m, Data: data})
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	// Written aside and renamed, so that concurrent
+	// builds never read a partial output.
+	f, err := ioutil.TempFile(c.dir, "output")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key))))
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package buildcache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/buildcache"
+	"sigs.k8s.io/kustomize/api/filesys"
+	fLdr "sigs.k8s.io/kustomize/api/loader"
+)
+
+func TestRecorder(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/app/a.yaml", []byte("a"))
+	fSys.WriteFile("/app/base/b.yaml", []byte("b"))
+	ldr, err := fLdr.NewLoader(fLdr.RestrictionRootOnly, "/app", fSys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := buildcache.NewRecorder()
+	ldr = rec.Loader(ldr)
+	child := rec.Child()
+	base, err := ldr.New("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base = child.Loader(base)
+	if _, err = ldr.Load("a.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = base.Load("b.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = base.Load("missing.yaml"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	m := rec.Manifest()
+	if len(m.Files) != 3 || m.Files["/app/base/missing.yaml"] != "" {
+		t.Fatalf("unexpected files %v", m.Files)
+	}
+	c := child.Manifest()
+	if len(c.Files) != 2 || c.Files["/app/a.yaml"] != "" {
+		t.Fatalf("unexpected files of the base %v", c.Files)
+	}
+	if !m.Unchanged(fSys) || !c.Unchanged(fSys) {
+		t.Fatal("expected the manifests to hold")
+	}
+	fSys.WriteFile("/app/a.yaml", []byte("changed"))
+	if m.Unchanged(fSys) {
+		t.Fatal("expected a changed file to be noticed")
+	}
+	if !c.Unchanged(fSys) {
+		t.Fatal("expected the manifest of the base to hold")
+	}
+	fSys.WriteFile("/app/base/missing.yaml", []byte("new"))
+	if c.Unchanged(fSys) {
+		t.Fatal("expected a new file to be noticed")
+	}
+}
+
+func TestManifestUnchanged(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testCases := map[string]struct {
+		record    func(r *buildcache.Recorder)
+		unchanged bool
+	}{
+		"remoteAtCommit": {
+			record: func(r *buildcache.Recorder) {
+				r.Remote("github.com/org/repo//base?ref=" +
+					"0123456789abcdef0123456789abcdef01234567")
+			},
+			unchanged: true,
+		},
+		"remoteAtBranch": {
+			record: func(r *buildcache.Recorder) {
+				r.Remote("github.com/org/repo//base?ref=main")
+			},
+		},
+		"pinnedChart": {
+			record:    func(r *buildcache.Recorder) { r.Chart("minecraft", "1.2.3") },
+			unchanged: true,
+		},
+		"unpinnedChart": {
+			record: func(r *buildcache.Recorder) { r.Chart("minecraft", "") },
+		},
+		"pinnedImage": {
+			record: func(r *buildcache.Recorder) {
+				r.Image("example.com/fn@sha256:0123")
+			},
+			unchanged: true,
+		},
+		"taggedImage": {
+			record: func(r *buildcache.Recorder) { r.Image("example.com/fn:v1") },
+		},
+		"url": {
+			record: func(r *buildcache.Recorder) { r.Volatile("https://example.com/a.yaml") },
+		},
+		"env": {
+			record: func(r *buildcache.Recorder) {
+				r.Env("KUSTOMIZE_BUILDCACHE_TEST", os.Getenv("KUSTOMIZE_BUILDCACHE_TEST"))
+			},
+			unchanged: true,
+		},
+		"changedEnv": {
+			record: func(r *buildcache.Recorder) {
+				r.Env("KUSTOMIZE_BUILDCACHE_TEST", os.Getenv("KUSTOMIZE_BUILDCACHE_TEST")+"x")
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			r := buildcache.NewRecorder()
+			tc.record(r)
+			m := r.Manifest()
+			if m.Unchanged(fSys) != tc.unchanged {
+				t.Fatalf("expected unchanged to be %v for %v", tc.unchanged, m)
+			}
+		})
+	}
+}
+
+func TestCachePersisted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-build-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/app/a.yaml", []byte("a"))
+	content, err := fSys.ReadFile("/app/a.yaml")
+	rec := buildcache.NewRecorder()
+	rec.File("/app/a.yaml", content, err)
+
+	if err = buildcache.New(dir).Write("k", rec.Manifest(), []byte("out")); err != nil {
+		t.Fatal(err)
+	}
+	c := buildcache.New(dir)
+	if data, found := c.Read("k", fSys); !found || string(data) != "out" {
+		t.Fatalf("expected the output read back, got %q, %v", data, found)
+	}
+	fSys.WriteFile("/app/a.yaml", []byte("changed"))
+	if _, found := buildcache.New(dir).Read("k", fSys); found {
+		t.Fatal("expected the output to be stale")
+	}
+
+	rec.Volatile("https://example.com/a.yaml")
+	if err = buildcache.New(dir).Write("v", rec.Manifest(), []byte("out")); err != nil {
+		t.Fatal(err)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected only the first output persisted, got %d files", len(files))
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package buildcache
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/internal/git"
+)
+
+// Manifest lists what a build, or the accumulation
+// of one of its kustomizations, depended on.
+type Manifest struct {
+	// Files holds the sha256 of each file read, by
+	// absolute path; empty if it couldn't be read.
+	Files map[string]string `json:"files,omitempty"`
+
+	// Remotes holds the ref of each remote base
+	// fetched, by url.  Its files aren't listed.
+	Remotes map[string]string `json:"remotes,omitempty"`
+
+	// Plugins holds the sha256 of each plugin run,
+	// by path, or the digest of the image of each
+	// container function, by image.
+	Plugins map[string]string `json:"plugins,omitempty"`
+
+	// Charts holds the version of each helm chart
+	// inflated, by name; empty if not pinned.
+	Charts map[string]string `json:"charts,omitempty"`
+
+	// Env holds the value of each environment variable
+	// read, e.g. by env files, by name; empty if unset.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Volatile lists what was depended on that can't be
+	// checked for changes, e.g. urls, unpinned images
+	// and functions with network access or mounts.
+	Volatile []string `json:"volatile,omitempty"`
+}
+
+func newManifest() Manifest {
+	return Manifest{
+		Files:   map[string]string{},
+		Remotes: map[string]string{},
+		Plugins: map[string]string{},
+		Charts:  map[string]string{},
+		Env:     map[string]string{},
+	}
+}
+
+// commit matches a full git commit hash, which,
+// unlike branches and tags, pins a remote base.
+var commit = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Unchanged returns whether all that's listed is as
+// it was when listed, reading files from fSys, plugins
+// from disk and variables from the environment.  Remote bases must be pinned to
+// commits, and charts to versions, to be unchanged.
+func (m *Manifest) Unchanged(fSys filesys.FileSystem) bool {
+	if len(m.Volatile) > 0 {
+		return false
+	}
+	for _, ref := range m.Remotes {
+		if !commit.MatchString(ref) {
+			return false
+		}
+	}
+	for _, version := range m.Charts {
+		if version == "" {
+			return false
+		}
+	}
+	for name, value := range m.Env {
+		if os.Getenv(name) != value {
+			return false
+		}
+	}
+	for path, sum := range m.Files {
+		if fileSum(fSys.ReadFile(path)) != sum {
+			return false
+		}
+	}
+	for p, sum := range m.Plugins {
+		// images are listed only if pinned to digests
+		if filepath.IsAbs(p) && fileSum(ioutil.ReadFile(p)) != sum {
+			return false
+		}
+	}
+	return true
+}
+
+// merge adds what the other manifest lists.
+func (m *Manifest) merge(other Manifest) {
+	for k, v := range other.Files {
+		m.Files[k] = v
+	}
+	for k, v := range other.Remotes {
+		m.Remotes[k] = v
+	}
+	for k, v := range other.Plugins {
+		m.Plugins[k] = v
+	}
+	for k, v := range other.Charts {
+		m.Charts[k] = v
+	}
+	for k, v := range other.Env {
+		m.Env[k] = v
+	}
+	m.Volatile = append(m.Volatile, other.Volatile...)
+}
+
+// copy returns a copy of the manifest.
+func (m *Manifest) copy() Manifest {
+	result := newManifest()
+	result.merge(*m)
+	return result
+}
+
+// fileSum returns the sha256 of the content
+// of a file, or "" if it couldn't be read.
+func fileSum(content []byte, err error) string {
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content))
+}
+
+// remoteRef returns the ref of the remote base at url.
+func remoteRef(url string) string {
+	spec, err := git.NewRepoSpecFromUrl(url)
+	if err != nil {
+		return ""
+	}
+	return spec.Ref
+}
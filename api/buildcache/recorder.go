@@ -0,0 +1,170 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package buildcache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/git"
+)
+
+// Recorder records what a build depends on into a Manifest.
+// It's safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	m      Manifest
+	parent *Recorder
+}
+
+// NewRecorder returns a Recorder with an empty manifest.
+func NewRecorder() *Recorder {
+	return &Recorder{m: newManifest()}
+}
+
+// Child returns a Recorder whose records are also
+// made by this one, to list what a part of the
+// build depends on apart from the rest.
+func (r *Recorder) Child() *Recorder {
+	return &Recorder{m: newManifest(), parent: r}
+}
+
+// Manifest returns a copy of what's been recorded so far.
+func (r *Recorder) Manifest() Manifest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.m.copy()
+}
+
+// Include records all that the manifest lists, e.g.
+// when reusing what was built from its inputs.
+func (r *Recorder) Include(m Manifest) {
+	r.record(func(mine *Manifest) { mine.merge(m) })
+}
+
+// File records the content of the file at the absolute
+// path, or that it couldn't be read.
+func (r *Recorder) File(path string, content []byte, err error) {
+	sum := fileSum(content, err)
+	r.record(func(m *Manifest) { m.Files[path] = sum })
+}
+
+// Remote records the remote base at url.
+func (r *Recorder) Remote(url string) {
+	ref := remoteRef(url)
+	r.record(func(m *Manifest) { m.Remotes[url] = ref })
+}
+
+// Plugin records the file at the absolute path that
+// a plugin is loaded from, or that it doesn't exist.
+func (r *Recorder) Plugin(path string) {
+	sum := fileSum(ioutil.ReadFile(path))
+	r.record(func(m *Manifest) { m.Plugins[path] = sum })
+}
+
+// Image records the image of a container function.
+// Only an image pinned to a digest is known not to change.
+func (r *Recorder) Image(image string) {
+	i := strings.LastIndex(image, "@")
+	if i < 0 {
+		r.Volatile("image " + image)
+		return
+	}
+	r.record(func(m *Manifest) { m.Plugins[image] = image[i+1:] })
+}
+
+// Chart records the version of a helm chart,
+// which may be empty if it's not pinned.
+func (r *Recorder) Chart(name, version string) {
+	r.record(func(m *Manifest) { m.Charts[name] = version })
+}
+
+// Env records the value of an environment variable.
+func (r *Recorder) Env(name, value string) {
+	r.record(func(m *Manifest) { m.Env[name] = value })
+}
+
+// Volatile records something depended on
+// that can't be checked for changes.
+func (r *Recorder) Volatile(what string) {
+	r.record(func(m *Manifest) { m.Volatile = append(m.Volatile, what) })
+}
+
+func (r *Recorder) record(f func(m *Manifest)) {
+	for ; r != nil; r = r.parent {
+		r.mu.Lock()
+		f(&r.m)
+		r.mu.Unlock()
+	}
+}
+
+// Loader returns a loader recording into r what's loaded
+// through ldr, and through the loaders it makes.
+func (r *Recorder) Loader(ldr ifc.Loader) ifc.Loader {
+	if rl, ok := ldr.(*recordingLoader); ok {
+		if rl.remote != "" {
+			r.Remote(rl.remote)
+		}
+		return &recordingLoader{Loader: rl.Loader, rec: r, remote: rl.remote}
+	}
+	return &recordingLoader{Loader: ldr, rec: r}
+}
+
+// RemoteLoader returns a loader recording into r the
+// remote base at url, which ldr is rooted in a clone of.
+// The files of the clone aren't recorded.
+func (r *Recorder) RemoteLoader(ldr ifc.Loader, url string) ifc.Loader {
+	r.Remote(url)
+	return &recordingLoader{Loader: ldr, rec: r, remote: url}
+}
+
+// recordingLoader records what's loaded by a loader.
+type recordingLoader struct {
+	ifc.Loader
+	rec *Recorder
+	// remote is the url of the remote base
+	// the loader is rooted in a clone of, if any.
+	remote string
+}
+
+// New returns a recording loader rooted at newRoot.
+func (l *recordingLoader) New(newRoot string) (ifc.Loader, error) {
+	ldr, err := l.Loader.New(newRoot)
+	if err != nil {
+		return nil, err
+	}
+	if _, errGit := git.NewRepoSpecFromUrl(newRoot); errGit == nil {
+		return l.rec.RemoteLoader(ldr, newRoot), nil
+	}
+	return &recordingLoader{Loader: ldr, rec: l.rec, remote: l.remote}, nil
+}
+
+// Getenv returns the value of the environment
+// variable, recording it.
+func (l *recordingLoader) Getenv(name string) string {
+	value := os.Getenv(name)
+	l.rec.Env(name, value)
+	return value
+}
+
+// Load records what's loaded from the location.
+func (l *recordingLoader) Load(location string) ([]byte, error) {
+	content, err := l.Loader.Load(location)
+	switch {
+	case strings.HasPrefix(location, "http://"),
+		strings.HasPrefix(location, "https://"):
+		l.rec.Volatile(location)
+	case l.remote == "":
+		path := location
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(l.Root(), path)
+		}
+		l.rec.File(path, content, err)
+	}
+	return content, err
+}
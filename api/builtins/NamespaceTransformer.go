@@ -33,7 +33,7 @@ func (p *NamespaceTransformerPlugin) Transform(m resmap.ResMap) error {
 		return nil
 	}
 	for _, r := range m.Resources() {
-		if len(r.Map()) == 0 {
+		if r.IsEmpty() {
 			// Don't mutate empty objects?
 			continue
 		}
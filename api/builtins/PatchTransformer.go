@@ -23,6 +23,7 @@ type PatchTransformerPlugin struct {
 	Path         string          `json:"path,omitempty" yaml:"path,omitempty"`
 	Patch        string          `json:"patch,omitempty" yaml:"patch,omitempty"`
 	Target       *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+	Strict       bool            `json:"strict,omitempty" yaml:"strict,omitempty"`
 }
 
 func (p *PatchTransformerPlugin) Config(
@@ -110,12 +111,13 @@ func (p *PatchTransformerPlugin) transformStrategicMerge(m resmap.ResMap, patch
 // applySMPatch applies the provided strategic merge patch to the
 // given resource.
 func (p *PatchTransformerPlugin) applySMPatch(resource, patch *resource.Resource) error {
-	node, err := filtersutil.GetRNode(patch)
+	node, err := patch.CopyRNode()
 	if err != nil {
 		return err
 	}
 	return filtersutil.ApplyToJSON(patchstrategicmerge.Filter{
-		Patch: node,
+		Patch:  node,
+		Strict: p.Strict,
 	}, resource)
 }
 
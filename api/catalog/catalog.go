@@ -0,0 +1,151 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package catalog loads signed catalogs of the functions
+// and plugins approved to run.
+//
+// Catalogs are signed as by sigstore's
+// "cosign sign-blob --key": the signature of a catalog is
+// the base64 encoded signature of the catalog file, made
+// with an ECDSA, ed25519 or RSA key, and is found beside
+// the catalog, its URL suffixed with ".sig".
+package catalog
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// SignatureSuffix is appended to the URL of
+// a catalog to find its signature.
+const SignatureSuffix = ".sig"
+
+const fetchTimeout = 30 * time.Second
+
+// Source locates a signed catalog.
+type Source struct {
+	// URL of the catalog; an http or https URL,
+	// else a path in the file system.
+	URL string
+
+	// Path in the file system of the PEM encoded
+	// public key verifying the catalog's signature.
+	PublicKey string
+}
+
+// Load fetches the catalogs, verifies their signatures,
+// and returns the catalog approving the functions of all.
+func Load(fSys filesys.FileSystem, sources []Source) (*types.Catalog, error) {
+	result := &types.Catalog{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.CatalogVersion,
+			Kind:       types.CatalogKind,
+		},
+	}
+	for _, s := range sources {
+		c, err := load(fSys, s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading catalog %s", s.URL)
+		}
+		result.Merge(c)
+	}
+	return result, nil
+}
+
+func load(fSys filesys.FileSystem, s Source) (*types.Catalog, error) {
+	key, err := fSys.ReadFile(s.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := fetch(fSys, s.URL)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := fetch(fSys, s.URL+SignatureSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if err = Verify(payload, signature, key); err != nil {
+		return nil, err
+	}
+	var c types.Catalog
+	if err = yaml.UnmarshalStrict(payload, &c); err != nil {
+		return nil, err
+	}
+	if err = c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func fetch(fSys filesys.FileSystem, url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return fSys.ReadFile(url)
+	}
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Verify returns an error unless the signature, base64
+// encoded or not, is that of the payload made with the
+// private key of the given PEM encoded public key.
+func Verify(payload, signature, pemKey []byte) error {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return fmt.Errorf("public key isn't PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parsing public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(
+		strings.TrimSpace(string(signature)))
+	if err != nil {
+		sig = signature
+	}
+	digest := sha256.Sum256(payload)
+	var ok bool
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var rs struct{ R, S *big.Int }
+		if _, err = asn1.Unmarshal(sig, &rs); err == nil {
+			ok = ecdsa.Verify(k, digest[:], rs.R, rs.S)
+		}
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, payload, sig)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
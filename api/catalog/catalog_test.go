@@ -0,0 +1,141 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package catalog_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/catalog"
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+const approved = `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Catalog
+metadata:
+  name: approved
+functions:
+- name: label-namespace
+  image: gcr.io/kpt-functions/label-namespace@sha256:4f03
+- name: sed
+  sha256: 9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08
+`
+
+func pemKey(t *testing.T, key interface{}) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// signECDSA signs the payload as "cosign sign-blob" does.
+func signECDSA(t *testing.T, key *ecdsa.PrivateKey, payload string) []byte {
+	digest := sha256.Sum256([]byte(payload))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := asn1.Marshal(struct{ R, S interface{} }{r, s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(sig))
+}
+
+func TestLoad(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/keys/cosign.pub", pemKey(t, &key.PublicKey))
+	fSys.WriteFile("/keys/other.pub", pemKey(t, &otherKey.PublicKey))
+	fSys.WriteFile("/catalogs/approved.yaml", []byte(approved))
+	fSys.WriteFile("/catalogs/approved.yaml.sig", signECDSA(t, key, approved))
+	fSys.WriteFile("/catalogs/tampered.yaml", []byte(approved+"- image: evil\n"))
+	fSys.WriteFile("/catalogs/tampered.yaml.sig", signECDSA(t, key, approved))
+	fSys.WriteFile("/catalogs/unsigned.yaml", []byte(approved))
+	malformed := strings.Replace(approved, "  image:", "  sha256: 00\n  image:", 1)
+	fSys.WriteFile("/catalogs/malformed.yaml", []byte(malformed))
+	fSys.WriteFile("/catalogs/malformed.yaml.sig", signECDSA(t, key, malformed))
+
+	c, err := Load(fSys, []Source{
+		{URL: "/catalogs/approved.yaml", PublicKey: "/keys/cosign.pub"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.ApprovesImage("gcr.io/kpt-functions/label-namespace@sha256:4f03") {
+		t.Errorf("expected image to be approved")
+	}
+	if c.ApprovesImage("gcr.io/kpt-functions/label-namespace") {
+		t.Errorf("expected unpinned image not to be approved")
+	}
+	if !c.ApprovesSha256("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08") {
+		t.Errorf("expected digest to be approved")
+	}
+
+	for source, errMsg := range map[Source]string{
+		{URL: "/catalogs/tampered.yaml", PublicKey: "/keys/cosign.pub"}:  "invalid signature",
+		{URL: "/catalogs/approved.yaml", PublicKey: "/keys/other.pub"}:   "invalid signature",
+		{URL: "/catalogs/unsigned.yaml", PublicKey: "/keys/cosign.pub"}:  "unsigned.yaml.sig",
+		{URL: "/catalogs/malformed.yaml", PublicKey: "/keys/cosign.pub"}: "exactly one of image and sha256",
+	} {
+		_, err = Load(fSys, []Source{source})
+		if err == nil || !strings.Contains(err.Error(), errMsg) {
+			t.Errorf("%s: expected error %q, got %v", source.URL, errMsg, err)
+		}
+	}
+}
+
+func TestLoadRemote(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(approved)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/approved.yaml":
+			w.Write([]byte(approved))
+		case "/approved.yaml.sig":
+			w.Write([]byte(sig))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/cosign.pub", pemKey(t, pub))
+
+	c, err := Load(fSys, []Source{
+		{URL: server.URL + "/approved.yaml", PublicKey: "/cosign.pub"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Functions) != 2 {
+		t.Errorf("expected 2 functions, got %v", c.Functions)
+	}
+	_, err = Load(fSys, []Source{
+		{URL: server.URL + "/missing.yaml", PublicKey: "/cosign.pub"}})
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
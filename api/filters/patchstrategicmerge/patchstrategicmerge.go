@@ -4,13 +4,23 @@
 package patchstrategicmerge
 
 import (
+	"fmt"
+	"strings"
+
 	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 	"sigs.k8s.io/kustomize/kyaml/yaml/merge2"
 )
 
 type Filter struct {
 	Patch *yaml.RNode
+
+	// When true, the patch fails, rather than being
+	// merged as well as can be, if the schema of the
+	// kind of a node is unknown or lacks a field the
+	// patch sets, e.g. because of a typo.
+	Strict bool
 }
 
 var _ kio.Filter = Filter{}
@@ -18,6 +28,11 @@ var _ kio.Filter = Filter{}
 func (pf Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 	var result []*yaml.RNode
 	for i := range nodes {
+		if pf.Strict {
+			if err := checkPatch(pf.Patch, nodes[i]); err != nil {
+				return nil, err
+			}
+		}
 		r, err := merge2.Merge(pf.Patch, nodes[i])
 		if err != nil {
 			return nil, err
@@ -26,3 +41,76 @@ func (pf Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 	}
 	return result, nil
 }
+
+// checkPatch returns an error if the schema of the kind
+// of the node is unknown, or lacks a field the patch sets.
+// The schemas of custom resources declared with no group
+// and version, as in the crds of a kustomization, are
+// found by kind alone.
+func checkPatch(patch, node *yaml.RNode) error {
+	meta, err := node.GetMeta()
+	if err != nil {
+		return err
+	}
+	s := openapi.SchemaForResourceType(meta.TypeMeta)
+	if s == nil {
+		s = openapi.SchemaForResourceType(yaml.TypeMeta{Kind: meta.Kind})
+	}
+	if s == nil {
+		return fmt.Errorf(
+			"strict patch of %s %s: no schema for kind %s of %s",
+			meta.Kind, meta.Name, meta.Kind, meta.APIVersion)
+	}
+	if err = checkFields(patch.YNode(), s, ""); err != nil {
+		return fmt.Errorf(
+			"strict patch of %s %s: %v", meta.Kind, meta.Name, err)
+	}
+	return nil
+}
+
+// checkFields returns an error if the schema lacks a field
+// set by n, found at path, skipping patch directives and
+// the content of objects whose fields may be anything.
+func checkFields(n *yaml.Node, s *openapi.ResourceSchema, path string) error {
+	switch n.Kind {
+	case yaml.MappingNode:
+		if len(s.Schema.Type) > 0 && !s.Schema.Type.Contains("object") {
+			return fmt.Errorf("%s is of type %s, not an object", path, s.Schema.Type[0])
+		}
+		if len(s.Schema.Properties) == 0 && s.Schema.AdditionalProperties == nil {
+			return nil
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			field := n.Content[i].Value
+			if strings.HasPrefix(field, "$") {
+				continue
+			}
+			fieldPath := strings.TrimPrefix(path+"."+field, ".")
+			fs := s.Field(field)
+			if fs == nil {
+				return fmt.Errorf("unknown field %s", fieldPath)
+			}
+			if err := checkFields(n.Content[i+1], fs, fieldPath); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		if len(s.Schema.Type) == 0 {
+			return nil
+		}
+		if !s.Schema.Type.Contains("array") {
+			return fmt.Errorf("%s is of type %s, not a list", path, s.Schema.Type[0])
+		}
+		es := s.Elements()
+		if es == nil {
+			return fmt.Errorf("no schema for the elements of %s", path)
+		}
+		for i, e := range n.Content {
+			err := checkFields(e, es, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
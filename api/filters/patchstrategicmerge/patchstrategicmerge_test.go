@@ -160,3 +160,140 @@ spec:
 		})
 	}
 }
+
+func TestFilterStrict(t *testing.T) {
+	input := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+`
+	testCases := map[string]struct {
+		input    string
+		patch    *yaml.RNode
+		expected string
+		errMsg   string
+	}{
+		"known fields": {
+			input: input,
+			patch: yaml.MustParse(`
+metadata:
+  labels:
+    app: nginx
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.19
+        resources:
+          limits:
+            cpu: 100m
+`),
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+  labels:
+    app: nginx
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.19
+        resources:
+          limits:
+            cpu: 100m
+  replicas: 3
+`,
+		},
+		"directives": {
+			input: input,
+			patch: yaml.MustParse(`
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        $patch: delete
+`),
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+spec:
+  template:
+    spec:
+      containers: []
+`,
+		},
+		"typo": {
+			input: input,
+			patch: yaml.MustParse(`
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        imagePullPolicy: Always
+        imgae: nginx:1.19
+`),
+			errMsg: "strict patch of Deployment myDeploy: " +
+				"unknown field spec.template.spec.containers[0].imgae",
+		},
+		"object for a scalar": {
+			input: input,
+			patch: yaml.MustParse(`
+spec:
+  replicas:
+    count: 3
+`),
+			errMsg: "spec.replicas is of type integer, not an object",
+		},
+		"unknown kind": {
+			input: `
+apiVersion: example.com/v1
+kind: Gorilla
+metadata:
+  name: koko
+`,
+			patch: yaml.MustParse(`
+spec:
+  diet: bamboo
+`),
+			errMsg: "no schema for kind Gorilla of example.com/v1",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			f := Filter{
+				Patch:  tc.patch,
+				Strict: true,
+			}
+			if tc.errMsg != "" {
+				_, err := filtertest.RunFilterE(t, tc.input, f)
+				if !assert.Error(t, err) ||
+					!assert.Contains(t, err.Error(), tc.errMsg) {
+					t.FailNow()
+				}
+				return
+			}
+			if !assert.Equal(t,
+				strings.TrimSpace(tc.expected),
+				strings.TrimSpace(
+					filtertest.RunFilter(t, tc.input, f))) {
+				t.FailNow()
+			}
+		})
+	}
+}
@@ -66,7 +66,7 @@ func updateNodeValue(node *yaml.Node, newValue interface{}) {
 }
 
 func (f Filter) setScalar(node *yaml.RNode) error {
-	if !yaml.IsYNodeString(node.YNode()) {
+	if !isString(node.YNode()) {
 		return nil
 	}
 	v := expansion2.Expand(node.YNode().Value, f.MappingFunc)
@@ -77,10 +77,10 @@ func (f Filter) setScalar(node *yaml.RNode) error {
 func (f Filter) setMap(node *yaml.RNode) error {
 	contents := node.YNode().Content
 	for i := 0; i < len(contents); i += 2 {
-		if !yaml.IsYNodeString(contents[i]) {
+		if !isString(contents[i]) {
 			return fmt.Errorf("invalid map key: %s, type: %s", contents[i].Value, contents[i].Tag)
 		}
-		if !yaml.IsYNodeString(contents[i+1]) {
+		if !isString(contents[i+1]) {
 			continue
 		}
 		newValue := expansion2.Expand(contents[i+1].Value, f.MappingFunc)
@@ -91,7 +91,7 @@ func (f Filter) setMap(node *yaml.RNode) error {
 
 func (f Filter) setSeq(node *yaml.RNode) error {
 	for _, item := range node.YNode().Content {
-		if !yaml.IsYNodeString(item) {
+		if !isString(item) {
 			return fmt.Errorf("invalid value type expect a string")
 		}
 		newValue := expansion2.Expand(item.Value, f.MappingFunc)
@@ -99,3 +99,9 @@ func (f Filter) setSeq(node *yaml.RNode) error {
 	}
 	return nil
 }
+
+// isString returns true if the node is a string, tagged
+// as such or, as nodes made by yaml.FieldSetter are, untagged.
+func isString(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == yaml.NodeTagString
+}
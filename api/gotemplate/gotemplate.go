@@ -0,0 +1,98 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gotemplate renders Go templates into resources,
+// with the functions of Sprig, and toYaml and required
+// of Helm, behaving as their namesakes.
+//
+// Sprig's functions whose results depend on more than their
+// arguments aren't available: env and expandenv read the
+// environment, getHostByName the network, now, ago and the
+// date functions the clock, and the rand, uuid, shuffle,
+// gen and encryptAES functions are random.  Builds using
+// them couldn't be cached nor reproduced, and templates
+// could read secrets from the environment.  Templates
+// calling them fail to parse.
+//
+// keys returns the keys sorted, rather than in the random
+// order of Sprig's, so that the output is reproducible.
+// Unlike in Helm, a key missing from the values is an error.
+package gotemplate
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// nonhermetic are the functions of Sprig's hermetic
+// function map that aren't: they read the clock,
+// or are random.
+var nonhermetic = []string{
+	"ago",
+	"shuffle",
+	"genPrivateKey",
+	"genCA",
+	"genSelfSignedCert",
+	"genSignedCert",
+	"encryptAES",
+}
+
+// Render renders the template with the given values as dot.
+// A missing key of the values is an error.
+func Render(name, text string, values interface{}) ([]byte, error) {
+	t, err := template.New(name).
+		Option("missingkey=error").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err = t.Execute(&b, values); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// FuncMap returns the functions templates may call.
+func FuncMap() template.FuncMap {
+	m := sprig.HermeticTxtFuncMap()
+	for _, name := range nonhermetic {
+		delete(m, name)
+	}
+	m["keys"] = keys
+	m["toYaml"] = toYaml
+	m["required"] = required
+	return m
+}
+
+// keys returns the keys of the dicts, sorted,
+// so that the output is reproducible.
+func keys(dicts ...map[string]interface{}) []string {
+	var result []string
+	for _, d := range dicts {
+		for k := range d {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func toYaml(v interface{}) (string, error) {
+	b, err := yaml.Marshal(v)
+	return strings.TrimSuffix(string(b), "\n"), err
+}
+
+// required is v, or an error with the given
+// message if v is nil or an empty string.
+func required(msg string, v interface{}) (interface{}, error) {
+	if s, ok := v.(string); v == nil || ok && s == "" {
+		return nil, errors.New(msg)
+	}
+	return v, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package gotemplate_test
+
+import (
+	"strings"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/gotemplate"
+)
+
+func TestRender(t *testing.T) {
+	values := map[string]interface{}{
+		"name":     "app",
+		"replicas": 3,
+		"empty":    "",
+		"labels":   map[string]interface{}{"tier": "web", "app": "app"},
+		"ports":    []interface{}{80, 443},
+	}
+	testCases := map[string]struct {
+		template string
+		expected string
+		errMsg   string
+	}{
+		"values": {
+			template: "{{ .name }}-{{ .replicas }}",
+			expected: "app-3",
+		},
+		"default": {
+			template: `{{ .empty | default "none" }} {{ .name | default "none" }}`,
+			expected: "none app",
+		},
+		"strings": {
+			template: `{{ .name | upper | quote }} {{ "a-b" | replace "-" "_" }} {{ trimPrefix "x" "xy" }}`,
+			expected: `"APP" a_b y`,
+		},
+		"quote": {
+			template: `{{ quote "a" nil 1 }} {{ squote "a" nil }}`,
+			expected: `"a" "1" 'a'`,
+		},
+		"sprig": {
+			template: `{{ .name | camelcase }} {{ list 3 1 2 | sortAlpha | join "," }} {{ .name | sha1sum | trunc 7 }}`,
+			expected: "App 1,2,3 7d10434",
+		},
+		"env": {
+			template: `{{ env "HOME" }}`,
+			errMsg:   `function "env" not defined`,
+		},
+		"now": {
+			template: `{{ now }}`,
+			errMsg:   `function "now" not defined`,
+		},
+		"random": {
+			template: `{{ list 1 2 | shuffle }}`,
+			errMsg:   `function "shuffle" not defined`,
+		},
+		"toYaml": {
+			template: "labels:{{ .labels | toYaml | nindent 2 }}",
+			expected: "labels:\n  app: app\n  tier: web",
+		},
+		"lists": {
+			template: `{{ join "," .ports }} {{ keys .labels | join "," }} {{ keys .labels (dict "b" 1) | join "," }} {{ list 1 2 | toJson }}`,
+			expected: "80,443 app,tier app,b,tier [1,2]",
+		},
+		"dict": {
+			template: `{{ $d := dict "a" 1 }}{{ hasKey $d "a" }} {{ hasKey $d "b" }}`,
+			expected: "true false",
+		},
+		"encodings": {
+			template: `{{ "hi" | b64enc }} {{ "aGk=" | b64dec }}`,
+			expected: "aGk= hi",
+		},
+		"required": {
+			template: `{{ required "empty is required" .empty }}`,
+			errMsg:   "empty is required",
+		},
+		"missing": {
+			template: "{{ .nope }}",
+			errMsg:   `map has no entry for key "nope"`,
+		},
+		"parse": {
+			template: "{{ .name ",
+			errMsg:   "unclosed action",
+		},
+	}
+	for n, tc := range testCases {
+		actual, err := Render(n, tc.template, values)
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if string(actual) != tc.expected {
+			t.Errorf("%s: expected %q, got %q", n, tc.expected, actual)
+		}
+	}
+}
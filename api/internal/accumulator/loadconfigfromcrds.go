@@ -10,6 +10,7 @@ import (
 	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
 	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/util"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
@@ -26,14 +27,10 @@ func LoadConfigFromCRDs(
 	ldr ifc.Loader, paths []string) (*builtinconfig.TransformerConfig, error) {
 	tc := builtinconfig.MakeEmptyConfig()
 	for _, path := range paths {
-		content, err := ldr.Load(path)
+		m, err := loadNameToApiMap(ldr, path)
 		if err != nil {
 			return nil, err
 		}
-		m, err := makeNameToApiMap(content)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to parse open API definition from '%s'", path)
-		}
 		otherTc, err := makeConfigFromApiMap(m)
 		if err != nil {
 			return nil, err
@@ -46,6 +43,91 @@ func LoadConfigFromCRDs(
 	return tc, nil
 }
 
+// LoadConfigFromSchema parses OpenAPI definitions, such as
+// those plugins declare, into a TransformerConfig.
+func LoadConfigFromSchema(
+	defs spec.Definitions) (*builtinconfig.TransformerConfig, error) {
+	m := nameToApiMap{}
+	for name, d := range defs {
+		m[name] = common.OpenAPIDefinition{Schema: d}
+	}
+	return makeConfigFromApiMap(m)
+}
+
+// LoadSchemasFromCRDs parses CRD schemas from paths into
+// definitions for the global schema, named and referring to
+// one another as the built-in definitions do.  Kinds with
+// no "x-kubernetes-group-version-kind" extension are given
+// one naming the kind alone, as their group and version
+// are unknown.
+func LoadSchemasFromCRDs(
+	ldr ifc.Loader, paths []string) (spec.Definitions, error) {
+	defs := spec.Definitions{}
+	for _, path := range paths {
+		m, err := loadNameToApiMap(ldr, path)
+		if err != nil {
+			return nil, err
+		}
+		for name, api := range m {
+			s := api.Schema
+			rewriteRefs(&s)
+			_, found := s.Extensions[xGvk]
+			if !found && looksLikeAk8sType(s.SchemaProps.Properties) {
+				s.AddExtension(xGvk, []interface{}{map[string]interface{}{
+					"group":   "",
+					"version": "",
+					"kind":    makeGvkFromTypeName(name).Kind,
+				}})
+			}
+			defs[util.ToRESTFriendlyName(name)] = s
+		}
+	}
+	return defs, nil
+}
+
+// rewriteRefs rewrites the bare type names the schema
+// refers to into references to definitions named as
+// the built-in ones are, e.g. k8s.io/api/core/v1.Pod
+// into #/definitions/io.k8s.api.core.v1.Pod.
+func rewriteRefs(s *spec.Schema) {
+	if r := s.Ref.String(); r != "" && !strings.HasPrefix(r, "#") {
+		s.Ref = spec.MustCreateRef(
+			definitionsRef + util.ToRESTFriendlyName(r))
+	}
+	for k, p := range s.Properties {
+		rewriteRefs(&p)
+		s.Properties[k] = p
+	}
+	if s.Items != nil {
+		if s.Items.Schema != nil {
+			rewriteRefs(s.Items.Schema)
+		}
+		for i := range s.Items.Schemas {
+			rewriteRefs(&s.Items.Schemas[i])
+		}
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		rewriteRefs(s.AdditionalProperties.Schema)
+	}
+	for _, ss := range [][]spec.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range ss {
+			rewriteRefs(&ss[i])
+		}
+	}
+}
+
+func loadNameToApiMap(ldr ifc.Loader, path string) (nameToApiMap, error) {
+	content, err := ldr.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := makeNameToApiMap(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse open API definition from '%s'", path)
+	}
+	return m, nil
+}
+
 func makeNameToApiMap(content []byte) (result nameToApiMap, err error) {
 	if content[0] == '{' {
 		err = json.Unmarshal(content, &result)
@@ -63,7 +145,7 @@ func makeConfigFromApiMap(m nameToApiMap) (*builtinconfig.TransformerConfig, err
 		}
 		tc := builtinconfig.MakeEmptyConfig()
 		err := loadCrdIntoConfig(
-			tc, makeGvkFromTypeName(name), m, name, []string{})
+			tc, makeGvk(name, api.Schema), m, name, []string{})
 		if err != nil {
 			return result, err
 		}
@@ -75,6 +157,19 @@ func makeConfigFromApiMap(m nameToApiMap) (*builtinconfig.TransformerConfig, err
 	return result, nil
 }
 
+// makeGvk returns the gvk of the type, per its
+// "x-kubernetes-group-version-kind" extension, if any.
+func makeGvk(n string, s spec.Schema) resid.Gvk {
+	var gvks []resid.Gvk
+	if ext, ok := s.Extensions[xGvk]; ok {
+		b, err := json.Marshal(ext)
+		if err == nil && json.Unmarshal(b, &gvks) == nil && len(gvks) == 1 {
+			return gvks[0]
+		}
+	}
+	return makeGvkFromTypeName(n)
+}
+
 // TODO: Get Group and Version for CRD from the
 // openAPI definition once
 // "x-kubernetes-group-version-kind" is available in CRD
@@ -98,6 +193,9 @@ func looksLikeAk8sType(properties myProperties) bool {
 }
 
 const (
+	// "x-kubernetes-group-version-kind": [{group: <group>, version: <version>, kind: <kind>}]
+	xGvk = "x-kubernetes-group-version-kind"
+
 	// "x-kubernetes-annotation": ""
 	xAnnotation = "x-kubernetes-annotation"
 
@@ -118,6 +216,11 @@ const (
 	xNameKey = "x-kubernetes-object-ref-name-key"
 )
 
+// definitionsRef prefixes references to the definitions
+// of an OpenAPI document, as opposed to the bare type names
+// CRD files refer to.
+const definitionsRef = "#/definitions/"
+
 // loadCrdIntoConfig loads a CRD spec into a TransformerConfig
 func loadCrdIntoConfig(
 	theConfig *builtinconfig.TransformerConfig, theGvk resid.Gvk, theMap nameToApiMap,
@@ -173,7 +276,8 @@ func loadCrdIntoConfig(
 		if property.Ref.GetURL() != nil {
 			loadCrdIntoConfig(
 				theConfig, theGvk, theMap,
-				property.Ref.String(), append(path, propName))
+				strings.TrimPrefix(property.Ref.String(), definitionsRef),
+				append(path, propName))
 		}
 	}
 	return nil
@@ -4,9 +4,11 @@
 package accumulator_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
+	"github.com/go-openapi/spec"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/loader"
 
@@ -179,3 +181,57 @@ func TestLoadCRDs(t *testing.T) {
 		t.Fatalf("expected\n %v\n but got\n %v\n", expectedTc, actualTc)
 	}
 }
+
+func TestLoadConfigFromSchema(t *testing.T) {
+	var s spec.Schema
+	err := json.Unmarshal([]byte(`
+{
+  "definitions": {
+    "com.example.v1.Route": {
+      "x-kubernetes-group-version-kind": [
+        {"group": "example.com", "version": "v1", "kind": "Route"}
+      ],
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"type": "object"},
+        "spec": {"$ref": "#/definitions/com.example.v1.RouteSpec"}
+      }
+    },
+    "com.example.v1.RouteSpec": {
+      "properties": {
+        "configMapRef": {
+          "x-kubernetes-object-ref-api-version": "v1",
+          "x-kubernetes-object-ref-kind": "ConfigMap"
+        }
+      }
+    }
+  }
+}`), &s)
+	if err != nil {
+		t.Fatalf("unexpected error:%v", err)
+	}
+	expectedTc := &builtinconfig.TransformerConfig{
+		NameReference: []builtinconfig.NameBackReferences{
+			{
+				Gvk: resid.Gvk{Kind: "ConfigMap", Version: "v1"},
+				FieldSpecs: []types.FieldSpec{
+					{
+						CreateIfNotPresent: false,
+						Gvk: resid.Gvk{
+							Group: "example.com", Version: "v1", Kind: "Route"},
+						Path: "spec/configMapRef/name",
+					},
+				},
+			},
+		},
+	}
+
+	actualTc, err := LoadConfigFromSchema(s.Definitions)
+	if err != nil {
+		t.Fatalf("unexpected error:%v", err)
+	}
+	if !reflect.DeepEqual(actualTc, expectedTc) {
+		t.Fatalf("expected\n %v\n but got\n %v\n", expectedTc, actualTc)
+	}
+}
@@ -21,6 +21,9 @@ type ResAccumulator struct {
 	resMap  resmap.ResMap
 	tConfig *builtinconfig.TransformerConfig
 	varSet  types.VarSet
+	// validators are run once all the
+	// resources are accumulated and customized.
+	validators []resmap.Transformer
 }
 
 func MakeEmptyAccumulator() *ResAccumulator {
@@ -31,6 +34,19 @@ func MakeEmptyAccumulator() *ResAccumulator {
 	return ra
 }
 
+// DeepCopy returns a copy of the accumulator whose
+// resources and vars can change independently.
+// The transformer config and validators, which
+// are only ever replaced or appended, are shared.
+func (ra *ResAccumulator) DeepCopy() *ResAccumulator {
+	return &ResAccumulator{
+		resMap:     ra.resMap.DeepCopy(),
+		tConfig:    ra.tConfig,
+		varSet:     ra.varSet.Copy(),
+		validators: append([]resmap.Transformer(nil), ra.validators...),
+	}
+}
+
 // ResMap returns a copy of the internal resMap.
 func (ra *ResAccumulator) ResMap() resmap.ResMap {
 	return ra.resMap.ShallowCopy()
@@ -61,6 +77,17 @@ func (ra *ResAccumulator) GetTransformerConfig() *builtinconfig.TransformerConfi
 	return ra.tConfig
 }
 
+// AddValidators adds validators to run on the
+// customized resources.
+func (ra *ResAccumulator) AddValidators(validators ...resmap.Transformer) {
+	ra.validators = append(ra.validators, validators...)
+}
+
+// Validators returns the validators added so far.
+func (ra *ResAccumulator) Validators() []resmap.Transformer {
+	return ra.validators
+}
+
 func (ra *ResAccumulator) MergeVars(incoming []types.Var) error {
 	for _, v := range incoming {
 		targetId := resid.NewResIdWithNamespace(v.ObjRef.GVK(), v.ObjRef.Name, v.ObjRef.Namespace)
@@ -93,6 +120,7 @@ func (ra *ResAccumulator) MergeAccumulator(other *ResAccumulator) (err error) {
 	if err != nil {
 		return err
 	}
+	ra.AddValidators(other.validators...)
 	return ra.varSet.MergeSet(other.varSet)
 }
 
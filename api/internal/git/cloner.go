@@ -22,6 +22,19 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 	if err != nil {
 		return errors.Wrap(err, "no 'git' program on path")
 	}
+	return clone(repoSpec, func(dir string, args ...string) ([]byte, error) {
+		cmd := exec.Command(gitProgram, args...)
+		cmd.Dir = dir
+		return cmd.CombinedOutput()
+	})
+}
+
+// gitRunner runs git with the arguments in the directory.
+type gitRunner func(dir string, args ...string) ([]byte, error)
+
+// clone clones the repo into a new temporary directory.
+func clone(repoSpec *RepoSpec, git gitRunner) error {
+	var err error
 	repoSpec.Dir, err = filesys.NewTmpConfirmedDir()
 	if err != nil {
 		return err
@@ -30,13 +43,12 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 	if repoSpec.Ref == "" {
 		repoSpec.Ref = "master"
 	}
-	cmd := exec.Command(
-		gitProgram,
+	out, err := git(
+		repoSpec.Dir.String(),
 		"clone",
 		"--depth=1",
 		repoSpec.CloneSpec(),
-		repoSpec.Dir.String())
-	out, err := cmd.CombinedOutput()
+		".")
 	if err != nil {
 		log.Printf("Error cloning git repo: %s", out)
 		return errors.Wrapf(
@@ -45,38 +57,32 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 			repoSpec.CloneSpec(), repoSpec.Dir.String())
 	}
 
-	cmd = exec.Command(
-		gitProgram,
+	out, err = git(
+		repoSpec.Dir.String(),
 		"fetch",
 		"--depth=1",
 		"origin",
 		repoSpec.Ref)
-	cmd.Dir = repoSpec.Dir.String()
-	out, err = cmd.CombinedOutput()
 	if err != nil {
 		log.Printf("Error fetching ref: %s", out)
 		return errors.Wrapf(err, "trouble fetching %s", repoSpec.Ref)
 	}
 
-	cmd = exec.Command(
-		gitProgram,
+	out, err = git(
+		repoSpec.Dir.String(),
 		"checkout",
 		"FETCH_HEAD")
-	cmd.Dir = repoSpec.Dir.String()
-	out, err = cmd.CombinedOutput()
 	if err != nil {
 		log.Printf("Error checking out ref: %s", out)
 		return errors.Wrapf(err, "trouble checking out %s", repoSpec.Ref)
 	}
 
-	cmd = exec.Command(
-		gitProgram,
+	out, err = git(
+		repoSpec.Dir.String(),
 		"submodule",
 		"update",
 		"--init",
 		"--recursive")
-	cmd.Dir = repoSpec.Dir.String()
-	out, err = cmd.CombinedOutput()
 	if err != nil {
 		log.Printf("Error fetching submodules: %s", out)
 		return errors.Wrapf(err, "trouble fetching submodules for %s", repoSpec.CloneSpec())
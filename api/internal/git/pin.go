@@ -0,0 +1,92 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultRef is the ref cloned when a url has none.
+const defaultRef = "master"
+
+var commitRegex = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// RefLister returns the refs of the repository named by
+// a clone spec that match the patterns, in the format
+// of "git ls-remote".
+type RefLister func(cloneSpec string, patterns ...string) (string, error)
+
+// RefListerUsingGitExec lists refs with a local git install.
+func RefListerUsingGitExec(cloneSpec string, patterns ...string) (string, error) {
+	gitProgram, err := exec.LookPath("git")
+	if err != nil {
+		return "", errors.Wrap(err, "no 'git' program on path")
+	}
+	out, err := exec.Command(
+		gitProgram, append([]string{"ls-remote", cloneSpec}, patterns...)...).CombinedOutput()
+	if err != nil {
+		return "", errors.Wrapf(
+			err, "trouble listing refs of git repo %s: %s", cloneSpec, out)
+	}
+	return string(out), nil
+}
+
+// ResolveRef returns the commit named by the ref of the spec,
+// and whether the ref is a tag.  A ref that is a commit
+// already is returned as is, since only branches and tags
+// can be listed remotely.  As when fetching, a tag takes
+// precedence over a branch of the same name.
+func (x *RepoSpec) ResolveRef(list RefLister) (commit string, isTag bool, err error) {
+	ref := x.Ref
+	if commitRegex.MatchString(ref) {
+		return ref, false, nil
+	}
+	if ref == "" {
+		ref = defaultRef
+	}
+	out, err := list(x.CloneSpec(), ref, ref+"^{}")
+	if err != nil {
+		return "", false, err
+	}
+	var branch, tag, peeled string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[1] {
+		case "refs/heads/" + ref:
+			branch = fields[0]
+		case "refs/tags/" + ref:
+			tag = fields[0]
+		case "refs/tags/" + ref + "^{}":
+			// The commit an annotated tag points to.
+			peeled = fields[0]
+		}
+	}
+	switch {
+	case peeled != "":
+		return peeled, true, nil
+	case tag != "":
+		return tag, true, nil
+	case branch != "":
+		return branch, false, nil
+	}
+	return "", false, fmt.Errorf(
+		"no branch or tag %s in git repo %s", ref, x.CloneSpec())
+}
+
+// RawWithRef returns the raw spec with its ref replaced.
+func (x *RepoSpec) RawWithRef(ref string) string {
+	r := regexp.MustCompile(refQueryRegex)
+	if j := r.FindStringIndex(x.raw); len(j) > 0 {
+		return x.raw[:j[1]] + ref
+	}
+	return x.raw + refQuery + ref
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// sandboxDir is where the clone is mounted in the sandbox.
+const sandboxDir = "/repo"
+
+// SandboxedCloner returns a cloner that runs git in a
+// container of the given image, which must have git as
+// its entrypoint.  The container sees only the directory
+// of the clone, runs with the caller's uid and no
+// capabilities, and has a read-only root filesystem.
+func SandboxedCloner(image string) Cloner {
+	return func(repoSpec *RepoSpec) error {
+		dockerProgram, err := exec.LookPath("docker")
+		if err != nil {
+			return errors.Wrap(
+				err, "no 'docker' program on path, needed to clone git repos in a sandbox")
+		}
+		return clone(repoSpec, func(dir string, args ...string) ([]byte, error) {
+			//nolint:gosec
+			return exec.Command(
+				dockerProgram, sandboxArgs(image, dir, os.Getuid(), os.Getgid(), args)...).
+				CombinedOutput()
+		})
+	}
+}
+
+// sandboxArgs returns the arguments of "docker"
+// running git with the args in dir.
+func sandboxArgs(image, dir string, uid, gid int, args []string) []string {
+	result := []string{
+		"run", "--rm",
+		"--cap-drop=ALL",
+		"--security-opt=no-new-privileges",
+		"--read-only",
+		"--tmpfs", "/tmp",
+		"--env", "HOME=/tmp",
+		"--mount", fmt.Sprintf("type=bind,src=%s,dst=%s", dir, sandboxDir),
+		"--workdir", sandboxDir,
+	}
+	// There are no uids on windows.
+	if uid >= 0 {
+		result = append(result, "--user", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	return append(append(result, image), args...)
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSandboxArgs(t *testing.T) {
+	expected := []string{
+		"run", "--rm",
+		"--cap-drop=ALL",
+		"--security-opt=no-new-privileges",
+		"--read-only",
+		"--tmpfs", "/tmp",
+		"--env", "HOME=/tmp",
+		"--mount", "type=bind,src=/tmp/kust-123,dst=/repo",
+		"--workdir", "/repo",
+		"--user", "1000:100",
+		"alpine/git", "fetch", "--depth=1", "origin", "v1"}
+	actual := sandboxArgs(
+		"alpine/git", "/tmp/kust-123", 1000, 100,
+		[]string{"fetch", "--depth=1", "origin", "v1"})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n%v\ngot\n%v", expected, actual)
+	}
+	actual = sandboxArgs("alpine/git", "/tmp/kust-123", -1, -1, []string{"checkout"})
+	for _, a := range actual {
+		if a == "--user" {
+			t.Errorf("unexpected --user in %v", actual)
+		}
+	}
+}
@@ -4,11 +4,22 @@
 package merge
 
 import (
+	"fmt"
+
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/kustomize/kyaml/yaml/merge2"
 )
 
 // Merginator implements resmap.Merginator using kyaml libs.
+//
+// Patches are merged with merge2, which merges the lists the
+// schema, or the OpenAPI extensions of a CRD, tells are
+// associative, and replaces the others, much as a strategic
+// merge patch does for built-in kinds and a JSON merge patch
+// does for other ones.
 type Merginator struct {
 }
 
@@ -20,6 +31,217 @@ func NewMerginator(_ *resource.Factory) *Merginator {
 
 // Merge implements resmap.Merginator
 func (m Merginator) Merge(
-	resources []*resource.Resource) (resmap.ResMap, error) {
-	panic("TODO(#Merginator): implement Merge")
+	patches []*resource.Resource) (resmap.ResMap, error) {
+	rc := resmap.New()
+	for ix, patch := range patches {
+		id := patch.OrgId()
+		existing := rc.GetMatchingResourcesByOriginalId(id.Equals)
+		if len(existing) == 0 {
+			rc.Append(patch)
+			continue
+		}
+		if len(existing) > 1 {
+			return nil, fmt.Errorf("self conflict in patches")
+		}
+		p1, err := heldRNode(existing[0])
+		if err != nil {
+			return nil, err
+		}
+		p2, err := heldRNode(patch)
+		if err != nil {
+			return nil, err
+		}
+		if hasConflict(p1.YNode(), p2.YNode(), schemaOf(p1)) {
+			conflicting := findConflict(ix, patches)
+			if conflicting == nil {
+				conflicting = existing[0]
+			}
+			return nil, fmt.Errorf(
+				"conflict between %#v and %#v",
+				conflicting.PeekMap(), patch.PeekMap())
+		}
+		merged, err := mergePatches(existing[0], patch)
+		if err != nil {
+			return nil, err
+		}
+		rc.Replace(merged)
+	}
+	return rc, nil
+}
+
+// heldRNode returns the node holding a copy of the
+// data of the resource.
+func heldRNode(r *resource.Resource) (*yaml.RNode, error) {
+	node := r.PeekRNode()
+	if node == nil {
+		return nil, fmt.Errorf("expected %s held in a yaml.RNode", r.OrgId())
+	}
+	return node.Copy(), nil
+}
+
+// schemaOf returns the schema of the kind of
+// the node, or nil if unknown.
+func schemaOf(node *yaml.RNode) *openapi.ResourceSchema {
+	meta, err := node.GetMeta()
+	if err != nil {
+		return nil
+	}
+	return openapi.SchemaForResourceType(meta.TypeMeta)
+}
+
+// findConflict returns the patch of the same object as the
+// conflicting one that conflicts with it.
+func findConflict(
+	conflictingPatchIdx int, patches []*resource.Resource) *resource.Resource {
+	p1, err := heldRNode(patches[conflictingPatchIdx])
+	if err != nil {
+		return nil
+	}
+	for i, patch := range patches {
+		if i == conflictingPatchIdx {
+			continue
+		}
+		if !patches[conflictingPatchIdx].OrgId().Equals(patch.OrgId()) {
+			continue
+		}
+		p2, err := heldRNode(patch)
+		if err != nil {
+			continue
+		}
+		if hasConflict(p2.YNode(), p1.YNode(), schemaOf(p1)) {
+			return patch
+		}
+	}
+	return nil
+}
+
+// hasConflict returns true if the nodes set a field to
+// different values, matching the elements of associative
+// lists by their key, and comparing other lists whole.
+func hasConflict(n1, n2 *yaml.Node, s *openapi.ResourceSchema) bool {
+	switch {
+	case n1.Kind != n2.Kind:
+		return true
+	case n1.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(n1.Content); i += 2 {
+			k := n1.Content[i].Value
+			for j := 0; j+1 < len(n2.Content); j += 2 {
+				if n2.Content[j].Value == k &&
+					hasConflict(n1.Content[i+1], n2.Content[j+1], fieldSchema(s, k)) {
+					return true
+				}
+			}
+		}
+		return false
+	case n1.Kind == yaml.SequenceNode:
+		key := associativeKey(yaml.NewRNode(n1), s)
+		if key == "" {
+			return !equal(n1, n2)
+		}
+		for _, e1 := range n1.Content {
+			v1 := yaml.NewRNode(e1).Field(key)
+			for _, e2 := range n2.Content {
+				v2 := yaml.NewRNode(e2).Field(key)
+				if v1 != nil && v2 != nil &&
+					v1.Value.YNode().Value == v2.Value.YNode().Value &&
+					hasConflict(e1, e2, elementSchema(s)) {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		return !equal(n1, n2)
+	}
+}
+
+func fieldSchema(s *openapi.ResourceSchema, field string) *openapi.ResourceSchema {
+	if s == nil {
+		return nil
+	}
+	return s.Field(field)
+}
+
+func elementSchema(s *openapi.ResourceSchema) *openapi.ResourceSchema {
+	if s == nil {
+		return nil
+	}
+	return s.Elements()
+}
+
+// associativeKey returns the key of the elements of
+// the list, or "" if it isn't associative.
+func associativeKey(list *yaml.RNode, s *openapi.ResourceSchema) string {
+	if s != nil {
+		if _, key := s.PatchStrategyAndKey(); key != "" {
+			return key
+		}
+	}
+	if list.IsAssociative() {
+		return list.GetAssociativeKey()
+	}
+	return ""
+}
+
+// equal returns true if the nodes have the same value.
+func equal(n1, n2 *yaml.Node) bool {
+	if n1.Kind == yaml.ScalarNode {
+		return n1.Value == n2.Value
+	}
+	s1, err1 := yaml.NewRNode(n1).MarshalJSON()
+	s2, err2 := yaml.NewRNode(n2).MarshalJSON()
+	return err1 == nil && err2 == nil && string(s1) == string(s2)
+}
+
+// mergePatches merges patch2 into patch1.  A patch deleting
+// something is merged into rather than merged, so that what
+// it deletes is still deleted.
+func mergePatches(
+	patch1, patch2 *resource.Resource) (*resource.Resource, error) {
+	p1, err := heldRNode(patch1)
+	if err != nil {
+		return nil, err
+	}
+	p2, err := heldRNode(patch2)
+	if err != nil {
+		return nil, err
+	}
+	if hasDeleteDirectiveMarker(p2.YNode()) {
+		if hasDeleteDirectiveMarker(p1.YNode()) {
+			return nil, fmt.Errorf(
+				"cannot merge patches both containing '$patch: delete' directives")
+		}
+		patch1, p1, p2 = patch2, p2, p1
+	}
+	merged, err := merge2.Merge(p2, p1)
+	if err != nil {
+		return nil, err
+	}
+	result := patch1.DeepCopy()
+	result.HeldRNode().SetYNode(merged.YNode())
+	return result, nil
+}
+
+func hasDeleteDirectiveMarker(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == "$patch" && n.Content[i+1].Value == "delete" {
+				return true
+			}
+			if hasDeleteDirectiveMarker(n.Content[i+1]) {
+				return true
+			}
+		}
+	case yaml.SequenceNode:
+		for _, e := range n.Content {
+			if e.Kind != yaml.MappingNode {
+				break
+			}
+			if hasDeleteDirectiveMarker(e) {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -2,3 +2,135 @@
 // SPDX-License-Identifier: Apache-2.0
 
 package merge_test
+
+import (
+	"strings"
+	"testing"
+
+	. "sigs.k8s.io/kustomize/api/internal/merge"
+	"sigs.k8s.io/kustomize/api/internal/wrappy"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+func TestMerge(t *testing.T) {
+	rf := resource.NewFactory(&wrappy.WNodeFactory{})
+	testCases := map[string]struct {
+		patches  string
+		expected string
+		errMsg   string
+	}{
+		"merge containers by name": {
+			patches: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  template:
+    spec:
+      containers:
+      - name: main
+        image: nginx
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: envoy
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  template:
+    spec:
+      containers:
+      - name: main
+        image: nginx
+      - name: sidecar
+        image: envoy
+  replicas: 3
+`,
+		},
+		"conflict": {
+			patches: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  replicas: 2
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  replicas: 3
+`,
+			errMsg: "conflict between",
+		},
+		"delete merged into": {
+			patches: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  replicas: 2
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        $patch: delete
+`,
+			expected: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        $patch: delete
+  replicas: 2
+`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			patches, err := rf.SliceFromBytes([]byte(tc.patches))
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			m, err := NewMerginator(rf).Merge(patches)
+			if tc.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected err containing %q, got %v", tc.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			actual, err := m.AsYaml()
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if string(actual) != tc.expected {
+				t.Fatalf("expected\n%s\ngot\n%s", tc.expected, actual)
+			}
+		})
+	}
+}
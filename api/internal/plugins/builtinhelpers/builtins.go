@@ -15,6 +15,7 @@ const (
 	Unknown BuiltinPluginType = iota
 	AnnotationsTransformer
 	ConfigMapGenerator
+	GoTemplateGenerator
 	HashTransformer
 	ImageTagTransformer
 	LabelTransformer
@@ -55,8 +56,9 @@ func GetBuiltinPluginType(n string) BuiltinPluginType {
 }
 
 var GeneratorFactories = map[BuiltinPluginType]func() resmap.GeneratorPlugin{
-	ConfigMapGenerator: builtins.NewConfigMapGeneratorPlugin,
-	SecretGenerator:    builtins.NewSecretGeneratorPlugin,
+	ConfigMapGenerator:  builtins.NewConfigMapGeneratorPlugin,
+	GoTemplateGenerator: builtins.NewGoTemplateGeneratorPlugin,
+	SecretGenerator:     builtins.NewSecretGeneratorPlugin,
 }
 
 var TransformerFactories = map[BuiltinPluginType]func() resmap.TransformerPlugin{
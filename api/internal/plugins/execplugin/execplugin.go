@@ -14,8 +14,11 @@ import (
 	"github.com/google/shlex"
 
 	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/internal/plugins/limits"
+	"sigs.k8s.io/kustomize/api/internal/plugins/sandbox"
 	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/yaml"
 )
 
@@ -39,6 +42,12 @@ type ExecPlugin struct {
 
 	// PluginHelpers
 	h *resmap.PluginHelpers
+
+	// Restrictions on the process running the plugin.
+	sandbox types.ExecSandbox
+
+	// Bounds on the process running the plugin.
+	limits types.PluginLimits
 }
 
 func NewExecPlugin(p string) *ExecPlugin {
@@ -56,6 +65,21 @@ func (p *ExecPlugin) ErrIfNotExecutable() error {
 	return nil
 }
 
+// SetSandbox restricts the process running the plugin.
+func (p *ExecPlugin) SetSandbox(s types.ExecSandbox) {
+	p.sandbox = s
+}
+
+// SetLimits bounds the process running the plugin.
+func (p *ExecPlugin) SetLimits(l types.PluginLimits) {
+	p.limits = l
+}
+
+// start starts the process running the plugin in its sandbox.
+func (p *ExecPlugin) start(cmd *exec.Cmd) error {
+	return sandbox.Start(p.sandbox, cmd)
+}
+
 func (p *ExecPlugin) Path() string {
 	return p.path
 }
@@ -135,10 +159,19 @@ func (p *ExecPlugin) Transform(rm resmap.ResMap) error {
 	return utils.UpdateResMapValues(p.path, p.h, output, rm)
 }
 
-// invokePlugin writes plugin config to a temp file, then
+// invokePlugin runs the plugin per the protocol
+// version it speaks, returning its output.
+func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
+	if p.protocolVersion() == protocolV2 {
+		return p.invokePluginV2(input)
+	}
+	return p.invokePluginV1(input)
+}
+
+// invokePluginV1 writes plugin config to a temp file, then
 // passes the full temp file path as the first arg to a process
 // running the plugin binary.  Process output is returned.
-func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
+func (p *ExecPlugin) invokePluginV1(input []byte) ([]byte, error) {
 	f, err := ioutil.TempFile("", tmpConfigFilePrefix)
 	if err != nil {
 		return nil, errors.Wrap(
@@ -163,7 +196,7 @@ func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 	if _, err := os.Stat(p.h.Loader().Root()); err == nil {
 		cmd.Dir = p.h.Loader().Root()
 	}
-	result, err := cmd.Output()
+	result, err := limits.Output(p.limits, p.start, cmd)
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "failure in plugin configured via %s; %v",
@@ -4,6 +4,10 @@
 package execplugin_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -16,6 +20,7 @@ import (
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
 	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 func TestExecPluginConfig(t *testing.T) {
@@ -89,3 +94,272 @@ metadata:
 		t.Fatalf("unexpected arg array: %#v", p.Args())
 	}
 }
+
+// v2Plugin speaks protocol version 2, generating a
+// ConfigMap holding its config, unless told to fail.
+const v2Plugin = `#!/bin/sh
+input=$(cat)
+case "$input" in
+*"fail: true"*)
+  cat <<EOF
+apiVersion: config.kubernetes.io/v1alpha1
+kind: ResourceList
+items: []
+results:
+  name: test
+  items:
+  - message: too many replicas
+    severity: error
+    resourceRef: {kind: Deployment, metadata: {name: app}}
+    field: {path: spec.replicas}
+  - message: just saying
+    severity: warning
+EOF
+  exit 1;;
+esac
+cat <<EOF
+apiVersion: config.kubernetes.io/v1alpha1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: generated
+  data:
+    version: "$KUSTOMIZE_PLUGIN_PROTOCOL_VERSION"
+    hasConfig: "$(echo "$input" | grep -c '^functionConfig:')"
+results:
+  name: test
+  items:
+  - message: just saying
+    severity: warning
+EOF
+`
+
+// v1Plugin speaks protocol version 1, generating a
+// ConfigMap holding the number of its arguments.
+const v1Plugin = `#!/bin/sh
+cat <<EOF
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: generated
+data:
+  args: "$#"
+EOF
+`
+
+// pluginPath returns the path of the plugin of the
+// script, named per the protocol version it speaks.
+func pluginPath(dir, name, script string) string {
+	if script == v2Plugin {
+		name += konfig.ExecPluginV2Suffix
+	}
+	return filepath.Join(dir, name)
+}
+
+func TestExecPluginProtocols(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-execplugin-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ldr, err := fLdr.NewLoader(
+		fLdr.RestrictionRootOnly, filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			kunstruct.NewKunstructuredFactoryImpl()), nil)
+	h := resmap.NewPluginHelpers(ldr, valtest_test.MakeFakeValidator(), rf)
+	testCases := map[string]struct {
+		script   string
+		config   string
+		expected string
+		errMsg   string
+	}{
+		"v1": {
+			script: v1Plugin,
+			expected: `apiVersion: v1
+data:
+  args: "1"
+kind: ConfigMap
+metadata:
+  name: generated
+`,
+		},
+		"v2": {
+			script: v2Plugin,
+			expected: `apiVersion: v1
+data:
+  hasConfig: "1"
+  version: "2"
+kind: ConfigMap
+metadata:
+  name: generated
+`,
+		},
+		"v2Error": {
+			script: v2Plugin,
+			config: "fail: true\n",
+			errMsg: "reported 1 error(s):\n  " +
+				"error: too many replicas (Deployment app, field spec.replicas)",
+		},
+	}
+	for n, tc := range testCases {
+		path := pluginPath(dir, n, tc.script)
+		if err = ioutil.WriteFile(path, []byte(tc.script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		p := NewExecPlugin(path)
+		err = p.Config(h, []byte(
+			"apiVersion: someteam.example.com/v1\nkind: Gen\nmetadata:\n  name: gen\n"+tc.config))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		m, err := p.Generate()
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		actual, err := m.AsYaml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != tc.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", n, tc.expected, actual)
+		}
+	}
+}
+
+// A plugin speaking version 1 runs once, as it did before
+// version 2, rather than being probed for its version.
+func TestExecPluginV1RunsOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-execplugin-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ldr, err := fLdr.NewLoader(
+		fLdr.RestrictionRootOnly, filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			kunstruct.NewKunstructuredFactoryImpl()), nil)
+	log := filepath.Join(dir, "argv.log")
+	path := filepath.Join(dir, "Logger")
+	err = ioutil.WriteFile(path, []byte(`#!/bin/sh
+echo "$#" >> `+log+`
+`+strings.TrimPrefix(v1Plugin, "#!/bin/sh\n")), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewExecPlugin(path)
+	err = p.Config(
+		resmap.NewPluginHelpers(ldr, valtest_test.MakeFakeValidator(), rf),
+		[]byte("apiVersion: someteam.example.com/v1\nkind: Logger\nmetadata:\n  name: gen\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = p.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Schema() != nil {
+		t.Fatalf("expected no schema")
+	}
+	runs, err := ioutil.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(runs) != "1\n" {
+		t.Fatalf("expected one run with the config file argument, got %q", runs)
+	}
+}
+
+// v1Failer fails, as a version 1 validator does.
+const v1Failer = `#!/bin/sh
+echo too many replicas >&2
+exit 1
+`
+
+func TestExecPluginValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-execplugin-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ldr, err := fLdr.NewLoader(
+		fLdr.RestrictionRootOnly, filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			kunstruct.NewKunstructuredFactoryImpl()), nil)
+	h := resmap.NewPluginHelpers(ldr, valtest_test.MakeFakeValidator(), rf)
+	testCases := map[string]struct {
+		script   string
+		config   string
+		expected []types.Finding
+	}{
+		"v1": {
+			script: v1Plugin,
+		},
+		"v1Failure": {
+			script: v1Failer,
+			expected: []types.Finding{{
+				Severity: types.SeverityError,
+				Message:  "failure in plugin " + filepath.Join(dir, "v1Failure") + ": exit status 1",
+			}},
+		},
+		"v2": {
+			script: v2Plugin,
+			expected: []types.Finding{{
+				Severity: types.SeverityWarning,
+				Message:  "just saying",
+			}},
+		},
+		"v2Error": {
+			script: v2Plugin,
+			config: "fail: true\n",
+			expected: []types.Finding{{
+				Severity: types.SeverityError,
+				Message:  "too many replicas",
+				Resource: "Deployment app",
+				Field:    "spec.replicas",
+			}, {
+				Severity: types.SeverityWarning,
+				Message:  "just saying",
+			}},
+		},
+	}
+	for n, tc := range testCases {
+		path := pluginPath(dir, n, tc.script)
+		if err = ioutil.WriteFile(path, []byte(tc.script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		p := NewExecPlugin(path)
+		err = p.Config(h, []byte(
+			"apiVersion: someteam.example.com/v1\nkind: Val\nmetadata:\n  name: val\n"+tc.config))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		findings, err := p.Validate(resmap.New())
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if !reflect.DeepEqual(findings, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", n, tc.expected, findings)
+		}
+	}
+}
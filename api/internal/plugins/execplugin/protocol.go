@@ -0,0 +1,130 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package execplugin
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/internal/plugins/limits"
+	"sigs.k8s.io/kustomize/api/internal/plugins/sandbox"
+	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+)
+
+// Exec plugins speak one of two protocols.
+//
+// In version 1, the plugin is given the path of a file
+// holding its config as its first argument, and reads
+// and writes resources as YAML streams.  It fails by
+// exiting non-zero.
+//
+// In version 2, the plugin reads a ResourceList from
+// stdin, holding its config as functionConfig and the
+// resources as items, and writes a ResourceList to stdout,
+// holding the resources and optionally results, which
+// report problems with severities, as functions do.
+// A result of error severity fails the build.
+//
+// A plugin speaks version 2 if its executable is named
+// with konfig.ExecPluginV2Suffix, and version 1 otherwise,
+// so that plugins predating version 2 run as they did.
+const (
+	// ProtocolVersionEnv is the version the plugin is run with.
+	ProtocolVersionEnv = "KUSTOMIZE_PLUGIN_PROTOCOL_VERSION"
+
+	// SchemaHandshakeEnv is set when a plugin speaking
+	// version 2 is only asked for the OpenAPI definitions
+	// of the resources it handles.  It writes them, as
+	// an OpenAPI document, or nothing, and exits.
+	SchemaHandshakeEnv = "KUSTOMIZE_PLUGIN_SCHEMA"
+
+	protocolV1 = 1
+	protocolV2 = 2
+)
+
+// schemaTimeout bounds the wait for the schema.
+var schemaTimeout = 10 * time.Second
+
+// protocolVersion returns the version the plugin speaks.
+func (p *ExecPlugin) protocolVersion() int {
+	if strings.HasSuffix(p.path, konfig.ExecPluginV2Suffix) {
+		return protocolV2
+	}
+	return protocolV1
+}
+
+// Schema returns the OpenAPI document the plugin writes
+// when asked for it, or nil if it doesn't speak version 2
+// or fails to answer, as plugins predating the question do.
+func (p *ExecPlugin) Schema() []byte {
+	if p.protocolVersion() != protocolV2 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), schemaTimeout)
+	defer cancel()
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Env = append(os.Environ(),
+		ProtocolVersionEnv+"="+strconv.Itoa(protocolV2),
+		SchemaHandshakeEnv+"=true")
+	out, err := sandbox.Output(p.sandbox, cmd)
+	if err != nil || len(bytes.TrimSpace(out)) == 0 {
+		return nil
+	}
+	return out
+}
+
+// invokePluginV2 runs the plugin on a ResourceList holding
+// its config and the given resources, returning the
+// resources it writes after reporting its results.
+func (p *ExecPlugin) invokePluginV2(input []byte) ([]byte, error) {
+	items, results, errRun, err := p.runPluginV2(input)
+	if err != nil {
+		return nil, err
+	}
+	if err = utils.ReportResults(os.Stderr, p.path, results); err != nil {
+		return nil, err
+	}
+	if errRun != nil {
+		return nil, errors.Wrapf(errRun, "failure in plugin %s", p.path)
+	}
+	return items, nil
+}
+
+// runPluginV2 runs the plugin on a ResourceList, returning
+// the resources and results it writes, and the error it
+// exited with, if its output could be read anyway.
+func (p *ExecPlugin) runPluginV2(
+	input []byte) ([]byte, *framework.Result, error, error) {
+	in, err := utils.MakeResourceList(p.cfg, input)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	//nolint:gosec
+	cmd := exec.Command(p.path, p.args...)
+	cmd.Env = append(p.getEnv(),
+		ProtocolVersionEnv+"="+strconv.Itoa(protocolV2))
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stderr = os.Stderr
+	if _, err := os.Stat(p.h.Loader().Root()); err == nil {
+		cmd.Dir = p.h.Loader().Root()
+	}
+	out, errRun := limits.Output(p.limits, p.start, cmd)
+	items, results, err := utils.ParseResourceList(out)
+	if err != nil {
+		if errRun != nil {
+			return nil, nil, nil, errors.Wrapf(errRun, "failure in plugin %s", p.path)
+		}
+		return nil, nil, nil, errors.Wrapf(err, "reading output of plugin %s", p.path)
+	}
+	return items, results, errRun, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package execplugin
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+)
+
+// Validate runs the plugin as a validator, returning
+// what it finds.  A plugin speaking version 2 of the
+// protocol reports findings as results; one speaking
+// version 1 reports a problem by exiting non-zero.
+// The resources the plugin writes are ignored.
+func (p *ExecPlugin) Validate(rm resmap.ResMap) ([]types.Finding, error) {
+	resources, err := rm.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+	if p.protocolVersion() != protocolV2 {
+		if _, err = p.invokePluginV1(resources); err != nil {
+			return []types.Finding{{
+				Severity: types.SeverityError,
+				Message: fmt.Sprintf(
+					"failure in plugin %s: %v", p.path, errors.Cause(err)),
+			}}, nil
+		}
+		return nil, nil
+	}
+	_, result, errRun, err := p.runPluginV2(resources)
+	if err != nil {
+		return nil, err
+	}
+	var findings []types.Finding
+	failed := false
+	for _, item := range result.Items {
+		f := finding(item)
+		failed = failed || f.Severity == types.SeverityError
+		findings = append(findings, f)
+	}
+	if errRun != nil && !failed {
+		findings = append(findings, types.Finding{
+			Severity: types.SeverityError,
+			Message:  fmt.Sprintf("failure in plugin %s: %v", p.path, errRun),
+		})
+	}
+	return findings, nil
+}
+
+func finding(item framework.Item) types.Finding {
+	f := types.Finding{
+		Message:  item.Message,
+		Resource: utils.ResourceRef(item),
+		Field:    item.Field.Path,
+	}
+	switch item.Severity {
+	case framework.Error:
+		f.Severity = types.SeverityError
+	case framework.Warning:
+		f.Severity = types.SeverityWarning
+	default:
+		f.Severity = types.SeverityInfo
+	}
+	return f
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fnplugin
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+// imageDigest returns the digest of the image,
+// or an error if it's unknown.  Tests replace it.
+var imageDigest = dockerImageDigest
+
+// dockerImageDigest returns the digest pinned in the
+// image name, else the id of the local image.
+func dockerImageDigest(image string) (string, error) {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:], nil
+	}
+	out, err := exec.Command(
+		"docker", "image", "inspect", "--format", "{{.Id}}", image).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// cacheKey returns the key of the output of the function
+// run on the input, or "" if the output isn't cached.
+// Only container functions are cached, and only those
+// without network access or mounts, whose output depends
+// on nothing but the image, its platform, the config
+// and the input.
+func (p *FnPlugin) cacheKey(spec *runtimeutil.FunctionSpec, input []byte) string {
+	if p.cacheDir == "" || spec == nil || spec.Container.Image == "" ||
+		spec.Container.Network.Required || len(spec.Container.StorageMounts) > 0 ||
+		len(spec.StorageMounts) > 0 || len(p.runFns.StorageMounts) > 0 {
+		return ""
+	}
+	digest, err := imageDigest(spec.Container.Image)
+	if err != nil || digest == "" {
+		return ""
+	}
+	h := sha256.New()
+	for _, part := range [][]byte{
+		[]byte(digest), []byte(p.runFns.ContainerPlatform), p.cfg, input} {
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// cachedOutput returns the cached output for the key.
+func (p *FnPlugin) cachedOutput(key string) ([]byte, bool) {
+	if key == "" || p.refreshCache {
+		return nil, false
+	}
+	out, err := ioutil.ReadFile(filepath.Join(p.cacheDir, key))
+	return out, err == nil
+}
+
+// cacheOutput stores the output for the key, ignoring
+// failures, which only cost running the function again.
+func (p *FnPlugin) cacheOutput(key string, output []byte) {
+	if key == "" {
+		return
+	}
+	if err := os.MkdirAll(p.cacheDir, 0700); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(p.cacheDir, key+".tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(output)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(p.cacheDir, key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fnplugin
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+const cachedFnConfig = `apiVersion: example.com/v1
+kind: Labeler
+metadata:
+  name: labeler
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/labeler:v1
+`
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-fn-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(f func(string) (string, error)) { imageDigest = f }(imageDigest)
+	imageDigest = func(image string) (string, error) {
+		return "sha256:" + image, nil
+	}
+	p := NewFnPlugin(&types.FnPluginLoadingOptions{CacheDir: dir})
+	p.cfg = []byte(cachedFnConfig)
+	if p.runFns.ContainerPlatform != container.HostPlatform() {
+		t.Fatalf("expected the host platform, got %q", p.runFns.ContainerPlatform)
+	}
+	spec := &runtimeutil.FunctionSpec{
+		Container: runtimeutil.ContainerSpec{Image: "example.com/labeler:v1"},
+	}
+	key := p.cacheKey(spec, []byte("kind: Service\n"))
+	if key == "" {
+		t.Fatalf("expected a cache key")
+	}
+	if other := p.cacheKey(spec, []byte("kind: Deployment\n")); other == key {
+		t.Fatalf("expected keys to differ with the input")
+	}
+	arm := NewFnPlugin(&types.FnPluginLoadingOptions{
+		CacheDir: dir, Platform: "linux/arm64"})
+	arm.cfg = p.cfg
+	amd := NewFnPlugin(&types.FnPluginLoadingOptions{
+		CacheDir: dir, Platform: "linux/amd64"})
+	amd.cfg = p.cfg
+	if arm.cacheKey(spec, []byte("kind: Service\n")) ==
+		amd.cacheKey(spec, []byte("kind: Service\n")) {
+		t.Fatalf("expected keys to differ with the platform")
+	}
+	if _, ok := p.cachedOutput(key); ok {
+		t.Fatalf("expected nothing cached")
+	}
+
+	// A cached output is returned without running the function,
+	// so this test doesn't need a container runtime.
+	p.cacheOutput(key, []byte("kind: Service\nmetadata:\n  name: cached\n"))
+	out, err := p.invokePlugin([]byte("kind: Service\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "kind: Service\nmetadata:\n  name: cached\n" {
+		t.Fatalf("unexpected output %s", out)
+	}
+
+	p.refreshCache = true
+	if _, ok := p.cachedOutput(key); ok {
+		t.Fatalf("expected the cache to be ignored when refreshing")
+	}
+}
+
+func TestCacheKeyNotCached(t *testing.T) {
+	defer func(f func(string) (string, error)) { imageDigest = f }(imageDigest)
+	imageDigest = func(image string) (string, error) {
+		return "sha256:" + image, nil
+	}
+	image := runtimeutil.ContainerSpec{Image: "example.com/labeler:v1"}
+	networked := image
+	networked.Network.Required = true
+	testCases := map[string]struct {
+		cacheDir string
+		spec     *runtimeutil.FunctionSpec
+	}{
+		"noCacheDir": {spec: &runtimeutil.FunctionSpec{Container: image}},
+		"exec": {
+			cacheDir: "/cache",
+			spec:     &runtimeutil.FunctionSpec{Exec: runtimeutil.ExecSpec{Path: "./fn"}},
+		},
+		"network": {
+			cacheDir: "/cache",
+			spec:     &runtimeutil.FunctionSpec{Container: networked},
+		},
+	}
+	for n, tc := range testCases {
+		p := NewFnPlugin(&types.FnPluginLoadingOptions{CacheDir: tc.cacheDir})
+		if key := p.cacheKey(tc.spec, nil); key != "" {
+			t.Errorf("%s: expected no key, got %s", n, key)
+		}
+	}
+}
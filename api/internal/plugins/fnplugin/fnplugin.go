@@ -5,14 +5,20 @@ package fnplugin
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"os/exec"
 
 	"github.com/pkg/errors"
 
+	"sigs.k8s.io/kustomize/api/internal/plugins/limits"
+	"sigs.k8s.io/kustomize/api/internal/plugins/sandbox"
 	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
 	"sigs.k8s.io/kustomize/kyaml/runfn"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
@@ -31,6 +37,23 @@ type FnPlugin struct {
 
 	// PluginHelpers
 	h *resmap.PluginHelpers
+
+	// Directory caching the output of the
+	// function, if any; see cacheKey.
+	cacheDir string
+
+	// If true, the cached output is replaced.
+	refreshCache bool
+
+	// Restrictions on the processes of exec functions.
+	sandbox types.ExecSandbox
+
+	// File the exec function runs, if resolved
+	// by the loader; see SetExecPath.
+	execPath string
+
+	// Bounds on the processes and containers running the function.
+	limits types.PluginLimits
 }
 
 func bytesToRNode(yml []byte) (*yaml.RNode, error) {
@@ -68,20 +91,81 @@ func toStorageMounts(mounts []string) []runtimeutil.StorageMount {
 	return sms
 }
 
+// startExec returns the way the processes of exec
+// functions are started, restricted per the policy,
+// running the file at path, if not empty.
+func startExec(policy types.ExecSandbox, path string) func(*exec.Cmd) error {
+	return func(cmd *exec.Cmd) error {
+		if path != "" {
+			cmd.Path = path
+		}
+		return sandbox.Start(policy, cmd)
+	}
+}
+
+// startContainer starts the docker client running a
+// container, naming the container, so that it can be
+// killed by name along with the client.
+func startContainer(cmd *exec.Cmd) error {
+	if len(cmd.Args) > 1 && cmd.Args[1] == "run" {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		args := []string{cmd.Args[0], "run", "--name", "kustomize-fn-" + hex.EncodeToString(b)}
+		cmd.Args = append(args, cmd.Args[2:]...)
+	}
+	return cmd.Start()
+}
+
+// killContainer kills the container the docker client
+// runs, if startContainer named it, as killing the
+// client leaves the container running.
+func killContainer(cmd *exec.Cmd) {
+	if len(cmd.Args) > 3 && cmd.Args[1] == "run" && cmd.Args[2] == "--name" {
+		_ = exec.Command(cmd.Path, "kill", cmd.Args[3]).Run()
+	}
+}
+
 // NewFnPlugin creates a FnPlugin struct
 func NewFnPlugin(o *types.FnPluginLoadingOptions) *FnPlugin {
+	// images run unemulated, unless asked otherwise; only
+	// the variant of a platform asked for replaces a local
+	// image of another platform
+	platform := o.Platform
+	if platform == "" {
+		platform = container.HostPlatform()
+	}
 	return &FnPlugin{
 		runFns: runfn.RunFns{
-			Functions:      []*yaml.RNode{},
-			Network:        o.Network,
-			NetworkName:    o.NetworkName,
-			EnableStarlark: o.EnableStar,
-			EnableExec:     o.EnableExec,
-			StorageMounts:  toStorageMounts(o.Mounts),
+			Functions:             []*yaml.RNode{},
+			Network:               o.Network,
+			NetworkName:           o.NetworkName,
+			EnableStarlark:        o.EnableStar,
+			EnableExec:            o.EnableExec,
+			StorageMounts:         toStorageMounts(o.Mounts),
+			ContainerPlatform:     platform,
+			ContainerPullPlatform: o.Platform != "",
 		},
+		cacheDir:     o.CacheDir,
+		refreshCache: o.RefreshCache,
+		sandbox:      o.ExecSandbox,
 	}
 }
 
+// SetLimits bounds the processes and containers running the function.
+func (p *FnPlugin) SetLimits(l types.PluginLimits) {
+	p.limits = l
+}
+
+// SetExecPath makes the exec function run the file at
+// path, the one its path in the config was resolved to
+// when the function was approved, rather than resolving
+// it again when it's run.
+func (p *FnPlugin) SetExecPath(path string) {
+	p.execPath = path
+}
+
 // Cfg returns function config
 func (p *FnPlugin) Cfg() []byte {
 	return p.cfg
@@ -180,6 +264,11 @@ func (p *FnPlugin) invokePlugin(input []byte) ([]byte, error) {
 		input = []byte(yml)
 	}
 
+	key := p.cacheKey(runtimeutil.GetFunctionSpec(functionConfig), input)
+	if output, ok := p.cachedOutput(key); ok {
+		return output, nil
+	}
+
 	// Configure and Execute Fn. We don't need to convert resources to ResourceList here
 	// because function runtime will do that. See kyaml/fn/runtime/runtimeutil/runtimeutil.go
 	var ouputBuffer bytes.Buffer
@@ -187,11 +276,26 @@ func (p *FnPlugin) invokePlugin(input []byte) ([]byte, error) {
 	p.runFns.Functions = append(p.runFns.Functions, functionConfig)
 	p.runFns.Output = &ouputBuffer
 
+	// Containers get their memory bounded by docker,
+	// rather than the client running them.
+	execs := limits.NewWatch(p.limits, startExec(p.sandbox, p.execPath))
+	cl := p.limits
+	cl.MaxMemory = 0
+	containers := limits.NewWatch(cl, startContainer)
+	containers.OnKill(killContainer)
+	p.runFns.StartExec = execs.Start
+	p.runFns.StartContainer = containers.Start
+	p.runFns.ContainerMaxMemory = p.limits.MaxMemory
+
 	err = p.runFns.Execute()
+	execs.Stop()
+	containers.Stop()
+	err = containers.Err(execs.Err(err))
 	if err != nil {
 		return nil, errors.Wrap(
 			err, "couldn't execute function")
 	}
 
+	p.cacheOutput(key, ouputBuffer.Bytes())
 	return ouputBuffer.Bytes(), nil
 }
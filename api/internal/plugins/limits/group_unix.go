@@ -0,0 +1,57 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+// +build !windows
+
+package limits
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// setGroup makes the command start a process group,
+// so that the processes it starts are killed with it.
+func setGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// killGroup kills the process group of the command,
+// returning false if the command had exited.
+func killGroup(cmd *exec.Cmd) bool {
+	if cmd.Process.Kill() != nil {
+		return false
+	}
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	return true
+}
+
+// forwardSignals forwards the interrupts and terminations
+// kustomize gets to the process group of the command, which
+// no longer gets those sent to the group of the terminal,
+// until stop is called.  The first forwarded is then sent
+// to kustomize again, to end it as it would have.
+func forwardSignals(cmd *exec.Cmd) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-ch:
+			_ = syscall.Kill(-cmd.Process.Pid, sig.(syscall.Signal))
+			signal.Stop(ch)
+			_ = syscall.Kill(os.Getpid(), sig.(syscall.Signal))
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package limits
+
+import "os/exec"
+
+// setGroup does nothing; the processes a
+// command starts aren't killed with it.
+func setGroup(*exec.Cmd) {}
+
+// killGroup kills the process of the command,
+// returning false if it had exited.
+func killGroup(cmd *exec.Cmd) bool {
+	return cmd.Process.Kill() == nil
+}
+
+// forwardSignals does nothing; the
+// command is in kustomize's group.
+func forwardSignals(*exec.Cmd) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,220 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package limits bounds the time, memory and output of
+// the processes running plugins, per types.PluginLimits.
+//
+// A process out of time, or writing too much, is killed
+// with the processes it started.  Memory is bounded on
+// Linux only, as soon as the process has started.
+//
+// Outside Windows, a process is started in a process group
+// of its own, to be killed with the processes it started,
+// so the interrupts and terminations kustomize gets from
+// the terminal are forwarded to the group.
+package limits
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// StartFunc starts a command, e.g. in a sandbox.
+type StartFunc func(cmd *exec.Cmd) error
+
+// Watch applies limits to the processes it starts, and
+// tells which limit a process that failed exceeded.
+type Watch struct {
+	limits types.PluginLimits
+	start  StartFunc
+	onKill func(cmd *exec.Cmd)
+
+	mu       sync.Mutex
+	exceeded error
+	timers   []*time.Timer
+	// forwards stop forwarding signals to the
+	// process groups of the processes started.
+	forwards []func()
+	// fired holds the timers that may have fired,
+	// until what they do is done.
+	fired sync.WaitGroup
+}
+
+// NewWatch returns a Watch starting processes with start,
+// or as exec.Cmd's Start does, if nil.
+func NewWatch(l types.PluginLimits, start StartFunc) *Watch {
+	if start == nil {
+		start = func(cmd *exec.Cmd) error { return cmd.Start() }
+	}
+	return &Watch{limits: l, start: start}
+}
+
+// OnKill arranges for f to be called with the commands
+// killed for exceeding a limit, e.g. to stop what they
+// started outside their process group.
+func (w *Watch) OnKill(f func(cmd *exec.Cmd)) {
+	w.onKill = f
+}
+
+// killed calls what OnKill arranged for the
+// command, once it was killed.
+func (w *Watch) killed(cmd *exec.Cmd) {
+	if w.onKill != nil {
+		w.onKill(cmd)
+	}
+}
+
+// Start starts the command with its output
+// bounded, then bounds its memory and time.
+// The caller waits for it as usual.
+func (w *Watch) Start(cmd *exec.Cmd) error {
+	if w.limits.MaxOutput > 0 && cmd.Stdout != nil {
+		cmd.Stdout = LimitWriter(cmd.Stdout, w.limits.MaxOutput, func() {
+			// The output may still be read once
+			// the process exited, so it's too much
+			// whether or not there's one to kill.
+			killGroup(cmd)
+			w.record(fmt.Errorf(
+				"plugin %s wrote more than the %d bytes of output allowed",
+				cmd.Path, w.limits.MaxOutput))
+			w.killed(cmd)
+		})
+	}
+	setGroup(cmd)
+	if err := w.start(cmd); err != nil {
+		return err
+	}
+	stop := forwardSignals(cmd)
+	w.mu.Lock()
+	w.forwards = append(w.forwards, stop)
+	w.mu.Unlock()
+	return w.Limit(cmd)
+}
+
+// Limit bounds the memory and time of a command
+// started otherwise, killing it if that fails.
+func (w *Watch) Limit(cmd *exec.Cmd) error {
+	if w.limits.MaxMemory > 0 {
+		if err := limitMemory(cmd.Process.Pid, w.limits.MaxMemory); err != nil {
+			killGroup(cmd)
+			w.killed(cmd)
+			return fmt.Errorf("limiting the memory of plugin %s: %v", cmd.Path, err)
+		}
+	}
+	if w.limits.Timeout > 0 {
+		w.fired.Add(1)
+		t := time.AfterFunc(w.limits.Timeout, func() {
+			defer w.fired.Done()
+			w.kill(cmd, fmt.Errorf(
+				"plugin %s timed out after %v", cmd.Path, w.limits.Timeout))
+		})
+		w.mu.Lock()
+		w.timers = append(w.timers, t)
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+// kill kills the command, if it's still running,
+// recording the limit it exceeded.
+func (w *Watch) kill(cmd *exec.Cmd, exceeded error) {
+	if killGroup(cmd) {
+		w.record(exceeded)
+		w.killed(cmd)
+	}
+}
+
+// record records the limit a process exceeded,
+// unless one was already.
+func (w *Watch) record(exceeded error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.exceeded == nil {
+		w.exceeded = exceeded
+	}
+}
+
+// Stop stops timing the processes started, and
+// forwarding signals to them, once they're waited
+// for, and waits for those that timed out to be
+// killed.
+func (w *Watch) Stop() {
+	w.mu.Lock()
+	for _, t := range w.timers {
+		if t.Stop() {
+			w.fired.Done()
+		}
+	}
+	w.timers = nil
+	for _, stop := range w.forwards {
+		stop()
+	}
+	w.forwards = nil
+	w.mu.Unlock()
+	w.fired.Wait()
+}
+
+// Err returns the error of a process that failed, or,
+// if it was killed for exceeding a limit, which one.
+func (w *Watch) Err(err error) error {
+	if err == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.exceeded != nil {
+		return w.exceeded
+	}
+	return err
+}
+
+// Output runs the command within the limits,
+// returning its standard output, as exec.Cmd's Output.
+func (w *Watch) Output(cmd *exec.Cmd) ([]byte, error) {
+	if cmd.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var b bytes.Buffer
+	cmd.Stdout = &b
+	if err := w.Start(cmd); err != nil {
+		return nil, err
+	}
+	err := cmd.Wait()
+	w.Stop()
+	return b.Bytes(), w.Err(err)
+}
+
+// Output runs the command like Watch's Output.
+func Output(l types.PluginLimits, start StartFunc, cmd *exec.Cmd) ([]byte, error) {
+	return NewWatch(l, start).Output(cmd)
+}
+
+// LimitWriter returns a Writer writing to w up to n
+// bytes, calling exceed once asked to write more.
+func LimitWriter(w io.Writer, n int64, exceed func()) io.Writer {
+	return &limitedWriter{w: w, n: n, exceed: exceed}
+}
+
+type limitedWriter struct {
+	w      io.Writer
+	n      int64
+	exceed func()
+}
+
+var errOutputExceeded = errors.New("output limit exceeded")
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.n {
+		l.exceed()
+		return 0, errOutputExceeded
+	}
+	l.n -= int64(len(p))
+	return l.w.Write(p)
+}
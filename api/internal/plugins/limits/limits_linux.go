@@ -0,0 +1,22 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package limits
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// limitMemory bounds the data segment of the process, which
+// on Linux counts the private writable memory it maps.
+func limitMemory(pid int, max int64) error {
+	l := syscall.Rlimit{Cur: uint64(max), Max: uint64(max)}
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64,
+		uintptr(pid), syscall.RLIMIT_DATA,
+		uintptr(unsafe.Pointer(&l)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+// +build !windows
+
+package limits
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestOutput(t *testing.T) {
+	testCases := map[string]struct {
+		script   string
+		limits   types.PluginLimits
+		expected string
+		errMsg   string
+	}{
+		"within limits": {
+			script:   "echo ok",
+			limits:   types.DefaultPluginLimits(),
+			expected: "ok\n",
+		},
+		"no limits": {
+			script:   "echo ok",
+			expected: "ok\n",
+		},
+		// The sleep holds the output open, unless
+		// it's killed with the shell.
+		"timeout": {
+			script: "sleep 30; echo late",
+			limits: types.PluginLimits{Timeout: 100 * time.Millisecond},
+			errMsg: "timed out after 100ms",
+		},
+		"output": {
+			script: "while true; do echo more; done",
+			limits: types.PluginLimits{MaxOutput: 1024},
+			errMsg: "wrote more than the 1024 bytes of output allowed",
+		},
+		"failure": {
+			script: "exit 3",
+			limits: types.DefaultPluginLimits(),
+			errMsg: "exit status 3",
+		},
+	}
+	for n, tc := range testCases {
+		began := time.Now()
+		out, err := Output(tc.limits, nil, exec.Command("/bin/sh", "-c", tc.script))
+		if time.Since(began) > 10*time.Second {
+			t.Errorf("%s: took %v", n, time.Since(began))
+		}
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if string(out) != tc.expected {
+			t.Errorf("%s: expected %q, got %q", n, tc.expected, out)
+		}
+	}
+}
+
+func TestOutputMemory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("memory is bounded on linux only")
+	}
+	script := `x=$(head -c 67108864 /dev/zero | tr '\0' a); echo ${#x}`
+	out, err := Output(types.PluginLimits{}, nil, exec.Command("/bin/sh", "-c", script))
+	if err != nil || strings.TrimSpace(string(out)) != "67108864" {
+		t.Fatalf("unexpected output %q, error %v", out, err)
+	}
+	_, err = Output(types.PluginLimits{MaxMemory: 16 << 20}, nil,
+		exec.Command("/bin/sh", "-c", script))
+	if err == nil {
+		t.Fatalf("expected the shell to run out of memory")
+	}
+}
+
+func TestOnKill(t *testing.T) {
+	var killed []string
+	w := NewWatch(types.PluginLimits{Timeout: 100 * time.Millisecond}, nil)
+	w.OnKill(func(cmd *exec.Cmd) {
+		killed = append(killed, cmd.Args[len(cmd.Args)-1])
+	})
+	_, err := w.Output(exec.Command("/bin/sh", "-c", "sleep 30"))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(killed) != 1 || killed[0] != "sleep 30" {
+		t.Fatalf("unexpected kills %v", killed)
+	}
+}
+
+func TestForwardSignals(t *testing.T) {
+	// Stands for kustomize's own handling of interrupts,
+	// catching rather than ending the test.
+	self := make(chan os.Signal, 2)
+	signal.Notify(self, os.Interrupt)
+	defer signal.Stop(self)
+
+	// The output is read through a pipe, so it's unbounded.
+	w := NewWatch(types.PluginLimits{Timeout: time.Minute}, nil)
+	cmd := exec.Command("/bin/sh", "-c",
+		`trap 'echo interrupted; exit 0' INT; echo ready; while true; do sleep 0.1; done`)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Start(cmd); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(stdout)
+	if line, _ := r.ReadString('\n'); line != "ready\n" {
+		t.Fatalf("unexpected output %q", line)
+	}
+	if err = syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan string, 1)
+	go func() {
+		line, _ := r.ReadString('\n')
+		done <- line
+	}()
+	select {
+	case line := <-done:
+		if line != "interrupted\n" {
+			t.Errorf("unexpected output %q", line)
+		}
+	case <-time.After(10 * time.Second):
+		cmd.Process.Kill()
+		t.Errorf("the plugin wasn't interrupted")
+	}
+	err = cmd.Wait()
+	w.Stop()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	select {
+	case <-self:
+	case <-time.After(10 * time.Second):
+		t.Errorf("kustomize wasn't interrupted")
+	}
+}
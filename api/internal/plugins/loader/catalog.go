@@ -0,0 +1,66 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+// errIfFunctionNotCatalogued returns an error if a
+// catalog is required and doesn't approve the function.
+// Exec functions are approved by the file they run;
+// see loadFnPlugin.
+func (l *Loader) errIfFunctionNotCatalogued(spec *runtimeutil.FunctionSpec) error {
+	if l.pc.Catalog == nil {
+		return nil
+	}
+	switch {
+	case spec.Container.Image != "":
+		if l.pc.Catalog.ApprovesImage(spec.Container.Image) {
+			return nil
+		}
+		return fmt.Errorf(
+			"function image %s is not in an approved catalog",
+			spec.Container.Image)
+	default:
+		return fmt.Errorf(
+			"only container and exec functions may be approved by a catalog")
+	}
+}
+
+// errIfNotCatalogued returns an error if a catalog is
+// required and doesn't approve the executable.
+func (l *Loader) errIfNotCatalogued(path string) error {
+	if l.pc.Catalog == nil {
+		return nil
+	}
+	digest, err := sha256File(path)
+	if err != nil {
+		return err
+	}
+	if l.pc.Catalog.ApprovesSha256(digest) {
+		return nil
+	}
+	return fmt.Errorf(
+		"executable %s (sha256 %s) is not in an approved catalog", path, digest)
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
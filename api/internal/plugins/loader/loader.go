@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"plugin"
 	"reflect"
@@ -17,12 +18,15 @@ import (
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinhelpers"
 	"sigs.k8s.io/kustomize/api/internal/plugins/execplugin"
 	"sigs.k8s.io/kustomize/api/internal/plugins/fnplugin"
+	"sigs.k8s.io/kustomize/api/internal/plugins/rpcplugin"
 	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
+	"sigs.k8s.io/kustomize/api/internal/plugins/wasmplugin"
 	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
 )
 
 // Loader loads plugins using a file loader (a different loader).
@@ -97,24 +101,95 @@ func relativePluginPath(id resid.ResId) string {
 
 func AbsolutePluginPath(pc *types.PluginConfig, id resid.ResId) string {
 	return filepath.Join(
-		pc.AbsPluginHome, relativePluginPath(id), id.Kind)
+		pluginHome(pc, id), relativePluginPath(id), id.Kind)
+}
+
+// pluginHome returns the first of AbsPluginHome and the
+// directories of the search path having a directory for
+// the plugin, else AbsPluginHome.
+func pluginHome(pc *types.PluginConfig, id resid.ResId) string {
+	homes := append([]string{pc.AbsPluginHome}, pc.PluginSearchPath...)
+	for _, home := range homes {
+		if isDir(filepath.Join(home, relativePluginPath(id))) {
+			return home
+		}
+	}
+	return pc.AbsPluginHome
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
 func (l *Loader) absolutePluginPath(id resid.ResId) string {
 	return AbsolutePluginPath(l.pc, id)
 }
 
+// PluginFiles returns the files the plugin configured by res
+// may be loaded from, whether or not they exist, including
+// the manifest of the schema it may declare.  Builtins and
+// functions run in containers have none.
+func (l *Loader) PluginFiles(res *resource.Resource) []string {
+	if isBuiltinPlugin(res) {
+		return nil
+	}
+	if fn := fnplugin.GetFunctionSpec(res); fn != nil {
+		var result []string
+		if fn.Exec.Path != "" {
+			path, err := execPath(fn.Exec.Path)
+			if err != nil {
+				path = fn.Exec.Path
+			}
+			result = append(result, path, path+konfig.PluginSchemaSuffix)
+		}
+		if fn.Starlark.Path != "" {
+			result = append(result, fn.Starlark.Path)
+		}
+		return result
+	}
+	path := l.absolutePluginPath(res.OrgId())
+	return []string{
+		path,
+		path + konfig.RpcPluginSuffix,
+		path + konfig.WasmPluginSuffix,
+		path + ".so",
+		path + konfig.PluginSchemaSuffix,
+	}
+}
+
 func isBuiltinPlugin(res *resource.Resource) bool {
 	// TODO: the special string should appear in Group, not Version.
 	return res.GetGvk().Group == "" &&
 		res.GetGvk().Version == konfig.BuiltinPluginApiVersion
 }
 
+// setLimits bounds the process running the plugin, if
+// it has its own, per its config's annotations.
+func (l *Loader) setLimits(c resmap.Configurable, res *resource.Resource) error {
+	p, ok := c.(interface{ SetLimits(types.PluginLimits) })
+	if !ok {
+		return nil
+	}
+	lim, err := l.pc.Limits.Override(res.GetAnnotations())
+	if err != nil {
+		return errors.Wrapf(err, "plugin %s", res.OrgId())
+	}
+	p.SetLimits(lim)
+	return nil
+}
+
 func (l *Loader) loadAndConfigurePlugin(
 	ldr ifc.Loader,
 	v ifc.Validator,
 	res *resource.Resource) (c resmap.Configurable, err error) {
 	if isBuiltinPlugin(res) {
+		if res.GetKind() == builtinhelpers.GoTemplateGenerator.String() &&
+			!l.pc.EnableGoTemplate {
+			return nil, fmt.Errorf(
+				"builtin %s is disabled; Go templates must be enabled explicitly",
+				res.GetKind())
+		}
 		switch l.pc.BpLoadingOptions {
 		case types.BploLoadFromFileSys:
 			c, err = l.loadPlugin(res)
@@ -135,6 +210,8 @@ func (l *Loader) loadAndConfigurePlugin(
 			c, err = l.loadPlugin(res)
 		case types.PluginRestrictionsBuiltinsOnly:
 			err = types.NewErrOnlyBuiltinPluginsAllowed(res.OrgId().Kind)
+		case types.PluginRestrictionsContainersOnly:
+			c, err = l.loadContainerPlugin(res)
 		default:
 			err = fmt.Errorf(
 				"unknown plugin restriction specified: %v",
@@ -144,6 +221,9 @@ func (l *Loader) loadAndConfigurePlugin(
 	if err != nil {
 		return nil, err
 	}
+	if err = l.setLimits(c, res); err != nil {
+		return nil, err
+	}
 	yaml, err := res.AsYAML()
 	if err != nil {
 		return nil, errors.Wrapf(err, "marshalling yaml from res %s", res.OrgId())
@@ -170,16 +250,66 @@ func (l *Loader) makeBuiltinPlugin(r resid.Gvk) (resmap.Configurable, error) {
 func (l *Loader) loadPlugin(res *resource.Resource) (resmap.Configurable, error) {
 	spec := fnplugin.GetFunctionSpec(res)
 	if spec != nil {
-		return fnplugin.NewFnPlugin(&l.pc.FnpLoadingOptions), nil
+		return l.loadFnPlugin(spec)
 	}
 	return l.loadExecOrGoPlugin(res.OrgId())
 }
 
+// loadFnPlugin loads the function, which, if run from an
+// executable, is approved and run from the same file.
+func (l *Loader) loadFnPlugin(spec *runtimeutil.FunctionSpec) (resmap.Configurable, error) {
+	p := fnplugin.NewFnPlugin(&l.pc.FnpLoadingOptions)
+	if spec.Container.Image != "" || spec.Exec.Path == "" {
+		return p, l.errIfFunctionNotCatalogued(spec)
+	}
+	path, err := execPath(spec.Exec.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "exec function %s", spec.Exec.Path)
+	}
+	if err = l.errIfNotCatalogued(path); err != nil {
+		return nil, err
+	}
+	p.SetExecPath(path)
+	return p, nil
+}
+
+// execPath returns the absolute path of the file an exec
+// function at path runs: a name without a separator is
+// looked up in the PATH, as exec.Command does, and other
+// paths are relative to the current directory.
+func execPath(path string) (string, error) {
+	p, err := exec.LookPath(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(p)
+}
+
+// loadContainerPlugin loads the plugin only if it's a function
+// run in a container, which can't reach the host.  Starlark
+// scripts are refused, as they're read on the host.
+func (l *Loader) loadContainerPlugin(res *resource.Resource) (resmap.Configurable, error) {
+	spec := fnplugin.GetFunctionSpec(res)
+	if spec == nil || spec.Container.Image == "" {
+		return nil, fmt.Errorf(
+			"plugin %s would run on the host; only container functions may run in a sandbox",
+			res.OrgId())
+	}
+	if err := l.errIfFunctionNotCatalogued(spec); err != nil {
+		return nil, err
+	}
+	return fnplugin.NewFnPlugin(&l.pc.FnpLoadingOptions), nil
+}
+
 func (l *Loader) loadExecOrGoPlugin(resId resid.ResId) (resmap.Configurable, error) {
 	// First try to load the plugin as an executable.
 	p := execplugin.NewExecPlugin(l.absolutePluginPath(resId))
+	p.SetSandbox(l.pc.FnpLoadingOptions.ExecSandbox)
 	err := p.ErrIfNotExecutable()
 	if err == nil {
+		if err = l.errIfNotCatalogued(p.Path()); err != nil {
+			return nil, err
+		}
 		return p, nil
 	}
 	if !os.IsNotExist(err) {
@@ -192,6 +322,40 @@ func (l *Loader) loadExecOrGoPlugin(resId resid.ResId) (resmap.Configurable, err
 		// in an obscure message.
 		return nil, err
 	}
+	// Next try an executable speaking version 2 of the protocol.
+	p = execplugin.NewExecPlugin(l.absolutePluginPath(resId) + konfig.ExecPluginV2Suffix)
+	p.SetSandbox(l.pc.FnpLoadingOptions.ExecSandbox)
+	err = p.ErrIfNotExecutable()
+	if err == nil {
+		if err = l.errIfNotCatalogued(p.Path()); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	// Next try an executable serving the plugin over RPC.
+	rp := rpcplugin.NewRpcPlugin(l.absolutePluginPath(resId) + konfig.RpcPluginSuffix)
+	rp.SetSandbox(l.pc.FnpLoadingOptions.ExecSandbox)
+	err = execplugin.NewExecPlugin(rp.Path()).ErrIfNotExecutable()
+	if err == nil {
+		if err = l.errIfNotCatalogued(rp.Path()); err != nil {
+			return nil, err
+		}
+		return rp, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	// Next try a module compiled to WebAssembly.
+	wp := wasmplugin.NewWasmPlugin(l.absolutePluginPath(resId) + konfig.WasmPluginSuffix)
+	if utils.FileExists(wp.Path()) {
+		if err = l.errIfNotCatalogued(wp.Path()); err != nil {
+			return nil, err
+		}
+		return wp, nil
+	}
 	// Failing the above, try loading it as a Go plugin.
 	c, err := l.loadGoPlugin(resId)
 	if err != nil {
@@ -210,14 +374,20 @@ var registry = make(map[string]resmap.Configurable)
 
 func (l *Loader) loadGoPlugin(id resid.ResId) (resmap.Configurable, error) {
 	regId := relativePluginPath(id)
+	absPath := l.absolutePluginPath(id) + ".so"
 	if c, ok := registry[regId]; ok {
+		if err := l.errIfNotCatalogued(absPath); err != nil {
+			return nil, err
+		}
 		return copyPlugin(c), nil
 	}
-	absPath := l.absolutePluginPath(id) + ".so"
 	if !utils.FileExists(absPath) {
 		return nil, fmt.Errorf(
 			"expected file with Go object code at: %s", absPath)
 	}
+	if err := l.errIfNotCatalogued(absPath); err != nil {
+		return nil, err
+	}
 	log.Printf("Attempting plugin load from '%s'", absPath)
 	p, err := plugin.Open(absPath)
 	if err != nil {
@@ -4,6 +4,10 @@
 package loader_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
@@ -11,6 +15,7 @@ import (
 	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
@@ -82,3 +87,424 @@ func TestLoader(t *testing.T) {
 		}
 	}
 }
+
+func TestLoaderContainersOnly(t *testing.T) {
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly,
+		filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := konfig.MakePluginConfig(
+		types.PluginRestrictionsContainersOnly,
+		types.BploUseStaticallyLinked, konfig.NoPluginHomeSentinal)
+	pLdr := NewLoader(c, rmF)
+	testCases := map[string]struct {
+		config string
+		errMsg string
+	}{
+		"builtin": {config: secretGenerator},
+		"container": {config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/gen:v1
+`},
+		"exec function": {
+			config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: ./gen
+`,
+			errMsg: "would run on the host",
+		},
+		"starlark": {
+			config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      starlark:
+        url: http://example.com/gen.star
+`,
+			errMsg: "would run on the host",
+		},
+		"exec or go": {
+			config: someServiceGenerator,
+			errMsg: "would run on the host",
+		},
+	}
+	for n, tc := range testCases {
+		configs, err := rmF.NewResMapFromBytes([]byte(tc.config))
+		if err != nil {
+			t.Fatalf("%s: %v", n, err)
+		}
+		_, err = pLdr.LoadGenerators(
+			fLdr, valtest_test.MakeFakeValidator(), configs)
+		if tc.errMsg == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", n, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+			t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+		}
+	}
+}
+
+func TestLoaderGoTemplate(t *testing.T) {
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly,
+		filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	configs, err := rmF.NewResMapFromBytes([]byte(`
+apiVersion: builtin
+kind: GoTemplateGenerator
+metadata:
+  name: gen
+template: |
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: cm
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := konfig.DisabledPluginConfig()
+	_, err = NewLoader(c, rmF).LoadGenerators(
+		fLdr, valtest_test.MakeFakeValidator(), configs)
+	if err == nil || !strings.Contains(err.Error(), "GoTemplateGenerator is disabled") {
+		t.Fatalf("expected disabled error, got %v", err)
+	}
+	c.EnableGoTemplate = true
+	_, err = NewLoader(c, rmF).LoadGenerators(
+		fLdr, valtest_test.MakeFakeValidator(), configs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAbsolutePluginPathSearchPath(t *testing.T) {
+	first, err := ioutil.TempDir("", "kustomize-plugins-first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(first)
+	second, err := ioutil.TempDir("", "kustomize-plugins-second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(second)
+	for _, dir := range []string{
+		filepath.Join(first, "someteam.example.com/v1/shared"),
+		filepath.Join(second, "someteam.example.com/v1/shared"),
+		filepath.Join(second, "someteam.example.com/v1/vendored"),
+	} {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pc := konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked, first)
+	pc.PluginSearchPath = []string{second}
+	testCases := map[string]string{
+		"Shared":   filepath.Join(first, "someteam.example.com/v1/shared/Shared"),
+		"Vendored": filepath.Join(second, "someteam.example.com/v1/vendored/Vendored"),
+		"Missing":  filepath.Join(first, "someteam.example.com/v1/missing/Missing"),
+	}
+	for kind, expected := range testCases {
+		id := resid.NewResId(resid.Gvk{
+			Group: "someteam.example.com", Version: "v1", Kind: kind}, "x")
+		if actual := AbsolutePluginPath(pc, id); actual != expected {
+			t.Errorf("%s: expected %s, got %s", kind, expected, actual)
+		}
+	}
+}
+
+func TestLoaderCatalog(t *testing.T) {
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly,
+		filesys.Separator, filesys.MakeFsInMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, err := ioutil.TempDir("", "kustomize-plugins-catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	for kind, script := range map[string]string{
+		"Approved":   "#!/bin/sh\necho approved\n",
+		"Unapproved": "#!/bin/sh\necho unapproved\n",
+	} {
+		dir := filepath.Join(home, "someteam.example.com/v1", strings.ToLower(kind))
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, kind), []byte(script), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked, home)
+	c.Catalog = &types.Catalog{Functions: []types.CatalogEntry{
+		{Image: "example.com/gen:v1"},
+		// The digest of the Approved script.
+		{Sha256: "25d2c09673fb1fed6b81fa005949ae3400d35fe3e665bcf7c9086321f87e27cb"},
+	}}
+	pLdr := NewLoader(c, rmF)
+	testCases := map[string]struct {
+		config string
+		errMsg string
+	}{
+		"builtin": {config: secretGenerator},
+		"approved image": {config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/gen:v1
+`},
+		"unapproved image": {
+			config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/gen:v2
+`,
+			errMsg: "function image example.com/gen:v2 is not in an approved catalog",
+		},
+		"starlark": {
+			config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      starlark:
+        path: gen.star
+`,
+			errMsg: "only container and exec functions may be approved",
+		},
+		"approved exec plugin": {config: `
+apiVersion: someteam.example.com/v1
+kind: Approved
+metadata:
+  name: gen
+`},
+		"unapproved exec plugin": {
+			config: `
+apiVersion: someteam.example.com/v1
+kind: Unapproved
+metadata:
+  name: gen
+`,
+			errMsg: "is not in an approved catalog",
+		},
+		"approved exec function": {config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: ./gen
+`},
+		// The file in the PATH runs, not the one in the
+		// current directory, so it's the one approved.
+		"exec function in the PATH": {
+			config: `
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: gen
+`,
+			errMsg: "is not in an approved catalog",
+		},
+	}
+	// The current directory holds the approved script as
+	// gen, and the PATH the unapproved one.
+	for dir, kind := range map[string]string{
+		"cwd": "approved", "bin": "unapproved"} {
+		src := filepath.Join(
+			home, "someteam.example.com/v1", kind, strings.Title(kind))
+		b, err := ioutil.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.Mkdir(filepath.Join(home, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(filepath.Join(home, dir, "gen"), b, 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err = os.Chdir(filepath.Join(home, "cwd")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", filepath.Join(home, "bin"))
+	for n, tc := range testCases {
+		configs, err := rmF.NewResMapFromBytes([]byte(tc.config))
+		if err != nil {
+			t.Fatalf("%s: %v", n, err)
+		}
+		_, err = pLdr.LoadGenerators(
+			fLdr, valtest_test.MakeFakeValidator(), configs)
+		if tc.errMsg == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", n, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+			t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+		}
+	}
+}
+
+func TestLoaderSchemas(t *testing.T) {
+	rmF := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	home, err := ioutil.TempDir("", "kustomize-plugins-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	for name, script := range map[string]string{
+		"Manifested": "#!/bin/sh\necho manifested\n",
+		"Plain":      "#!/bin/sh\necho plain\n",
+		"Telling" + konfig.ExecPluginV2Suffix: `#!/bin/sh
+if [ -n "$KUSTOMIZE_PLUGIN_SCHEMA" ]; then
+  echo '{"definitions": {"com.example.v1.Told": {"type": "object"}}}'
+fi
+`,
+		// Only plugins speaking version 2 are asked.
+		"Asked": `#!/bin/sh
+if [ -n "$KUSTOMIZE_PLUGIN_SCHEMA" ]; then
+  echo '{"definitions": {"com.example.v1.Asked": {"type": "object"}}}'
+fi
+`,
+		"Broken": "#!/bin/sh\necho broken\n",
+	} {
+		kind := strings.TrimSuffix(name, konfig.ExecPluginV2Suffix)
+		dir := filepath.Join(home, "someteam.example.com/v1", strings.ToLower(kind))
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(script), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifests := map[string]string{
+		"Manifested": `
+definitions:
+  com.example.v1.Declared:
+    type: object
+`,
+		"Broken": "definitions: [",
+	}
+	for kind, manifest := range manifests {
+		err = ioutil.WriteFile(filepath.Join(
+			home, "someteam.example.com/v1", strings.ToLower(kind),
+			kind+konfig.PluginSchemaSuffix), []byte(manifest), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	testCases := map[string]struct {
+		kind         string
+		restrictions types.PluginRestrictions
+		expected     string
+		errMsg       string
+	}{
+		"manifest": {kind: "Manifested", expected: "com.example.v1.Declared"},
+		"v2":       {kind: "Telling", expected: "com.example.v1.Told"},
+		"v1":       {kind: "Asked"},
+		"none":     {kind: "Plain"},
+		"builtins only": {
+			kind:         "Manifested",
+			restrictions: types.PluginRestrictionsBuiltinsOnly,
+		},
+		"broken": {kind: "Broken", errMsg: "reading schema of plugin"},
+	}
+	for n, tc := range testCases {
+		restrictions := tc.restrictions
+		if restrictions == types.PluginRestrictionsUnknown {
+			restrictions = types.PluginRestrictionsNone
+		}
+		pLdr := NewLoader(konfig.MakePluginConfig(
+			restrictions, types.BploUseStaticallyLinked, home), rmF)
+		configs, err := rmF.NewResMapFromBytes([]byte(`
+apiVersion: someteam.example.com/v1
+kind: ` + tc.kind + `
+metadata:
+  name: gen
+`))
+		if err != nil {
+			t.Fatalf("%s: %v", n, err)
+		}
+		defs, err := pLdr.LoadSchemas(configs)
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if tc.expected == "" {
+			if len(defs) != 0 {
+				t.Errorf("%s: expected no definitions, got %v", n, defs)
+			}
+			continue
+		}
+		if _, ok := defs[tc.expected]; !ok || len(defs) != 1 {
+			t.Errorf("%s: expected definition %s, got %v", n, tc.expected, defs)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/internal/plugins/execplugin"
+	"sigs.k8s.io/kustomize/api/internal/plugins/fnplugin"
+	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// LoadSchemas returns the OpenAPI definitions of the custom
+// resources the plugins configured in rm handle, so that they
+// can be patched and referred to by name as builtin kinds are.
+//
+// A plugin declares them in a manifest next to it, named
+// after it with konfig.PluginSchemaSuffix; an exec plugin
+// speaking protocol version 2, named with
+// konfig.ExecPluginV2Suffix, may write them instead when
+// asked per execplugin.SchemaHandshakeEnv.  Functions run
+// from an executable may have a manifest next to it.
+// Container functions can't declare any.
+func (l *Loader) LoadSchemas(rm resmap.ResMap) (spec.Definitions, error) {
+	result := spec.Definitions{}
+	if l.pc.PluginRestrictions != types.PluginRestrictionsNone {
+		return result, nil
+	}
+	for _, res := range rm.Resources() {
+		if isBuiltinPlugin(res) {
+			continue
+		}
+		var b []byte
+		var err error
+		if fn := fnplugin.GetFunctionSpec(res); fn != nil {
+			if fn.Exec.Path == "" {
+				continue
+			}
+			path, pathErr := execPath(fn.Exec.Path)
+			if pathErr != nil {
+				// the function fails to load
+				continue
+			}
+			b, err = loadManifest(path)
+		} else {
+			b, err = l.loadSchema(l.absolutePluginPath(res.OrgId()))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			continue
+		}
+		defs, err := parseSchema(b)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "reading schema of plugin %s", res.OrgId())
+		}
+		for name, d := range defs {
+			result[name] = d
+		}
+	}
+	return result, nil
+}
+
+// loadSchema returns the schema declared for the
+// plugin at path, or nil if there's none.
+func (l *Loader) loadSchema(path string) ([]byte, error) {
+	b, err := loadManifest(path)
+	if b != nil || err != nil {
+		return b, err
+	}
+	p := execplugin.NewExecPlugin(path + konfig.ExecPluginV2Suffix)
+	p.SetSandbox(l.pc.FnpLoadingOptions.ExecSandbox)
+	if p.ErrIfNotExecutable() != nil {
+		return nil, nil
+	}
+	return p.Schema(), nil
+}
+
+// loadManifest returns the manifest next to
+// the plugin at path, or nil if there's none.
+func loadManifest(path string) ([]byte, error) {
+	manifest := path + konfig.PluginSchemaSuffix
+	if !utils.FileExists(manifest) {
+		return nil, nil
+	}
+	return ioutil.ReadFile(manifest)
+}
+
+// parseSchema returns the definitions of
+// an OpenAPI document, in JSON or YAML.
+func parseSchema(b []byte) (spec.Definitions, error) {
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	var s spec.Schema
+	if err = json.Unmarshal(j, &s); err != nil {
+		return nil, err
+	}
+	return s.Definitions, nil
+}
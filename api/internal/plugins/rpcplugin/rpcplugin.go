@@ -0,0 +1,155 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rpcplugin runs plugins served
+// over the pluginrpc protocol.
+package rpcplugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/internal/plugins/limits"
+	"sigs.k8s.io/kustomize/api/internal/plugins/sandbox"
+	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
+	"sigs.k8s.io/kustomize/api/pluginrpc"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// RpcPlugin is a generator or transformer run as a
+// subprocess, started anew for each call, like an
+// exec plugin.
+type RpcPlugin struct {
+	// absolute path of the executable
+	path string
+
+	// Plugin configuration data.
+	cfg []byte
+
+	h *resmap.PluginHelpers
+
+	// Restrictions on the process running the plugin.
+	sandbox types.ExecSandbox
+
+	// Bounds on the process running the plugin.
+	limits types.PluginLimits
+}
+
+func NewRpcPlugin(p string) *RpcPlugin {
+	return &RpcPlugin{path: p}
+}
+
+func (p *RpcPlugin) Path() string {
+	return p.path
+}
+
+// SetSandbox restricts the process running the plugin.
+func (p *RpcPlugin) SetSandbox(s types.ExecSandbox) {
+	p.sandbox = s
+}
+
+// start starts the process running the plugin in its sandbox.
+func (p *RpcPlugin) start(cmd *exec.Cmd) error {
+	return sandbox.Start(p.sandbox, cmd)
+}
+
+// SetLimits bounds the process running the plugin.
+func (p *RpcPlugin) SetLimits(l types.PluginLimits) {
+	p.limits = l
+}
+
+func (p *RpcPlugin) Config(h *resmap.PluginHelpers, config []byte) error {
+	p.h = h
+	p.cfg = config
+	return nil
+}
+
+func (p *RpcPlugin) Generate() (resmap.ResMap, error) {
+	var output []byte
+	err := p.call(func(c *pluginrpc.Client, caps pluginrpc.Capabilities) (err error) {
+		if !caps.Generator {
+			return fmt.Errorf("plugin %s isn't a generator", p.path)
+		}
+		output, err = c.Generate()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	rm, err := p.h.ResmapFactory().NewResMapFromBytes(output)
+	if err != nil {
+		return nil, err
+	}
+	return utils.UpdateResourceOptions(rm)
+}
+
+func (p *RpcPlugin) Transform(rm resmap.ResMap) error {
+	// add ResIds as annotations to all objects so that we can add them back
+	inputRM, err := utils.GetResMapWithIDAnnotation(rm)
+	if err != nil {
+		return err
+	}
+	resources, err := inputRM.AsYaml()
+	if err != nil {
+		return err
+	}
+	var output []byte
+	err = p.call(func(c *pluginrpc.Client, caps pluginrpc.Capabilities) (err error) {
+		if !caps.Transformer {
+			return fmt.Errorf("plugin %s isn't a transformer", p.path)
+		}
+		output, err = c.Transform(resources)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return utils.UpdateResMapValues(p.path, p.h, output, rm)
+}
+
+// call starts and configures the plugin, calls f,
+// and stops the plugin.
+func (p *RpcPlugin) call(
+	f func(*pluginrpc.Client, pluginrpc.Capabilities) error) error {
+	//nolint:gosec
+	cmd := exec.Command(p.path)
+	cmd.Stderr = os.Stderr
+	root := p.h.Loader().Root()
+	if _, err := os.Stat(root); err == nil {
+		cmd.Dir = root
+	}
+	// The watch bounds the handshake too, killing
+	// a plugin that takes longer than its timeout.
+	// The plugin's output comes over gRPC, rather
+	// than through its stdout.
+	l := p.limits
+	l.MaxOutput = 0
+	w := limits.NewWatch(l, p.start)
+	c, err := pluginrpc.StartWith(cmd, w.Start, 0)
+	if err != nil {
+		w.Stop()
+		return w.Err(err)
+	}
+	err = p.callStarted(c, root, f)
+	if errClose := c.Close(); err == nil && errClose != nil {
+		err = errors.Wrapf(errClose, "plugin %s exited", p.path)
+	}
+	w.Stop()
+	return w.Err(err)
+}
+
+func (p *RpcPlugin) callStarted(
+	c *pluginrpc.Client, root string,
+	f func(*pluginrpc.Client, pluginrpc.Capabilities) error) error {
+	caps, err := c.Capabilities()
+	if err != nil {
+		return errors.Wrapf(err, "asking plugin %s for its capabilities", p.path)
+	}
+	if err = c.Config(root, p.cfg); err != nil {
+		return errors.Wrapf(err, "plugin %s fails configuration", p.path)
+	}
+	return errors.Wrapf(f(c, caps), "failure in plugin %s", p.path)
+}
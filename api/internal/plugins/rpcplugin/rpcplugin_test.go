@@ -0,0 +1,149 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpcplugin_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	. "sigs.k8s.io/kustomize/api/internal/plugins/rpcplugin"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	fLdr "sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/pluginrpc"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// testPluginEnv makes the test binary serve writePlugin.
+const testPluginEnv = "RPCPLUGIN_TEST_PLUGIN"
+
+// writePlugin generates a ConfigMap telling whether
+// it could write the file named by its config.
+type writePlugin struct {
+	path string
+}
+
+func (p *writePlugin) Config(_ string, config []byte) error {
+	p.path = strings.TrimSpace(string(config))
+	return nil
+}
+
+func (p *writePlugin) Generate() ([]byte, error) {
+	err := ioutil.WriteFile(p.path, []byte("x"), 0644)
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: generated
+data:
+  written: "%v"
+`, err == nil)), nil
+}
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(testPluginEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "hang":
+		// Never shakes hands.
+		time.Sleep(time.Minute)
+		os.Exit(1)
+	}
+	if err := pluginrpc.Serve(&writePlugin{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func makeHelpers(t *testing.T, root string) *resmap.PluginHelpers {
+	ldr, err := fLdr.NewLoader(
+		fLdr.RestrictionRootOnly, root, filesys.MakeFsOnDisk())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			kunstruct.NewKunstructuredFactoryImpl()), nil)
+	return resmap.NewPluginHelpers(ldr, valtest_test.MakeFakeValidator(), rf)
+}
+
+func TestRpcPluginHandshakeTimeout(t *testing.T) {
+	defer os.Unsetenv(testPluginEnv)
+	os.Setenv(testPluginEnv, "hang")
+	p := NewRpcPlugin(os.Args[0])
+	p.SetLimits(types.PluginLimits{Timeout: 100 * time.Millisecond})
+	if err := p.Config(makeHelpers(t, os.TempDir()), nil); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err := p.Generate()
+	if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("the handshake took %v", d)
+	}
+}
+
+func TestRpcPluginSandbox(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("exec sandboxes are only supported on linux")
+	}
+	root, err := ioutil.TempDir("", "kustomize-rpcplugin-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	work := filepath.Join(root, "work")
+	tmp := filepath.Join(root, "tmp")
+	for _, d := range []string{work, tmp} {
+		if err = os.Mkdir(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The plugin's temporary directory mustn't hold the others.
+	defer os.Setenv("TMPDIR", os.Getenv("TMPDIR"))
+	os.Setenv("TMPDIR", tmp)
+	defer os.Unsetenv(testPluginEnv)
+	os.Setenv(testPluginEnv, "1")
+
+	h := makeHelpers(t, work)
+	out := filepath.Join(root, "out")
+	testCases := map[types.ExecSandbox]string{
+		types.ExecSandboxNone:    "true",
+		types.ExecSandboxWorkdir: "false",
+	}
+	for policy, expected := range testCases {
+		os.Remove(out)
+		p := NewRpcPlugin(os.Args[0])
+		p.SetSandbox(policy)
+		p.SetLimits(types.DefaultPluginLimits())
+		if err = p.Config(h, []byte(out)); err != nil {
+			t.Fatal(err)
+		}
+		rm, err := p.Generate()
+		if err != nil {
+			if strings.Contains(err.Error(), "landlock") {
+				t.Skip(err)
+			}
+			t.Fatalf("%s: unexpected error: %v", policy, err)
+		}
+		written, err := rm.Resources()[0].GetFieldValue("data.written")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != expected {
+			t.Errorf("%s: expected written %s, got %s", policy, expected, written)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sandbox starts the processes of exec plugins
+// and exec functions with OS-level restrictions.
+//
+// On Linux, landlock confines the filesystem the process
+// may reach, and seccomp refuses it the network and the
+// syscalls administering the host.  Both are applied to a
+// thread dedicated to starting the process, which the
+// process inherits them from; kustomize itself is never
+// restricted.
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// systemDirs are the directories holding what programs
+// need to run, which restricted processes may read.
+var systemDirs = []string{
+	"/bin", "/etc", "/lib", "/lib32", "/lib64",
+	"/opt", "/proc", "/sbin", "/usr",
+}
+
+// Start starts the command, restricted per the policy.
+// The caller waits for it as usual.
+func Start(policy types.ExecSandbox, cmd *exec.Cmd) error {
+	if policy == "" || policy == types.ExecSandboxNone {
+		return cmd.Start()
+	}
+	return start(policy, cmd)
+}
+
+// Output runs the command restricted per the policy,
+// returning its standard output, as exec.Cmd's Output.
+func Output(policy types.ExecSandbox, cmd *exec.Cmd) ([]byte, error) {
+	if cmd.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var b bytes.Buffer
+	cmd.Stdout = &b
+	if err := Start(policy, cmd); err != nil {
+		return nil, err
+	}
+	err := cmd.Wait()
+	return b.Bytes(), err
+}
+
+// readDirs returns the directories the command may
+// read: the system's and that of the executable.
+func readDirs(cmd *exec.Cmd) []string {
+	dirs := append([]string{}, systemDirs...)
+	return append(dirs, filepath.Dir(absPath(cmd, cmd.Path)))
+}
+
+// writeDirs returns the directories the command may
+// write: its working directory, the temporary directory
+// and the devices.
+func writeDirs(cmd *exec.Cmd) []string {
+	return []string{absPath(cmd, "."), os.TempDir(), "/dev"}
+}
+
+// absPath returns the path, relative to the working
+// directory of the command, made absolute.
+func absPath(cmd *exec.Cmd, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	if cmd.Dir != "" {
+		path = filepath.Join(cmd.Dir, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
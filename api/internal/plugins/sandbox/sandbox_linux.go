@@ -0,0 +1,175 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	prSetNoNewPrivs = 38
+	oPath           = 0x200000
+
+	sysLandlockCreateRuleset = 444
+	sysLandlockAddRule       = 445
+	sysLandlockRestrictSelf  = 446
+
+	landlockCreateRulesetVersion = 1
+	landlockRulePathBeneath      = 1
+
+	// The access rights of the first landlock ABI.
+	accessExecute    = 1 << 0
+	accessWriteFile  = 1 << 1
+	accessReadFile   = 1 << 2
+	accessReadDir    = 1 << 3
+	accessRemoveDir  = 1 << 4
+	accessRemoveFile = 1 << 5
+	accessMakeChar   = 1 << 6
+	accessMakeDir    = 1 << 7
+	accessMakeReg    = 1 << 8
+	accessMakeSock   = 1 << 9
+	accessMakeFifo   = 1 << 10
+	accessMakeBlock  = 1 << 11
+	accessMakeSym    = 1 << 12
+
+	accessFile  = accessExecute | accessWriteFile | accessReadFile
+	accessRead  = accessExecute | accessReadFile | accessReadDir
+	accessWrite = accessFile | accessReadDir | accessRemoveDir |
+		accessRemoveFile | accessMakeChar | accessMakeDir |
+		accessMakeReg | accessMakeSock | accessMakeFifo |
+		accessMakeBlock | accessMakeSym
+)
+
+type landlockRulesetAttr struct {
+	handledAccessFs uint64
+}
+
+// landlockPathBeneathAttr is packed in the kernel,
+// which ignores the trailing padding of this struct.
+type landlockPathBeneathAttr struct {
+	allowedAccess uint64
+	parentFd      int32
+}
+
+// start applies the restrictions to a thread locked for
+// the purpose, and starts the command from it.  The thread
+// is never unlocked, so that it exits with its goroutine,
+// leaving the rest of the process unrestricted.
+func start(policy types.ExecSandbox, cmd *exec.Cmd) error {
+	if policy != types.ExecSandboxWorkdir && policy != types.ExecSandboxStrict {
+		return fmt.Errorf("unknown exec sandbox %q", policy)
+	}
+	if policy == types.ExecSandboxStrict && len(deniedSyscalls) == 0 {
+		return fmt.Errorf(
+			"exec sandbox %s not supported on %s", policy, runtime.GOARCH)
+	}
+	ruleset, err := makeRuleset(cmd)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(ruleset)
+	filter := seccompFilter()
+	errs := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		errs <- restrictAndStart(policy, ruleset, filter, cmd)
+	}()
+	return <-errs
+}
+
+func restrictAndStart(
+	policy types.ExecSandbox, ruleset int,
+	filter []sockFilter, cmd *exec.Cmd) error {
+	_, _, errno := syscall.RawSyscall6(
+		syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0, 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("setting no_new_privs: %v", errno)
+	}
+	_, _, errno = syscall.RawSyscall(
+		sysLandlockRestrictSelf, uintptr(ruleset), 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("landlock_restrict_self: %v", errno)
+	}
+	if policy == types.ExecSandboxStrict {
+		if err := installSeccompFilter(filter); err != nil {
+			return err
+		}
+	}
+	return cmd.Start()
+}
+
+// landlockABI returns the version of landlock
+// the kernel supports, or an error if none.
+func landlockABI() (int, error) {
+	v, _, errno := syscall.Syscall(
+		sysLandlockCreateRuleset, 0, 0, landlockCreateRulesetVersion)
+	if errno != 0 {
+		return 0, fmt.Errorf("landlock unavailable: %v", errno)
+	}
+	return int(v), nil
+}
+
+// makeRuleset returns a landlock ruleset letting the
+// command read its read dirs and write its write dirs.
+func makeRuleset(cmd *exec.Cmd) (int, error) {
+	if _, err := landlockABI(); err != nil {
+		return -1, err
+	}
+	attr := landlockRulesetAttr{handledAccessFs: accessWrite}
+	fd, _, errno := syscall.Syscall(
+		sysLandlockCreateRuleset, uintptr(unsafe.Pointer(&attr)),
+		unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return -1, fmt.Errorf("landlock_create_ruleset: %v", errno)
+	}
+	ruleset := int(fd)
+	for _, d := range readDirs(cmd) {
+		if err := addRule(ruleset, d, accessRead); err != nil {
+			syscall.Close(ruleset)
+			return -1, err
+		}
+	}
+	for _, d := range writeDirs(cmd) {
+		if err := addRule(ruleset, d, accessWrite); err != nil {
+			syscall.Close(ruleset)
+			return -1, err
+		}
+	}
+	return ruleset, nil
+}
+
+// addRule lets the ruleset access beneath the path,
+// unless the path doesn't exist.
+func addRule(ruleset int, path string, access uint64) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		access &= accessFile
+	}
+	fd, err := syscall.Open(path, oPath|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("opening %s: %v", path, err)
+	}
+	defer syscall.Close(fd)
+	attr := landlockPathBeneathAttr{allowedAccess: access, parentFd: int32(fd)}
+	_, _, errno := syscall.Syscall6(
+		sysLandlockAddRule, uintptr(ruleset), landlockRulePathBeneath,
+		uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("landlock_add_rule %s: %v", path, errno)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const helperEnv = "KUSTOMIZE_SANDBOX_HELPER"
+
+// TestHelperProcess isn't a test; it's run in a sandbox by
+// the other tests, and reports what it may do.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(helperEnv) == "" {
+		return
+	}
+	for _, arg := range os.Args {
+		switch {
+		case strings.HasPrefix(arg, "read="):
+			_, err := ioutil.ReadFile(strings.TrimPrefix(arg, "read="))
+			fmt.Printf("read %v\n", err == nil)
+		case strings.HasPrefix(arg, "write="):
+			err := ioutil.WriteFile(strings.TrimPrefix(arg, "write="), []byte("x"), 0644)
+			fmt.Printf("write %v\n", err == nil)
+		case arg == "socket":
+			fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0)
+			if err == nil {
+				syscall.Close(fd)
+			}
+			fmt.Printf("socket %v\n", err == nil)
+		}
+	}
+	os.Exit(0)
+}
+
+func runHelper(
+	t *testing.T, policy types.ExecSandbox, dir string, args ...string) string {
+	cmd := exec.Command(os.Args[0],
+		append([]string{"-test.run=TestHelperProcess", "--"}, args...)...)
+	cmd.Env = append(os.Environ(), helperEnv+"=1")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	out, err := Output(policy, cmd)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", policy, err)
+	}
+	return string(out)
+}
+
+func TestSandbox(t *testing.T) {
+	if _, err := landlockABI(); err != nil {
+		t.Skip(err)
+	}
+	root, err := ioutil.TempDir("", "kust-sandbox-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	work := filepath.Join(root, "work")
+	tmp := filepath.Join(root, "tmp")
+	for _, d := range []string{work, tmp} {
+		if err = os.Mkdir(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	secret := filepath.Join(root, "secret")
+	if err = ioutil.WriteFile(secret, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The helper's temporary directory mustn't hold the others.
+	defer os.Setenv("TMPDIR", os.Getenv("TMPDIR"))
+	os.Setenv("TMPDIR", tmp)
+
+	args := []string{
+		"read=" + secret,
+		"write=" + filepath.Join(root, "out"),
+		"write=" + filepath.Join(work, "out"),
+		"write=" + filepath.Join(tmp, "out"),
+		"socket",
+	}
+	testCases := map[types.ExecSandbox]string{
+		types.ExecSandboxNone:    "read true\nwrite true\nwrite true\nwrite true\nsocket true\n",
+		types.ExecSandboxWorkdir: "read false\nwrite false\nwrite true\nwrite true\nsocket true\n",
+		types.ExecSandboxStrict:  "read false\nwrite false\nwrite true\nwrite true\nsocket false\n",
+	}
+	for policy, expected := range testCases {
+		os.Remove(filepath.Join(root, "out"))
+		actual := runHelper(t, policy, work, args...)
+		if actual != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", policy, expected, actual)
+		}
+	}
+	// kustomize itself isn't restricted.
+	if _, err = ioutil.ReadFile(secret); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
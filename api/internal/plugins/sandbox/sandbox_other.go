@@ -0,0 +1,20 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+// +build !linux
+
+package sandbox
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func start(policy types.ExecSandbox, _ *exec.Cmd) error {
+	return fmt.Errorf(
+		"exec sandbox %s not supported on %s", policy, runtime.GOOS)
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	prSetSeccomp      = 22
+	seccompModeFilter = 2
+
+	bpfLdAbs = 0x20
+	bpfJeqK  = 0x15
+	bpfJgeK  = 0x35
+	bpfRetK  = 0x06
+
+	seccompRetKillProcess = 0x80000000
+	seccompRetErrno       = 0x00050000
+	seccompRetAllow       = 0x7fff0000
+
+	// Offsets in struct seccomp_data.
+	offsetNr   = 0
+	offsetArch = 4
+	offsetArg0 = 16
+
+	afUnix = 1
+)
+
+type sockFilter struct {
+	code uint16
+	jt   uint8
+	jf   uint8
+	k    uint32
+}
+
+type sockFprog struct {
+	len    uint16
+	filter *sockFilter
+}
+
+func stmt(code uint16, k uint32) sockFilter {
+	return sockFilter{code: code, k: k}
+}
+
+func jump(code uint16, k uint32, jt, jf uint8) sockFilter {
+	return sockFilter{code: code, jt: jt, jf: jf, k: k}
+}
+
+// seccompFilter returns a program refusing the denied
+// syscalls, and sockets other than unix sockets, with
+// EPERM, and killing processes of a foreign arch.
+func seccompFilter() []sockFilter {
+	deny := stmt(bpfRetK, seccompRetErrno|uint32(syscall.EPERM))
+	prog := []sockFilter{
+		stmt(bpfLdAbs, offsetArch),
+		jump(bpfJeqK, auditArch, 1, 0),
+		stmt(bpfRetK, seccompRetKillProcess),
+		stmt(bpfLdAbs, offsetNr),
+	}
+	if minForeignSyscall != 0 {
+		prog = append(prog, jump(bpfJgeK, minForeignSyscall, 0, 1), deny)
+	}
+	for _, nr := range deniedSyscalls {
+		prog = append(prog, jump(bpfJeqK, nr, 0, 1), deny)
+	}
+	return append(prog,
+		jump(bpfJeqK, sysSocket, 0, 4),
+		stmt(bpfLdAbs, offsetArg0),
+		jump(bpfJeqK, afUnix, 0, 1),
+		stmt(bpfRetK, seccompRetAllow),
+		deny,
+		stmt(bpfRetK, seccompRetAllow))
+}
+
+// installSeccompFilter applies the filter to the
+// calling thread, which must have no_new_privs.
+func installSeccompFilter(filter []sockFilter) error {
+	prog := sockFprog{len: uint16(len(filter)), filter: &filter[0]}
+	_, _, errno := syscall.RawSyscall(
+		syscall.SYS_PRCTL, prSetSeccomp, seccompModeFilter,
+		uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return fmt.Errorf("installing seccomp filter: %v", errno)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+const (
+	auditArch         = 0xc00000b7
+	sysSocket         = 198
+	minForeignSyscall = 0
+)
+
+// deniedSyscalls administer the host, or
+// inspect or escape from the process.
+var deniedSyscalls = []uint32{
+	39,  // umount2
+	40,  // mount
+	41,  // pivot_root
+	51,  // chroot
+	97,  // unshare
+	104, // kexec_load
+	105, // init_module
+	106, // delete_module
+	117, // ptrace
+	142, // reboot
+	217, // add_key
+	218, // request_key
+	219, // keyctl
+	224, // swapon
+	225, // swapoff
+	241, // perf_event_open
+	268, // setns
+	270, // process_vm_readv
+	271, // process_vm_writev
+	273, // finit_module
+	280, // bpf
+	282, // userfaultfd
+	294, // kexec_file_load
+	425, // io_uring_setup
+}
@@ -0,0 +1,16 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && !amd64 && !arm64
+// +build linux,!amd64,!arm64
+
+package sandbox
+
+// The strict policy isn't supported on other archs.
+const (
+	auditArch         = 0
+	sysSocket         = 0
+	minForeignSyscall = 0
+)
+
+var deniedSyscalls []uint32
@@ -0,0 +1,134 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Plugins run out of process, other than exec plugins
+// speaking version 1 of their protocol, read and write
+// ResourceLists, as functions do.  The plugin's config is
+// the functionConfig; results of error severity fail the build.
+
+const resourceListAPIVersion = "config.kubernetes.io/v1alpha1"
+
+// MakeResourceList returns the ResourceList given to a
+// plugin with the config, holding the resources in input.
+func MakeResourceList(cfg, input []byte) ([]byte, error) {
+	config, err := kyaml.Parse(string(cfg))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing plugin config")
+	}
+	var items []*kyaml.RNode
+	if len(input) > 0 {
+		items, err = (&kio.ByteReader{
+			Reader:                bytes.NewReader(input),
+			OmitReaderAnnotations: true,
+		}).Read()
+		if err != nil {
+			return nil, err
+		}
+	}
+	var b bytes.Buffer
+	err = kio.ByteWriter{
+		Writer:             &b,
+		FunctionConfig:     config,
+		WrappingKind:       kio.ResourceListKind,
+		WrappingAPIVersion: resourceListAPIVersion,
+	}.Write(items)
+	return b.Bytes(), err
+}
+
+// ParseResourceList returns the items of the ResourceList
+// a plugin wrote, as a YAML stream, and its results.
+func ParseResourceList(out []byte) ([]byte, *framework.Result, error) {
+	r := &kio.ByteReader{
+		Reader:                bytes.NewReader(out),
+		OmitReaderAnnotations: true,
+	}
+	items, err := r.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.WrappingKind != kio.ResourceListKind {
+		return nil, nil, fmt.Errorf(
+			"expected a %s, got %q", kio.ResourceListKind, r.WrappingKind)
+	}
+	var result framework.Result
+	if r.Results != nil {
+		s, err := r.Results.String()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = kyaml.Unmarshal([]byte(s), &result); err != nil {
+			return nil, nil, errors.Wrap(err, "reading results")
+		}
+	}
+	var b bytes.Buffer
+	err = kio.ByteWriter{Writer: &b}.Write(items)
+	return b.Bytes(), &result, err
+}
+
+// ReportResults writes the results of the plugin at path
+// that aren't errors, returning an error listing those that are.
+func ReportResults(w io.Writer, path string, result *framework.Result) error {
+	var errs []string
+	for _, item := range result.Items {
+		if item.Severity == framework.Error {
+			errs = append(errs, formatItem(item))
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s\n", path, formatItem(item))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf(
+		"plugin %s reported %d error(s):\n  %s",
+		path, len(errs), strings.Join(errs, "\n  "))
+}
+
+func formatItem(item framework.Item) string {
+	severity := item.Severity
+	if severity == "" {
+		severity = framework.Info
+	}
+	s := string(severity) + ": " + item.Message
+	var where []string
+	if id := ResourceRef(item); id != "" {
+		where = append(where, id)
+	}
+	if item.Field.Path != "" {
+		where = append(where, "field "+item.Field.Path)
+	}
+	if item.File.Path != "" {
+		where = append(where, "file "+item.File.Path)
+	}
+	if len(where) > 0 {
+		s += " (" + strings.Join(where, ", ") + ")"
+	}
+	return s
+}
+
+// ResourceRef names the resource the item is about, if any.
+func ResourceRef(item framework.Item) string {
+	ref := item.ResourceRef
+	if ref.Kind == "" && ref.Name == "" {
+		return ""
+	}
+	id := ref.Kind + " " + ref.Name
+	if ref.Namespace != "" {
+		id += " in namespace " + ref.Namespace
+	}
+	return strings.TrimSpace(id)
+}
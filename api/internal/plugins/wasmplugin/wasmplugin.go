@@ -0,0 +1,259 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasmplugin runs plugins compiled to WebAssembly
+// in the kustomize process, needing no container runtime,
+// yet confined as the WebAssembly sandbox confines them.
+//
+// A plugin is a WASI (snapshot preview 1) command module,
+// e.g. built by GOOS=wasip1 GOARCH=wasm go build, or for
+// the wasm32-wasi target of other toolchains.  Its ABI,
+// which this package keeps stable, is:
+//
+//   - its _start function is called once per generation
+//     or transformation, with the plugin's name as its
+//     only argument;
+//   - it reads from stdin a ResourceList holding its
+//     config as functionConfig and the resources to
+//     transform, if any, as items;
+//   - it writes to stdout a ResourceList holding the
+//     resources and optionally results, which report
+//     problems with severities, as functions do;
+//   - it fails by exiting non-zero, or writing a result
+//     of error severity;
+//   - its environment holds only ConfigStringEnv
+//     and ConfigRootEnv;
+//   - it may read, but not write, the files below the
+//     kustomization root, which is its root directory,
+//     and use the clocks and random numbers of the host;
+//     it has no other access to the host.
+package wasmplugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	"sigs.k8s.io/kustomize/api/internal/plugins/limits"
+	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	// ConfigStringEnv holds the plugin's config.
+	ConfigStringEnv = "KUSTOMIZE_PLUGIN_CONFIG_STRING"
+
+	// ConfigRootEnv holds the path, to the plugin,
+	// of the kustomization root.
+	ConfigRootEnv = "KUSTOMIZE_PLUGIN_CONFIG_ROOT"
+
+	// rootDir is the kustomization root as the plugin sees it.
+	rootDir = "/"
+
+	// pageSize is the size of a page of WebAssembly memory.
+	pageSize = 64 << 10
+
+	// maxPages is the most pages a module may have.
+	maxPages = 1 << 16
+)
+
+// cache holds the modules compiled during this process,
+// by content, sparing their recompilation on each call.
+var cache = struct {
+	sync.Mutex
+	c wazero.CompilationCache
+}{}
+
+func compilationCache() wazero.CompilationCache {
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.c == nil {
+		cache.c = wazero.NewCompilationCache()
+	}
+	return cache.c
+}
+
+// WasmPlugin is a generator or transformer
+// compiled to WebAssembly.
+type WasmPlugin struct {
+	// absolute path of the module
+	path string
+
+	// Plugin configuration data.
+	cfg []byte
+
+	h *resmap.PluginHelpers
+
+	// Bounds on the module running the plugin.
+	limits types.PluginLimits
+}
+
+func NewWasmPlugin(p string) *WasmPlugin {
+	return &WasmPlugin{path: p}
+}
+
+func (p *WasmPlugin) Path() string {
+	return p.path
+}
+
+// SetLimits bounds the module running the plugin.
+func (p *WasmPlugin) SetLimits(l types.PluginLimits) {
+	p.limits = l
+}
+
+func (p *WasmPlugin) Config(h *resmap.PluginHelpers, config []byte) error {
+	p.h = h
+	p.cfg = config
+	return nil
+}
+
+func (p *WasmPlugin) Generate() (resmap.ResMap, error) {
+	output, err := p.invokePlugin(nil)
+	if err != nil {
+		return nil, err
+	}
+	rm, err := p.h.ResmapFactory().NewResMapFromBytes(output)
+	if err != nil {
+		return nil, err
+	}
+	return utils.UpdateResourceOptions(rm)
+}
+
+func (p *WasmPlugin) Transform(rm resmap.ResMap) error {
+	// add ResIds as annotations to all objects so that we can add them back
+	inputRM, err := utils.GetResMapWithIDAnnotation(rm)
+	if err != nil {
+		return err
+	}
+	resources, err := inputRM.AsYaml()
+	if err != nil {
+		return err
+	}
+	output, err := p.invokePlugin(resources)
+	if err != nil {
+		return err
+	}
+	return utils.UpdateResMapValues(p.path, p.h, output, rm)
+}
+
+// invokePlugin runs the plugin on a ResourceList holding
+// its config and the given resources, returning the
+// resources it writes after reporting its results.
+func (p *WasmPlugin) invokePlugin(input []byte) ([]byte, error) {
+	in, err := utils.MakeResourceList(p.cfg, input)
+	if err != nil {
+		return nil, err
+	}
+	out, errRun := p.run(in)
+	items, results, err := utils.ParseResourceList(out)
+	if err != nil {
+		if errRun != nil {
+			return nil, errRun
+		}
+		return nil, errors.Wrapf(err, "reading output of plugin %s", p.path)
+	}
+	if err = utils.ReportResults(os.Stderr, p.path, results); err != nil {
+		return nil, err
+	}
+	if errRun != nil {
+		return nil, errRun
+	}
+	return items, nil
+}
+
+// run runs the module's _start function on the
+// input, within the limits, returning its output.
+func (p *WasmPlugin) run(input []byte) ([]byte, error) {
+	binary, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading plugin %s", p.path)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if p.limits.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, p.limits.Timeout)
+		defer cancel()
+	}
+	config := wazero.NewRuntimeConfig().
+		WithCompilationCache(compilationCache()).
+		WithCloseOnContextDone(true)
+	if p.limits.MaxMemory > 0 && p.limits.MaxMemory/pageSize < maxPages {
+		config = config.WithMemoryLimitPages(uint32(p.limits.MaxMemory / pageSize))
+	}
+	r := wazero.NewRuntimeWithConfig(ctx, config)
+	defer r.Close(context.Background())
+	if _, err = wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		return nil, err
+	}
+	m, err := r.CompileModule(ctx, binary)
+	if err != nil {
+		return nil, errors.Wrapf(err, "compiling plugin %s", p.path)
+	}
+
+	var stdout bytes.Buffer
+	var out io.Writer = &stdout
+	outputExceeded := false
+	if p.limits.MaxOutput > 0 {
+		out = limits.LimitWriter(out, p.limits.MaxOutput, func() {
+			outputExceeded = true
+			cancel()
+		})
+	}
+	mc := wazero.NewModuleConfig().
+		WithArgs(strings.TrimSuffix(filepath.Base(p.path), filepath.Ext(p.path))).
+		WithEnv(ConfigStringEnv, string(p.cfg)).
+		WithEnv(ConfigRootEnv, rootDir).
+		WithStdin(bytes.NewReader(input)).
+		WithStdout(out).
+		WithStderr(os.Stderr).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep()
+	fs := wazero.NewFSConfig()
+	if root := p.h.Loader().Root(); isDir(root) {
+		fs = fs.WithReadOnlyDirMount(root, rootDir)
+	}
+	mc = mc.WithFSConfig(fs)
+	_, err = r.InstantiateModule(ctx, m, mc)
+	return stdout.Bytes(), p.exitErr(err, outputExceeded, ctx.Err())
+}
+
+// exitErr returns the error the module exited with, if
+// any, telling which limit it exceeded if it was stopped.
+// Output beyond the limit is an error even if the module
+// ignored the failure to write it and exited cleanly.
+func (p *WasmPlugin) exitErr(err error, outputExceeded bool, ctxErr error) error {
+	if outputExceeded {
+		return fmt.Errorf(
+			"plugin %s wrote more than the %d bytes of output allowed",
+			p.path, p.limits.MaxOutput)
+	}
+	if err == nil {
+		return nil
+	}
+	if ctxErr == context.DeadlineExceeded {
+		return fmt.Errorf(
+			"plugin %s timed out after %v", p.path, p.limits.Timeout)
+	}
+	if e, ok := err.(*sys.ExitError); ok {
+		return fmt.Errorf(
+			"failure in plugin %s; exit status %d", p.path, e.ExitCode())
+	}
+	return errors.Wrapf(err, "failure in plugin %s", p.path)
+}
+
+func isDir(path string) bool {
+	f, err := os.Stat(path)
+	return err == nil && f.IsDir()
+}
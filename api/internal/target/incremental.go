@@ -0,0 +1,172 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"path/filepath"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/buildcache"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/accumulator"
+	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/api/internal/plugins/fnplugin"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// incremental holds what's needed to reuse bases
+// accumulated by earlier builds.
+type incremental struct {
+	cache *buildcache.Cache
+	fSys  filesys.FileSystem
+	// rec records what the target depends on.
+	rec *buildcache.Recorder
+	// key tells apart builds whose options differ.
+	key string
+	// schemas holds the plugin schemas absorbed
+	// while accumulating the target.
+	schemas *schemaLog
+}
+
+// schemaLog lists the definitions of plugin schemas,
+// which are added to the global schema as a side
+// effect of accumulating a base, so that they're
+// added again when it's reused.
+type schemaLog struct {
+	defs   []spec.Definitions
+	parent *schemaLog
+}
+
+func (l *schemaLog) add(defs spec.Definitions) {
+	for ; l != nil; l = l.parent {
+		l.defs = append(l.defs, defs)
+	}
+}
+
+// cachedBase is an accumulated base, as cached.
+type cachedBase struct {
+	ra      *accumulator.ResAccumulator
+	schemas []spec.Definitions
+}
+
+// SetCache arranges for the bases of the build to be
+// reused from the cache as long as the inputs they were
+// accumulated from are unchanged, per files read from
+// fSys, and to be put there otherwise.  The loader of
+// kt should record what it loads in rec, which is told
+// of all the build depends on.  The key tells apart
+// builds whose options differ.
+func (kt *KustTarget) SetCache(
+	c *buildcache.Cache, fSys filesys.FileSystem,
+	rec *buildcache.Recorder, key string) {
+	kt.inc = &incremental{
+		cache: c, fSys: fSys, rec: rec, key: key, schemas: &schemaLog{}}
+}
+
+// baseKey returns the key under which the base
+// at path is cached, or "" if the cache isn't used.
+func (kt *KustTarget) baseKey(path string) string {
+	if kt.inc == nil {
+		return ""
+	}
+	if _, err := git.NewRepoSpecFromUrl(path); err != nil {
+		path = filepath.Clean(filepath.Join(kt.ldr.Root(), path))
+	}
+	return kt.inc.key + "\x00" + kt.transformationsRoot + "\x00" + path
+}
+
+// reuseBase merges the base at path into ra, returning
+// true, if it's cached and unchanged.
+func (kt *KustTarget) reuseBase(
+	ra *accumulator.ResAccumulator, path string) (bool, error) {
+	key := kt.baseKey(path)
+	if key == "" {
+		return false, nil
+	}
+	v, m, found := kt.inc.cache.Get(key, kt.inc.fSys)
+	if !found {
+		return false, nil
+	}
+	base := v.(*cachedBase)
+	if err := ra.MergeAccumulator(base.ra.DeepCopy()); err != nil {
+		return false, errors.Wrapf(err, "merging cached base '%s'", path)
+	}
+	kt.inc.rec.Include(m)
+	for _, defs := range base.schemas {
+		kt.inc.schemas.add(defs)
+		if err := kt.schemas.add(defs); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// newSubTarget returns the target of the kustomization
+// ldr is rooted at, and, if key isn't empty, a function
+// caching what it accumulates under the key.
+func (kt *KustTarget) newSubTarget(ldr ifc.Loader, key string) (
+	*KustTarget, func(*accumulator.ResAccumulator)) {
+	if kt.inc == nil || key == "" {
+		subKt := NewKustTarget(ldr, kt.validator, kt.rFactory, kt.pLdr)
+		subKt.inc = kt.inc
+		subKt.schemas = kt.schemas
+		return subKt, func(*accumulator.ResAccumulator) {}
+	}
+	inc := *kt.inc
+	inc.rec = kt.inc.rec.Child()
+	inc.schemas = &schemaLog{parent: kt.inc.schemas}
+	subKt := NewKustTarget(inc.rec.Loader(ldr), kt.validator, kt.rFactory, kt.pLdr)
+	subKt.inc = &inc
+	subKt.schemas = kt.schemas
+	return subKt, func(ra *accumulator.ResAccumulator) {
+		inc.cache.Put(key, inc.rec.Manifest(), &cachedBase{
+			ra: ra.DeepCopy(), schemas: inc.schemas.defs})
+	}
+}
+
+// recordPlugins records what the plugins
+// configured in configs are loaded from.
+func (kt *KustTarget) recordPlugins(configs resmap.ResMap) {
+	if kt.inc == nil {
+		return
+	}
+	for _, res := range configs.Resources() {
+		for _, path := range kt.pLdr.PluginFiles(res) {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(kt.ldr.Root(), path)
+			}
+			kt.inc.rec.Plugin(path)
+		}
+		if name, ok := res.PeekMap()["chartName"].(string); ok {
+			version, _ := res.PeekMap()["chartVersion"].(string)
+			kt.inc.rec.Chart(name, version)
+		}
+		fn := fnplugin.GetFunctionSpec(res)
+		if fn == nil {
+			continue
+		}
+		if fn.Container.Image != "" {
+			kt.inc.rec.Image(fn.Container.Image)
+		}
+		if fn.Container.Network.Required {
+			kt.inc.rec.Volatile("network of " + fn.Container.Image)
+		}
+		if len(fn.Container.StorageMounts) > 0 || len(fn.StorageMounts) > 0 {
+			kt.inc.rec.Volatile("mounts of " + fn.Container.Image)
+		}
+		if fn.Starlark.URL != "" {
+			kt.inc.rec.Volatile(fn.Starlark.URL)
+		}
+	}
+}
+
+// recordSchemas notes the definitions of plugin
+// schemas added to the global schema.
+func (kt *KustTarget) recordSchemas(defs spec.Definitions) {
+	if kt.inc != nil {
+		kt.inc.schemas.add(defs)
+	}
+}
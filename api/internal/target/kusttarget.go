@@ -6,18 +6,26 @@ package target
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"reflect"
 	"strings"
 
+	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/builtins"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/accumulator"
+	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/api/internal/kusterr"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinhelpers"
 	"sigs.k8s.io/kustomize/api/internal/plugins/loader"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/profile"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/trace"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
 	"sigs.k8s.io/yaml"
 )
 
@@ -28,6 +36,22 @@ type KustTarget struct {
 	validator     ifc.Validator
 	rFactory      *resmap.Factory
 	pLdr          *loader.Loader
+	profile       *profile.Profile
+	trace         *trace.Trace
+	validation    *types.ValidationReport
+	failOn        types.Severity
+	strictPatch   bool
+	// kustFile is the path of the kustomization file, once loaded.
+	kustFile string
+	// transformationsRoot is the root of the build, if its
+	// kustomization asks for transformerAnnotations or
+	// originAnnotations, which both need the generators
+	// and transformers recorded.
+	transformationsRoot string
+	// inc is set when bases are reused from a cache.
+	inc *incremental
+	// schemas is shared by the targets of a build.
+	schemas *schemaScope
 }
 
 // NewKustTarget returns a new instance of KustTarget.
@@ -41,30 +65,70 @@ func NewKustTarget(
 		validator: validator,
 		rFactory:  rFactory,
 		pLdr:      pLdr,
+		schemas:   &schemaScope{},
 	}
 }
 
+// SetProfile arranges for the time spent in the phases
+// of the build to be recorded in the given profile.
+// The profile may be nil.
+func (kt *KustTarget) SetProfile(p *profile.Profile) {
+	kt.profile = p
+}
+
+// SetTrace arranges for the changes made by each
+// transformer to be recorded in the given trace.
+// The trace may be nil.
+func (kt *KustTarget) SetTrace(t *trace.Trace) {
+	kt.trace = t
+}
+
+// SetValidation arranges for the findings of the
+// validators to be added to the given report, which
+// may be nil, and for the build to fail on findings
+// at least as severe as failOn (error, if empty).
+func (kt *KustTarget) SetValidation(
+	report *types.ValidationReport, failOn types.Severity) {
+	kt.validation = report
+	kt.failOn = failOn
+}
+
+// SetStrictPatch arranges for strategic merge patches to
+// fail if they set fields unknown to the schema of the
+// kind of what they patch, or if that schema is unknown.
+func (kt *KustTarget) SetStrictPatch(strict bool) {
+	kt.strictPatch = strict
+}
+
 // Load attempts to load the target's kustomization file.
 func (kt *KustTarget) Load() error {
-	content, err := loadKustFile(kt.ldr)
+	defer kt.profile.Start(profile.PhaseLoad, kt.ldr.Root(), "")()
+	content, name, err := loadKustFile(kt.ldr)
 	if err != nil {
 		return err
 	}
+	kt.kustFile = filepath.Join(kt.ldr.Root(), name)
+	loc := types.ErrorLocation{File: kt.kustFile}
 	content, err = types.FixKustomizationPreUnmarshalling(content)
 	if err != nil {
-		return err
+		return types.NewErrLocated(types.NewErrBuild(types.BuildErrorParse, err), loc)
 	}
 	var k types.Kustomization
 	err = k.Unmarshal(content)
 	if err != nil {
-		return err
+		loc.FieldPath = unknownField(err)
+		return types.NewErrLocated(types.NewErrBuild(types.BuildErrorParse, err), loc)
 	}
 	k.FixKustomizationPostUnmarshalling()
-	errs := k.EnforceFields()
-	if len(errs) > 0 {
-		return fmt.Errorf(
-			"Failed to read kustomization file under %s:\n"+
-				strings.Join(errs, "\n"), kt.ldr.Root())
+	if errs := k.EnforceFields(); len(errs) > 0 {
+		var located []error
+		for _, e := range errs {
+			// Each message starts with the name of the field.
+			located = append(located, types.NewErrLocated(errors.New(e), types.ErrorLocation{
+				File: kt.kustFile, FieldPath: strings.Fields(e)[0]}))
+		}
+		return types.NewErrLocated(types.NewErrBuild(types.BuildErrorParse, types.NewErrAggregate(
+			"Failed to read kustomization file under "+kt.ldr.Root(), located)), loc)
 	}
 	kt.kustomization = &k
 	return nil
@@ -78,24 +142,37 @@ func (kt *KustTarget) Kustomization() types.Kustomization {
 	return result
 }
 
-func loadKustFile(ldr ifc.Loader) ([]byte, error) {
+// unknownField returns the name of the field the error
+// complains about, if it's an unknown field error.
+func unknownField(err error) string {
+	const prefix = "json: unknown field "
+	if m := err.Error(); strings.HasPrefix(m, prefix) {
+		return strings.Trim(m[len(prefix):], `"`)
+	}
+	return ""
+}
+
+func loadKustFile(ldr ifc.Loader) ([]byte, string, error) {
 	var content []byte
+	var name string
 	match := 0
 	for _, kf := range konfig.RecognizedKustomizationFileNames() {
 		c, err := ldr.Load(kf)
 		if err == nil {
 			match += 1
 			content = c
+			name = kf
 		}
 	}
 	switch match {
 	case 0:
-		return nil, NewErrMissingKustomization(ldr.Root())
+		return nil, "", types.NewErrBuild(
+			types.BuildErrorMissingFile, NewErrMissingKustomization(ldr.Root()))
 	case 1:
-		return content, nil
+		return content, name, nil
 	default:
-		return nil, fmt.Errorf(
-			"Found multiple kustomization files under: %s\n", ldr.Root())
+		return nil, "", types.NewErrBuild(types.BuildErrorParse, fmt.Errorf(
+			"Found multiple kustomization files under: %s\n", ldr.Root()))
 	}
 }
 
@@ -106,6 +183,10 @@ func (kt *KustTarget) MakeCustomizedResMap() (resmap.ResMap, error) {
 }
 
 func (kt *KustTarget) makeCustomizedResMap() (resmap.ResMap, error) {
+	if kt.kustomization.HasBuildMetadata(types.TransformerAnnotations) ||
+		kt.kustomization.HasBuildMetadata(types.OriginAnnotations) {
+		kt.transformationsRoot = kt.ldr.Root()
+	}
 	ra, err := kt.AccumulateTarget()
 	if err != nil {
 		return nil, err
@@ -113,25 +194,57 @@ func (kt *KustTarget) makeCustomizedResMap() (resmap.ResMap, error) {
 
 	// The following steps must be done last, not as part of
 	// the recursion implicit in AccumulateTarget.
+	defer kt.profile.Start(profile.PhaseFinalize, kt.ldr.Root(), "")()
 
+	record := kt.trace.Start(kt.ldr.Root(), "HashTransformer", ra.ResMap())
+	note := kt.startTransformation("HashTransformer", ra.ResMap())
 	err = kt.addHashesToNames(ra)
+	note()
+	record()
 	if err != nil {
 		return nil, err
 	}
 
 	// Given that names have changed (prefixs/suffixes added),
 	// fix all the back references to those names.
+	record = kt.trace.Start(kt.ldr.Root(), "NameReferenceTransformer", ra.ResMap())
+	note = kt.startTransformation("NameReferenceTransformer", ra.ResMap())
 	err = ra.FixBackReferences()
+	note()
+	record()
 	if err != nil {
 		return nil, err
 	}
 
 	// With all the back references fixed, it's OK to resolve Vars.
+	record = kt.trace.Start(kt.ldr.Root(), "RefVarTransformer", ra.ResMap())
+	note = kt.startTransformation("RefVarTransformer", ra.ResMap())
 	err = ra.ResolveVars()
+	note()
+	record()
 	if err != nil {
 		return nil, err
 	}
 
+	// Validators see the resources as they're output.
+	err = kt.runValidators(ra)
+	if err != nil {
+		return nil, err
+	}
+
+	if kt.kustomization.HasBuildMetadata(types.OriginAnnotations) {
+		err = kt.annotateOrigins(ra.ResMap())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if kt.kustomization.HasBuildMetadata(types.TransformerAnnotations) {
+		err = kt.annotateTransformations(ra.ResMap())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return ra.ResMap(), nil
 }
 
@@ -158,13 +271,14 @@ func (kt *KustTarget) AccumulateTarget() (
 // (or empty if the Component does not have a parent).
 func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 	resRa *accumulator.ResAccumulator, err error) {
+	defer kt.profile.Start(profile.PhaseKustomization, kt.ldr.Root(), "")()
 	ra, err = kt.accumulateResources(ra, kt.kustomization.Resources)
 	if err != nil {
-		return nil, errors.Wrap(err, "accumulating resources")
+		return nil, kt.locate(errors.Wrap(err, "accumulating resources"), "resources")
 	}
 	ra, err = kt.accumulateComponents(ra, kt.kustomization.Components)
 	if err != nil {
-		return nil, errors.Wrap(err, "accumulating components")
+		return nil, kt.locate(errors.Wrap(err, "accumulating components"), "components")
 	}
 	tConfig, err := builtinconfig.MakeTransformerConfig(
 		kt.ldr, kt.kustomization.Configurations)
@@ -186,6 +300,16 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 		return nil, errors.Wrapf(
 			err, "merging CRDs %v", crdTc)
 	}
+	crdDefs, err := accumulator.LoadSchemasFromCRDs(kt.ldr, kt.kustomization.Crds)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "loading CRD schemas %v", kt.kustomization.Crds)
+	}
+	if len(crdDefs) > 0 {
+		if err = kt.schemas.add(crdDefs); err != nil {
+			return nil, err
+		}
+	}
 	err = kt.runGenerators(ra)
 	if err != nil {
 		return nil, err
@@ -194,7 +318,7 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 	if err != nil {
 		return nil, err
 	}
-	err = kt.runValidators(ra)
+	err = kt.configureValidators(ra)
 	if err != nil {
 		return nil, err
 	}
@@ -208,22 +332,27 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 
 func (kt *KustTarget) runGenerators(
 	ra *accumulator.ResAccumulator) error {
-	var generators []resmap.Generator
-	gs, err := kt.configureBuiltinGenerators()
+	bgs, err := kt.configureBuiltinGenerators()
 	if err != nil {
 		return err
 	}
-	generators = append(generators, gs...)
-	gs, err = kt.configureExternalGenerators()
+	gs, err := kt.configureExternalGenerators(ra)
 	if err != nil {
-		return errors.Wrap(err, "loading generator plugins")
+		return kt.locate(errors.Wrap(err, "loading generator plugins"), "generators")
 	}
-	generators = append(generators, gs...)
-	for _, g := range generators {
+	generators := append(bgs, gs...)
+	for i, g := range generators {
+		stop := kt.profile.Start(profile.PhaseGenerator, kt.ldr.Root(), pluginName(g))
 		resMap, err := g.Generate()
+		stop()
 		if err != nil {
+			if i >= len(bgs) {
+				return types.NewErrBuild(types.BuildErrorPlugin, err)
+			}
 			return err
 		}
+		kt.setMissingOrigins(resMap, kt.ldr.Root())
+		kt.recordGeneration(pluginName(g), resMap)
 		err = ra.AbsorbAll(resMap)
 		if err != nil {
 			return errors.Wrapf(err, "merging from generator %v", g)
@@ -232,60 +361,189 @@ func (kt *KustTarget) runGenerators(
 	return nil
 }
 
-func (kt *KustTarget) configureExternalGenerators() ([]resmap.Generator, error) {
-	ra := accumulator.MakeEmptyAccumulator()
-	ra, err := kt.accumulateResources(ra, kt.kustomization.Generators)
+// configureExternalGenerators loads the generator plugins,
+// merging the schemas they declare into ra.
+func (kt *KustTarget) configureExternalGenerators(
+	ra *accumulator.ResAccumulator) ([]resmap.Generator, error) {
+	pa := accumulator.MakeEmptyAccumulator()
+	pa, err := kt.accumulateResources(pa, kt.kustomization.Generators)
 	if err != nil {
 		return nil, err
 	}
-	return kt.pLdr.LoadGenerators(kt.ldr, kt.validator, ra.ResMap())
+	gs, err := kt.pLdr.LoadGenerators(kt.ldr, kt.validator, pa.ResMap())
+	if err != nil {
+		return nil, types.NewErrBuild(types.BuildErrorPlugin, err)
+	}
+	kt.recordPlugins(pa.ResMap())
+	return gs, kt.absorbSchemas(ra, pa.ResMap())
 }
 
 func (kt *KustTarget) runTransformers(ra *accumulator.ResAccumulator) error {
-	var r []resmap.Transformer
+	// The transformer plugins are loaded first, so that the
+	// builtins are configured with the schemas they declare.
+	lts, err := kt.configureExternalTransformers(ra, kt.kustomization.Transformers)
+	if err != nil {
+		return kt.locate(err, "transformers")
+	}
 	tConfig := ra.GetTransformerConfig()
-	lts, err := kt.configureBuiltinTransformers(tConfig)
+	bts, err := kt.configureBuiltinTransformers(tConfig)
 	if err != nil {
 		return err
 	}
-	r = append(r, lts...)
-	lts, err = kt.configureExternalTransformers(kt.kustomization.Transformers)
+	mts, err := kt.configurePipeline(kt.pipelineMutators())
 	if err != nil {
-		return err
+		return kt.locate(err, "pipeline")
+	}
+	r := append(append(bts, lts...), mts...)
+	for i, t := range r {
+		stop := kt.profile.Start(profile.PhaseTransformer, kt.ldr.Root(), pluginName(t))
+		record := kt.trace.Start(kt.ldr.Root(), pluginName(t), ra.ResMap())
+		note := kt.startTransformation(pluginName(t), ra.ResMap())
+		err = ra.Transform(t)
+		note()
+		record()
+		stop()
+		if err != nil {
+			if i >= len(bts) {
+				return types.NewErrBuild(types.BuildErrorPlugin, err)
+			}
+			return err
+		}
 	}
-	r = append(r, lts...)
-	return ra.Transform(newMultiTransformer(r))
+	return nil
 }
 
-func (kt *KustTarget) configureExternalTransformers(transformers []string) ([]resmap.Transformer, error) {
-	ra := accumulator.MakeEmptyAccumulator()
-	ra, err := kt.accumulateResources(ra, transformers)
+// configureExternalTransformers loads the transformer plugins,
+// merging the schemas they declare into ra.
+func (kt *KustTarget) configureExternalTransformers(
+	ra *accumulator.ResAccumulator, transformers []string) ([]resmap.Transformer, error) {
+	pa := accumulator.MakeEmptyAccumulator()
+	pa, err := kt.accumulateResources(pa, transformers)
 
 	if err != nil {
 		return nil, err
 	}
-	return kt.pLdr.LoadTransformers(kt.ldr, kt.validator, ra.ResMap())
+	ts, err := kt.pLdr.LoadTransformers(kt.ldr, kt.validator, pa.ResMap())
+	if err != nil {
+		return nil, types.NewErrBuild(types.BuildErrorPlugin, err)
+	}
+	kt.recordPlugins(pa.ResMap())
+	return ts, kt.absorbSchemas(ra, pa.ResMap())
 }
 
-func (kt *KustTarget) runValidators(ra *accumulator.ResAccumulator) error {
-	validators, err := kt.configureExternalTransformers(kt.kustomization.Validators)
+// absorbSchemas merges the OpenAPI definitions declared by
+// the plugins configured in configs into the transformer
+// config of ra, for name references and such, and into
+// the global schema, for strategic merge patches, until
+// the build is done.
+func (kt *KustTarget) absorbSchemas(
+	ra *accumulator.ResAccumulator, configs resmap.ResMap) error {
+	defs, err := kt.pLdr.LoadSchemas(configs)
 	if err != nil {
-		return err
+		return types.NewErrBuild(types.BuildErrorPlugin, err)
+	}
+	if len(defs) == 0 {
+		return nil
+	}
+	tc, err := accumulator.LoadConfigFromSchema(defs)
+	if err != nil {
+		return errors.Wrap(err, "loading plugin schemas")
+	}
+	if err = ra.MergeConfig(tc); err != nil {
+		return errors.Wrapf(err, "merging plugin schemas %v", tc)
+	}
+	if err = kt.schemas.add(defs); err != nil {
+		return types.NewErrBuild(types.BuildErrorPlugin, err)
 	}
-	for _, v := range validators {
-		// Validators shouldn't modify the resource map
-		orignal := ra.ResMap().DeepCopy()
-		err = v.Transform(ra.ResMap())
+	kt.recordSchemas(defs)
+	return nil
+}
+
+// schemaScope holds the functions restoring the global
+// schema as it was before plugin and CRD schemas were
+// added to it, for strategic merge patches, during a build.
+type schemaScope struct {
+	restores []func()
+}
+
+func (s *schemaScope) add(defs spec.Definitions) error {
+	restore, err := openapi.AddDefinitionsScoped(defs)
+	if err != nil {
+		return errors.Wrap(err, "adding schemas")
+	}
+	s.restores = append(s.restores, restore)
+	return nil
+}
+
+// RestoreSchemas removes the plugin and CRD schemas added
+// to the global schema by the build, so that they don't
+// leak into later builds.  Call it once the build is done.
+func (kt *KustTarget) RestoreSchemas() {
+	r := kt.schemas.restores
+	for i := len(r) - 1; i >= 0; i-- {
+		r[i]()
+	}
+	kt.schemas.restores = nil
+}
+
+// configureValidators adds the validators of the
+// kustomization to the accumulator, to run once the
+// build is otherwise done.
+func (kt *KustTarget) configureValidators(ra *accumulator.ResAccumulator) error {
+	validators, err := kt.configureExternalTransformers(ra, kt.kustomization.Validators)
+	if err != nil {
+		return kt.locate(err, "validators")
+	}
+	pvs, err := kt.configurePipeline(kt.pipelineValidators())
+	if err != nil {
+		return kt.locate(err, "pipeline")
+	}
+	ra.AddValidators(validators...)
+	ra.AddValidators(pvs...)
+	return nil
+}
+
+// runValidators runs the accumulated validators,
+// failing if any finding is at least as severe
+// as the threshold.
+func (kt *KustTarget) runValidators(ra *accumulator.ResAccumulator) error {
+	var report types.ValidationReport
+	for _, v := range ra.Validators() {
+		stop := kt.profile.Start(profile.PhaseValidator, kt.ldr.Root(), pluginName(v))
+		findings, err := kt.validate(v, ra.ResMap())
+		stop()
 		if err != nil {
-			return err
-		}
-		new := ra.ResMap().DeepCopy()
-		kt.removeValidatedByLabel(new)
-		if err = orignal.ErrorIfNotEqualSets(new); err != nil {
-			return fmt.Errorf("validator shouldn't modify the resource map: %v", err)
+			return types.NewErrBuild(types.BuildErrorValidation, err)
 		}
+		report.Add(pluginName(v), findings)
 	}
-	return nil
+	if kt.validation != nil {
+		kt.validation.Findings = append(kt.validation.Findings, report.Findings...)
+	}
+	return types.NewErrBuild(
+		types.BuildErrorValidation, report.ErrIfAtLeast(kt.failOn))
+}
+
+// validate runs the validator on a copy of the resources,
+// so that it can't modify them.  A validator that's
+// only a transformer reports a problem by failing.
+func (kt *KustTarget) validate(
+	v resmap.Transformer, m resmap.ResMap) ([]types.Finding, error) {
+	c := m.DeepCopy()
+	if x, ok := v.(resmap.Validator); ok {
+		return x.Validate(c)
+	}
+	if err := v.Transform(c); err != nil {
+		return []types.Finding{{
+			Severity: types.SeverityError,
+			Message:  err.Error(),
+		}}, nil
+	}
+	kt.removeValidatedByLabel(c)
+	if err := m.ErrorIfNotEqualSets(c); err != nil {
+		return nil, fmt.Errorf("validator shouldn't modify the resource map: %v", err)
+	}
+	return nil, nil
 }
 
 func (kt *KustTarget) removeValidatedByLabel(rm resmap.ResMap) {
@@ -311,14 +569,25 @@ func (kt *KustTarget) accumulateResources(
 	for _, path := range paths {
 		// try loading resource as file then as base (directory or git repository)
 		if errF := kt.accumulateFile(ra, path); errF != nil {
-			ldr, errL := kt.ldr.New(path)
+			reused, err := kt.reuseBase(ra, path)
+			if err != nil {
+				return nil, err
+			}
+			if reused {
+				continue
+			}
+			ldr, errL := kt.newLoader(path)
 			if errL != nil {
-				return nil, fmt.Errorf("accumulateFile %q, loader.New %q", errF, errL)
+				return nil, embedCauses(
+					fmt.Errorf("accumulateFile %q, loader.New %q", errF, errL),
+					resourceErrorKind(path, errF, errL), errL, errF)
 			}
 			var errD error
-			ra, errD = kt.accumulateDirectory(ra, ldr, false)
+			ra, errD = kt.accumulateDirectory(ra, ldr, false, kt.baseKey(path))
 			if errD != nil {
-				return nil, fmt.Errorf("accumulateFile %q, accumulateDirector: %q", errF, errD)
+				return nil, embedCauses(
+					fmt.Errorf("accumulateFile %q, accumulateDirector: %q", errF, errD),
+					types.BuildErrorKindOf(errD), errD)
 			}
 		}
 	}
@@ -331,23 +600,35 @@ func (kt *KustTarget) accumulateComponents(
 	ra *accumulator.ResAccumulator, paths []string) (*accumulator.ResAccumulator, error) {
 	for _, path := range paths {
 		// Components always refer to directories
-		ldr, errL := kt.ldr.New(path)
+		ldr, errL := kt.newLoader(path)
 		if errL != nil {
-			return nil, fmt.Errorf("loader.New %q", errL)
+			return nil, embedCauses(
+				fmt.Errorf("loader.New %q", errL),
+				resourceErrorKind(path, nil, errL), errL)
 		}
 		var errD error
-		ra, errD = kt.accumulateDirectory(ra, ldr, true)
+		ra, errD = kt.accumulateDirectory(ra, ldr, true, "")
 		if errD != nil {
-			return nil, fmt.Errorf("accumulateDirectory: %q", errD)
+			return nil, embedCauses(
+				fmt.Errorf("accumulateDirectory: %q", errD),
+				types.BuildErrorKindOf(errD), errD)
 		}
 	}
 	return ra, nil
 }
 
+// accumulateDirectory merges the kustomization ldr is
+// rooted at into ra, caching what it accumulates under
+// key, if not empty.
 func (kt *KustTarget) accumulateDirectory(
-	ra *accumulator.ResAccumulator, ldr ifc.Loader, isComponent bool) (*accumulator.ResAccumulator, error) {
+	ra *accumulator.ResAccumulator, ldr ifc.Loader, isComponent bool,
+	key string) (*accumulator.ResAccumulator, error) {
 	defer ldr.Cleanup()
-	subKt := NewKustTarget(ldr, kt.validator, kt.rFactory, kt.pLdr)
+	subKt, put := kt.newSubTarget(ldr, key)
+	subKt.SetProfile(kt.profile)
+	subKt.SetTrace(kt.trace)
+	subKt.SetStrictPatch(kt.strictPatch)
+	subKt.transformationsRoot = kt.transformationsRoot
 	err := subKt.Load()
 	if err != nil {
 		return nil, errors.Wrapf(
@@ -375,6 +656,7 @@ func (kt *KustTarget) accumulateDirectory(
 		return nil, errors.Wrapf(
 			err, "recursed accumulation of path '%s'", ldr.Root())
 	}
+	put(subRa)
 	err = ra.MergeAccumulator(subRa)
 	if err != nil {
 		return nil, errors.Wrapf(
@@ -383,19 +665,98 @@ func (kt *KustTarget) accumulateDirectory(
 	return ra, nil
 }
 
+// newLoader returns a loader rooted at the given path,
+// recording the time taken if the path is a remote base.
+func (kt *KustTarget) newLoader(path string) (ifc.Loader, error) {
+	if _, err := git.NewRepoSpecFromUrl(path); err == nil {
+		defer kt.profile.Start(profile.PhaseFetch, kt.ldr.Root(), path)()
+	}
+	return kt.ldr.New(path)
+}
+
+// locate attributes the error to the given field
+// of the kustomization file, unless it already has
+// a more precise location.
+func (kt *KustTarget) locate(err error, field string) error {
+	return types.NewErrLocated(err, types.ErrorLocation{
+		File: kt.kustFile, FieldPath: field})
+}
+
+// embedCauses classifies err, whose message embeds the
+// given causes, losing their chain, and gives it the
+// location of the first of the causes having one.
+func embedCauses(err error, k types.BuildErrorKind, causes ...error) error {
+	for _, c := range causes {
+		if loc := types.ErrorLocationOf(c); loc != (types.ErrorLocation{}) {
+			return types.NewErrLocated(types.NewErrBuild(k, err), loc)
+		}
+	}
+	return types.NewErrBuild(k, err)
+}
+
+// resourceErrorKind classifies the failure to read a path
+// both as a file (errF) and as a kustomization root (errL).
+func resourceErrorKind(path string, errF, errL error) types.BuildErrorKind {
+	if k := types.BuildErrorKindOf(errL); k != types.BuildErrorUnknown {
+		return k
+	}
+	if _, err := git.NewRepoSpecFromUrl(path); err == nil {
+		return types.BuildErrorRemoteFetch
+	}
+	if k := types.BuildErrorKindOf(errF); k != types.BuildErrorUnknown {
+		return k
+	}
+	return types.BuildErrorMissingFile
+}
+
+// pluginName returns a short name for a generator or
+// transformer, for use in reports.
+func pluginName(p interface{}) string {
+	if x, ok := p.(interface{ Path() string }); ok {
+		return filepath.Base(x.Path())
+	}
+	t := reflect.TypeOf(p)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.TrimSuffix(t.Name(), "Plugin")
+}
+
 func (kt *KustTarget) accumulateFile(
 	ra *accumulator.ResAccumulator, path string) error {
-	resources, err := kt.rFactory.FromFile(kt.ldr, path)
+	content, err := kt.ldr.Load(path)
 	if err != nil {
 		return errors.Wrapf(err, "accumulating resources from '%s'", path)
 	}
+	origin := path
+	if !filepath.IsAbs(origin) {
+		origin = filepath.Join(kt.ldr.Root(), path)
+	}
+	loc := types.ErrorLocation{File: origin}
+	resources, err := kt.rFactory.NewResMapFromBytes(content)
+	if err != nil {
+		return types.NewErrLocated(types.NewErrBuild(types.BuildErrorParse, errors.Wrapf(
+			kusterr.Handler(err, path), "accumulating resources from '%s'", path)), loc)
+	}
+	kt.setMissingOrigins(resources, origin)
 	err = ra.AppendAll(resources)
 	if err != nil {
-		return errors.Wrapf(err, "merging resources from '%s'", path)
+		return types.NewErrLocated(
+			errors.Wrapf(err, "merging resources from '%s'", path), loc)
 	}
 	return nil
 }
 
+// setMissingOrigins records the given origin on
+// each resource that doesn't already have one.
+func (kt *KustTarget) setMissingOrigins(m resmap.ResMap, origin string) {
+	for _, r := range m.Resources() {
+		if r.GetOrigin() == "" {
+			r.SetOrigin(origin)
+		}
+	}
+}
+
 func (kt *KustTarget) configureBuiltinPlugin(
 	p resmap.Configurable, c interface{}, bpt builtinhelpers.BuiltinPluginType) (err error) {
 	var y []byte
@@ -167,8 +167,10 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		var c struct {
 			Paths   []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
 			Patches string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+			Strict  bool                        `json:"strict,omitempty" yaml:"strict,omitempty"`
 		}
 		c.Paths = kt.kustomization.PatchesStrategicMerge
+		c.Strict = kt.strictPatch
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
 		if err != nil {
@@ -187,7 +189,9 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			Path   string          `json:"path,omitempty" yaml:"path,omitempty"`
 			Patch  string          `json:"patch,omitempty" yaml:"patch,omitempty"`
 			Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+			Strict bool            `json:"strict,omitempty" yaml:"strict,omitempty"`
 		}
+		c.Strict = kt.strictPatch
 		for _, pc := range kt.kustomization.Patches {
 			c.Target = pc.Target
 			c.Patch = pc.Patch
@@ -0,0 +1,105 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// pipelineFunctionName names the ConfigMaps made
+// for functions configured with a configMap.
+const pipelineFunctionName = "function-config"
+
+// pipelineMutators returns the mutators of the
+// kustomization's pipeline, if any.
+func (kt *KustTarget) pipelineMutators() []types.Function {
+	if kt.kustomization.Pipeline == nil {
+		return nil
+	}
+	return kt.kustomization.Pipeline.Mutators
+}
+
+// pipelineValidators returns the validators of the
+// kustomization's pipeline, if any.
+func (kt *KustTarget) pipelineValidators() []types.Function {
+	if kt.kustomization.Pipeline == nil {
+		return nil
+	}
+	return kt.kustomization.Pipeline.Validators
+}
+
+// configurePipeline loads the functions as transformer
+// plugins, so they run under the plugin restrictions
+// like functions listed among the transformers.
+func (kt *KustTarget) configurePipeline(
+	fns []types.Function) ([]resmap.Transformer, error) {
+	var result []resmap.Transformer
+	for _, f := range fns {
+		config, err := kt.functionConfig(f)
+		if err != nil {
+			return nil, err
+		}
+		ts, err := kt.pLdr.LoadTransformers(kt.ldr, kt.validator, config)
+		if err != nil {
+			return nil, types.NewErrBuild(types.BuildErrorPlugin, err)
+		}
+		kt.recordPlugins(config)
+		result = append(result, ts...)
+	}
+	return result, nil
+}
+
+// functionConfig returns the config of the function,
+// annotated to run the function's image.
+func (kt *KustTarget) functionConfig(f types.Function) (resmap.ResMap, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	var node *yaml.RNode
+	var err error
+	if f.ConfigPath != "" {
+		content, err := kt.ldr.Load(f.ConfigPath)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "loading config of pipeline function %s", f.Image)
+		}
+		node, err = yaml.Parse(string(content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", f.ConfigPath)
+		}
+	} else {
+		node, err = yaml.Parse(
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " +
+				pipelineFunctionName + "\n")
+		if err != nil {
+			return nil, err
+		}
+		if len(f.ConfigMap) > 0 {
+			if err = node.PipeE(yaml.SetField(
+				"data", yaml.NewMapRNode(&f.ConfigMap))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// marshalled, lest the image inject fields into the spec
+	spec, err := yaml.Marshal(runtimeutil.FunctionSpec{
+		Container: runtimeutil.ContainerSpec{Image: f.Image}})
+	if err != nil {
+		return nil, err
+	}
+	err = node.PipeE(yaml.SetAnnotation(
+		runtimeutil.FunctionAnnotationKey, string(spec)))
+	if err != nil {
+		return nil, err
+	}
+	content, err := node.String()
+	if err != nil {
+		return nil, err
+	}
+	return kt.rFactory.NewResMapFromBytes([]byte(content))
+}
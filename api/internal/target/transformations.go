@@ -0,0 +1,130 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// recordsTransformations returns whether the build
+// records the generators and transformers that made
+// or changed each resource.
+func (kt *KustTarget) recordsTransformations() bool {
+	return kt.transformationsRoot != ""
+}
+
+// transformation returns the record of the named
+// generator or transformer running in this target, with
+// the root made relative to the root of the build.
+func (kt *KustTarget) transformation(name string) resource.Transformation {
+	return resource.Transformation{
+		ConfiguredIn: kt.relativePath(kt.ldr.Root()), ConfiguredBy: name}
+}
+
+// relativePath returns the path relative to the root of
+// the build, separated by slashes on all platforms.
+func (kt *KustTarget) relativePath(path string) string {
+	if rel, err := filepath.Rel(kt.transformationsRoot, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
+
+// recordGeneration records that the named generator made the resources.
+func (kt *KustTarget) recordGeneration(name string, m resmap.ResMap) {
+	if !kt.recordsTransformations() {
+		return
+	}
+	t := kt.transformation(name)
+	for _, r := range m.Resources() {
+		r.AddTransformation(t)
+	}
+}
+
+// startTransformation notes the resources, returning a
+// function that records the named transformer on each
+// resource it since made or changed.
+func (kt *KustTarget) startTransformation(name string, m resmap.ResMap) func() {
+	if !kt.recordsTransformations() {
+		return func() {}
+	}
+	before := make(map[*resource.Resource]*resource.Resource)
+	for _, r := range m.Resources() {
+		before[r] = r.DeepCopy()
+	}
+	return func() {
+		t := kt.transformation(name)
+		for _, r := range m.Resources() {
+			if old, ok := before[r]; !ok || !old.KunstructEqual(r) {
+				r.AddTransformation(t)
+			}
+		}
+	}
+}
+
+// annotateTransformations writes the recorded transformations
+// of each resource to its transformations annotation.
+func (kt *KustTarget) annotateTransformations(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		ts := r.GetTransformations()
+		if len(ts) == 0 {
+			continue
+		}
+		if err := annotate(r, konfig.TransformationsAnnotationKey, ts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// origin is where a resource came from: the file it was
+// read from, or else the generator, or transformer, that
+// made it.
+type origin struct {
+	Path string `json:"path,omitempty"`
+	*resource.Transformation
+}
+
+// annotateOrigins writes the origin of each
+// resource to its origin annotation.
+func (kt *KustTarget) annotateOrigins(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		var o origin
+		path, ts := r.GetOrigin(), r.GetTransformations()
+		switch {
+		case len(ts) > 0 && (path == "" || kt.relativePath(path) == ts[0].ConfiguredIn):
+			// Generated, the origin being the root of
+			// the generator's kustomization, if any.
+			o.Transformation = &ts[0]
+		case path != "":
+			o.Path = kt.relativePath(path)
+		default:
+			continue
+		}
+		if err := annotate(r, konfig.OriginAnnotationKey, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// annotate sets the annotation to the value, as YAML.
+func annotate(r *resource.Resource, key string, value interface{}) error {
+	y, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	annotations := r.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = string(y)
+	r.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package wrappy
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Conversions records the conversions of WNodes from JSON
+// that lose something of the node they replace, i.e. the
+// order of its fields, its comments or the style of its
+// values, so that the code still converting resources can
+// be found and moved to the kyaml path.
+// It's safe for concurrent use.
+type Conversions struct {
+	mu    sync.Mutex
+	lossy []string
+}
+
+// Lossy returns the lossy conversions recorded, in order.
+func (c *Conversions) Lossy() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.lossy...)
+}
+
+// check records the conversion of the node, if
+// sending it through JSON doesn't give it back.
+func (c *Conversions) check(op string, node *yaml.RNode) {
+	if c == nil || yaml.IsMissingOrNull(node) {
+		return
+	}
+	before, err := node.String()
+	if err != nil {
+		return
+	}
+	after, err := roundTrip(node)
+	if err == nil && after == before {
+		return
+	}
+	meta, _ := node.GetMeta()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lossy = append(c.lossy, fmt.Sprintf(
+		"%s of %s %s by %s", op, meta.Kind, meta.Name, caller()))
+}
+
+// roundTrip returns the node sent through JSON,
+// as a string.
+func roundTrip(node *yaml.RNode) (string, error) {
+	data, err := node.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	n, err := parseJSON(data)
+	if err != nil {
+		return "", err
+	}
+	return n.String()
+}
+
+// caller returns the first function on the stack
+// outside of the code wrapping resources.
+func caller() string {
+	pc := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(3, pc)])
+	for {
+		f, more := frames.Next()
+		if !strings.Contains(f.Function, "/api/internal/wrappy.") &&
+			!strings.Contains(f.Function, "/api/resource.") {
+			return f.Function
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// parseJSON returns a node of the JSON data, keeping the
+// order of its fields, styled as if read from YAML.
+func parseJSON(data []byte) (*yaml.RNode, error) {
+	n, err := yaml.Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if yaml.IsMissingOrNull(n) {
+		return yaml.NewRNode(&yaml.Node{Kind: yaml.MappingNode}), nil
+	}
+	resetStyle(n.YNode())
+	return n, nil
+}
+
+// resetStyle drops the flow style and quotes of the
+// node and its content, which the encoder then adds
+// back only where needed.
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
+
+// decode returns the value of the node as read from JSON
+// by unstructured.Unstructured, i.e. with maps of strings,
+// integers of type int64, and other numbers of type float64.
+func decode(n *yaml.Node) (interface{}, error) {
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, err
+	}
+	return jsonValue(v), nil
+}
+
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case int:
+		return int64(t)
+	case uint64:
+		return float64(t)
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = jsonValue(e)
+		}
+		return t
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range t {
+			t[i] = jsonValue(e)
+		}
+		return t
+	default:
+		return v
+	}
+}
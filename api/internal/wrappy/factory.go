@@ -4,8 +4,20 @@
 package wrappy
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/hasher"
 	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // WNodeFactory makes instances of WNode.
@@ -14,28 +26,268 @@ import (
 // to implement ifc.Unstructured.
 // This factory is meant to implement ifc.KunstructuredFactory.
 type WNodeFactory struct {
+	// Conversions, if not nil, records the lossy
+	// conversions of the nodes the factory makes.
+	Conversions *Conversions
 }
 
 var _ ifc.KunstructuredFactory = (*WNodeFactory)(nil)
 
+func (k *WNodeFactory) wrap(node *yaml.RNode) *WNode {
+	return &WNode{node: node, conversions: k.Conversions}
+}
+
+// SliceFromBytes returns the objects of the YAML or JSON
+// documents, keeping their comments, the order of their
+// fields and the style of their values.
 func (k *WNodeFactory) SliceFromBytes(bs []byte) ([]ifc.Kunstructured, error) {
-	panic("TODO(#WNodeFactory): implement SliceFromBytes")
+	nodes, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(bs),
+		OmitReaderAnnotations: true,
+		// Lists are unwrapped by resource.Factory.
+		DisableUnwrapping: true,
+	}).Read()
+	if err != nil {
+		return nil, err
+	}
+	var result []ifc.Kunstructured
+	for _, n := range nodes {
+		if doc := n.YNode(); doc.Kind == yaml.DocumentNode && len(doc.Content) == 1 {
+			n = yaml.NewRNode(doc.Content[0])
+			if n.YNode().HeadComment == "" {
+				n.YNode().HeadComment = doc.HeadComment
+			}
+		}
+		if n.YNode().Kind != yaml.MappingNode {
+			// JSON is read as a flow mapping.
+			if n.YNode().Kind != yaml.ScalarNode || !yaml.IsMissingOrNull(n) {
+				return nil, fmt.Errorf("expected an object, got %s", n.MustString())
+			}
+			continue
+		}
+		if len(n.Content()) == 0 {
+			continue
+		}
+		if n.YNode().Style == yaml.FlowStyle {
+			resetStyle(n.YNode())
+		}
+		if err = validate(n); err != nil {
+			return nil, err
+		}
+		if !skipResource(n) {
+			result = append(result, k.wrap(n))
+		}
+	}
+	return result, nil
+}
+
+// validate validates that node has kind and name
+// except for kind `List`, which doesn't require a name
+func validate(node *yaml.RNode) error {
+	meta, err := node.GetMeta()
+	if err != nil && err != yaml.ErrMissingMetadata {
+		return err
+	}
+	if meta.Kind == "" {
+		return fmt.Errorf("missing kind in object %s", node.MustString())
+	} else if strings.HasSuffix(meta.Kind, "List") {
+		return nil
+	}
+	if meta.Name == "" {
+		return fmt.Errorf("missing metadata.name in object %s", node.MustString())
+	}
+	if path := nilListItem(node.YNode()); path != "" {
+		return fmt.Errorf("empty item at %v in object %s", path, node.MustString())
+	}
+	return nil
+}
+
+// nilListItem returns the path of the first null
+// item of a list in the node, if any.
+func nilListItem(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if path := nilListItem(n.Content[i+1]); path != "" {
+				return n.Content[i].Value + "/" + path
+			}
+		}
+	case yaml.SequenceNode:
+		for i, e := range n.Content {
+			if yaml.IsMissingOrNull(yaml.NewRNode(e)) {
+				return fmt.Sprintf("[%d]/", i)
+			}
+			if path := nilListItem(e); path != "" {
+				return fmt.Sprintf("[%d]/%s", i, path)
+			}
+		}
+	}
+	return ""
+}
+
+// ignoredByKustomizeResourceAnnotation if set on a Resource will cause
+// Kustomize to ignore the Resource rather than Kustomize it.
+const ignoredByKustomizeResourceAnnotation = "config.kubernetes.io/local-config"
+
+// skipResource returns true if the Resource should not be accumulated
+func skipResource(node *yaml.RNode) bool {
+	meta, _ := node.GetMeta()
+	_, found := meta.Annotations[ignoredByKustomizeResourceAnnotation]
+	return found
 }
 
+// FromMap returns a node of the map, with its fields sorted.
 func (k *WNodeFactory) FromMap(m map[string]interface{}) ifc.Kunstructured {
-	panic("TODO(#WNodeFactory): implement FromMap")
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		panic(errors.Wrap(err, "for FromMap, expected a valid map"))
+	}
+	n, err := yaml.Parse(string(b))
+	if err != nil {
+		panic(errors.Wrap(err, "for FromMap, expected a valid map"))
+	}
+	return k.wrap(n)
 }
 
+// Hasher returns a hasher of the nodes, whose
+// hashes match those of kunstruct.UnstructAdapter.
 func (k *WNodeFactory) Hasher() ifc.KunstructuredHasher {
-	panic("TODO(#WNodeFactory): implement Hasher")
+	return wNodeHasher{}
 }
 
+type wNodeHasher struct{}
+
+// Hash returns a hash of the object read back from
+// its JSON, so that the order of its fields doesn't
+// change the hash.
+func (wNodeHasher) Hash(m ifc.Kunstructured) (string, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	node, err := yaml.Parse(string(data))
+	if err != nil {
+		return "", err
+	}
+	return hasher.HashRNode(node)
+}
+
+// MakeConfigMap returns a node of a ConfigMap
+// of the key value pairs the args refer to.
 func (k *WNodeFactory) MakeConfigMap(
 	kvLdr ifc.KvLoader, args *types.ConfigMapArgs) (ifc.Kunstructured, error) {
-	panic("TODO(#WNodeFactory): implement MakeConfigMap")
+	all, err := kvLdr.Load(args.KvPairSources)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading KV pairs")
+	}
+	node := makeFreshNode("ConfigMap", args.Name, args.Namespace)
+	data := map[string]string{}
+	binaryData := map[string]string{}
+	for _, p := range all {
+		if err = kvLdr.Validator().ErrIfInvalidKey(p.Key); err != nil {
+			return nil, errors.Wrap(err, "trouble mapping")
+		}
+		// If the configmap data contains byte sequences that are all in the
+		// UTF-8 range, it's written to data, otherwise to binaryData.
+		m, v := data, p.Value
+		if !utf8.Valid([]byte(p.Value)) {
+			m, v = binaryData, base64.StdEncoding.EncodeToString([]byte(p.Value))
+		}
+		if _, exists := m[p.Key]; exists {
+			return nil, errors.Wrap(
+				fmt.Errorf(keyExistsErrorMsg, p.Key, m), "trouble mapping")
+		}
+		m[p.Key] = v
+	}
+	if err = setDataMap(node, "data", data); err != nil {
+		return nil, err
+	}
+	if err = setDataMap(node, "binaryData", binaryData); err != nil {
+		return nil, err
+	}
+	copyLabelsAndAnnotations(node, args.Options)
+	return k.wrap(node), nil
 }
 
+// MakeSecret returns a node of a Secret of
+// the key value pairs the args refer to.
 func (k *WNodeFactory) MakeSecret(
 	kvLdr ifc.KvLoader, args *types.SecretArgs) (ifc.Kunstructured, error) {
-	panic("TODO(#WNodeFactory): implement MakeSecret")
+	all, err := kvLdr.Load(args.KvPairSources)
+	if err != nil {
+		return nil, err
+	}
+	node := makeFreshNode("Secret", args.Name, args.Namespace)
+	data := map[string]string{}
+	for _, p := range all {
+		if err = kvLdr.Validator().ErrIfInvalidKey(p.Key); err != nil {
+			return nil, err
+		}
+		if _, exists := data[p.Key]; exists {
+			return nil, fmt.Errorf(keyExistsErrorMsg, p.Key, data)
+		}
+		data[p.Key] = base64.StdEncoding.EncodeToString([]byte(p.Value))
+	}
+	if err = setDataMap(node, "data", data); err != nil {
+		return nil, err
+	}
+	t := args.Type
+	if t == "" {
+		t = ifc.SecretTypeOpaque
+	}
+	if err = node.PipeE(yaml.SetField("type", newStringRNode(t))); err != nil {
+		return nil, err
+	}
+	copyLabelsAndAnnotations(node, args.Options)
+	return k.wrap(node), nil
+}
+
+const keyExistsErrorMsg = "cannot add key %s, another key by that name already exists: %v"
+
+// makeFreshNode returns a node of the kind of the v1 group,
+// with the name and namespace, if any.
+func makeFreshNode(kind, name, namespace string) *yaml.RNode {
+	node := yaml.NewRNode(&yaml.Node{Kind: yaml.MappingNode})
+	wn := FromRNode(node)
+	wn.SetGvk(resid.Gvk{Version: "v1", Kind: kind})
+	wn.SetName(name)
+	wn.SetNamespace(namespace)
+	if name == "" && namespace == "" {
+		// As written by kunstruct.NewKunstructuredFromObject.
+		_ = node.PipeE(yaml.LookupCreate(yaml.MappingNode, yaml.MetadataField))
+	}
+	return node
+}
+
+// setDataMap sets the field to the map, sorted,
+// unless the map is empty.
+func setDataMap(node *yaml.RNode, field string, m map[string]string) error {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	for _, k := range keys {
+		value.Content = append(value.Content,
+			newStringRNode(k).YNode(), newStringRNode(m[k]).YNode())
+	}
+	return node.PipeE(yaml.SetField(field, yaml.NewRNode(value)))
+}
+
+func copyLabelsAndAnnotations(
+	node *yaml.RNode, opts *types.GeneratorOptions) {
+	if opts == nil {
+		return
+	}
+	wn := FromRNode(node)
+	if opts.Labels != nil {
+		wn.SetLabels(opts.Labels)
+	}
+	if opts.Annotations != nil {
+		wn.SetAnnotations(opts.Annotations)
+	}
 }
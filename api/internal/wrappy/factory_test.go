@@ -2,3 +2,164 @@
 // SPDX-License-Identifier: Apache-2.0
 
 package wrappy_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/ifc"
+	. "sigs.k8s.io/kustomize/api/internal/wrappy"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/api/kv"
+	"sigs.k8s.io/kustomize/api/loader"
+	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestSliceFromBytes(t *testing.T) {
+	factory := &WNodeFactory{}
+	input := `# the service
+kind: Service
+apiVersion: v1
+metadata:
+  name: homer
+  annotations:
+    port: "80"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: local
+  annotations:
+    config.kubernetes.io/local-config: "true"
+---
+{"kind": "Secret", "apiVersion": "v1", "metadata": {"name": "bart"}}
+`
+	result, err := factory.SliceFromBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(result))
+	}
+	expected := `# the service
+kind: Service
+apiVersion: v1
+metadata:
+  name: homer
+  annotations:
+    port: "80"
+`
+	actual := result[0].(*WNode).HeldRNode().MustString()
+	if actual != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, actual)
+	}
+	expected = `kind: Secret
+apiVersion: v1
+metadata:
+  name: bart
+`
+	actual = result[1].(*WNode).HeldRNode().MustString()
+	if actual != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	_, err = factory.SliceFromBytes([]byte("kind: Service\nmetadata: {}\n"))
+	if err == nil {
+		t.Fatalf("expected a missing name err")
+	}
+}
+
+func TestGeneratedHashesMatchKunstruct(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/app.env", []byte("FRUIT=apple\nVEGGIE=carrot\n"))
+	ldr, err := loader.NewLoader(
+		loader.RestrictionRootOnly, filesys.Separator, fSys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kvLdr := kv.NewLoader(ldr, valtest_test.MakeFakeValidator())
+	sources := types.KvPairSources{
+		EnvSources:     []string{"app.env"},
+		LiteralSources: []string{"binary=\xff\xfe"},
+	}
+	wf := &WNodeFactory{}
+	kf := kunstruct.NewKunstructuredFactoryImpl()
+
+	cmArgs := &types.ConfigMapArgs{GeneratorArgs: types.GeneratorArgs{
+		Name: "cm", Namespace: "ns", KvPairSources: sources,
+		Options: &types.GeneratorOptions{Labels: map[string]string{"a": "b"}},
+	}}
+	wcm, err := wf.MakeConfigMap(kvLdr, cmArgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kcm, err := kf.MakeConfigMap(kvLdr, cmArgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameHash(t, wf.Hasher(), wcm, kf.Hasher(), kcm)
+
+	secretArgs := &types.SecretArgs{GeneratorArgs: types.GeneratorArgs{
+		Name: "secret", KvPairSources: sources,
+	}}
+	wsecret, err := wf.MakeSecret(kvLdr, secretArgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ksecret, err := kf.MakeSecret(kvLdr, secretArgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameHash(t, wf.Hasher(), wsecret, kf.Hasher(), ksecret)
+}
+
+func assertSameHash(t *testing.T,
+	h1 ifc.KunstructuredHasher, u1 ifc.Kunstructured,
+	h2 ifc.KunstructuredHasher, u2 ifc.Kunstructured) {
+	t.Helper()
+	actual, err := h1.Hash(u1)
+	if err != nil {
+		t.Fatalf("unexpected hash err: %v", err)
+	}
+	expected, err := h2.Hash(u2)
+	if err != nil {
+		t.Fatalf("unexpected hash err: %v", err)
+	}
+	if actual != expected {
+		t.Fatalf("expected hash %s, got %s", expected, actual)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	conversions := &Conversions{}
+	f := &WNodeFactory{Conversions: conversions}
+	objs, err := f.SliceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: commented # the name
+`))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	for _, obj := range objs {
+		obj.Map()
+	}
+	objs[1].Copy().Map()
+	if err = objs[0].UnmarshalJSON([]byte(`{"kind":"Secret"}`)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	lossy := conversions.Lossy()
+	if len(lossy) != 2 ||
+		!strings.HasPrefix(lossy[0], "Map of ConfigMap commented by ") ||
+		!strings.HasPrefix(lossy[1], "Map of ConfigMap commented by ") {
+		t.Fatalf("unexpected lossy conversions %v", lossy)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package wrappy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSection holds a list of nested fields, which may end
+// with an index into a list, per the field paths accepted
+// by kunstruct.UnstructAdapter, e.g. foo.bar is one section
+// of two fields, foo[0].bar is a section of field foo and
+// index 0 followed by a section of field bar, and
+// metadata.labels[app.kubernetes.io/name] is one section
+// of three fields, as the downward API has it.
+type pathSection struct {
+	fields []string
+	idx    int
+}
+
+func newPathSection() pathSection {
+	return pathSection{idx: -1}
+}
+
+func appendNonEmpty(section *pathSection, field string) {
+	if len(field) != 0 {
+		section.fields = append(section.fields, field)
+	}
+}
+
+func parseFields(path string) (result []pathSection, err error) {
+	section := newPathSection()
+	if !strings.Contains(path, "[") {
+		section.fields = strings.Split(path, ".")
+		result = append(result, section)
+		return result, nil
+	}
+
+	start := 0
+	insideParentheses := false
+	for i, c := range path {
+		switch c {
+		case '.':
+			if !insideParentheses {
+				appendNonEmpty(&section, path[start:i])
+				start = i + 1
+			}
+		case '[':
+			if !insideParentheses {
+				appendNonEmpty(&section, path[start:i])
+				start = i + 1
+				insideParentheses = true
+			} else {
+				return nil, fmt.Errorf("nested parentheses are not allowed: %s", path)
+			}
+		case ']':
+			if !insideParentheses {
+				return nil, fmt.Errorf("invalid field path %s", path)
+			}
+			if idx, err := strconv.Atoi(path[start:i]); err == nil {
+				section.idx = idx
+			} else {
+				appendNonEmpty(&section, path[start:i])
+			}
+			result = append(result, section)
+			section = newPathSection()
+			start = i + 1
+			insideParentheses = false
+		}
+	}
+	if start < len(path)-1 {
+		appendNonEmpty(&section, path[start:])
+		result = append(result, section)
+	}
+
+	for _, section := range result {
+		for i, f := range section.fields {
+			if strings.HasPrefix(f, "\"") || strings.HasPrefix(f, "'") {
+				section.fields[i] = strings.Trim(f, "\"'")
+			}
+		}
+	}
+	return result, nil
+}
@@ -4,10 +4,14 @@
 package wrappy
 
 import (
+	"fmt"
 	"log"
+	"sort"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/kyaml/filtersutil"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -17,13 +21,22 @@ import (
 // kunstruct.UnstructAdapter to yaml.RNode as the core
 // representation of KRM objects in kustomize.
 //
+// The node keeps the order of the fields, the comments and
+// the style of the values it's read with.  Filters reach it
+// through filtersutil.RNodeHolder, so that they change it in
+// place; what goes through maps or JSON, e.g. Map and
+// UnmarshalJSON, loses some of that, and is recorded in
+// the Conversions of the factory, if any.
+//
 // It's got a silly name because we don't want it around for long,
 // and want its use to be obvious.
 type WNode struct {
-	node *yaml.RNode
+	node        *yaml.RNode
+	conversions *Conversions
 }
 
 var _ ifc.Kunstructured = (*WNode)(nil)
+var _ filtersutil.RNodeHolder = (*WNode)(nil)
 
 func NewWNode() *WNode {
 	return FromRNode(yaml.NewRNode(nil))
@@ -33,9 +46,17 @@ func FromRNode(node *yaml.RNode) *WNode {
 	return &WNode{node: node}
 }
 
+// HeldRNode implements filtersutil.RNodeHolder.
+func (wn *WNode) HeldRNode() *yaml.RNode {
+	if wn.node.YNode() == nil {
+		wn.node.SetYNode(&yaml.Node{Kind: yaml.MappingNode})
+	}
+	return wn.node
+}
+
 func (wn *WNode) demandMetaData(label string) yaml.ResourceMeta {
 	meta, err := wn.node.GetMeta()
-	if err != nil {
+	if err != nil && err != yaml.ErrMissingMetadata {
 		// Log and die since interface doesn't allow error.
 		log.Fatalf("for %s', expected valid resource: %v", label, err)
 	}
@@ -44,7 +65,7 @@ func (wn *WNode) demandMetaData(label string) yaml.ResourceMeta {
 
 // Copy implements ifc.Kunstructured.
 func (wn *WNode) Copy() ifc.Kunstructured {
-	return &WNode{node: wn.node.Copy()}
+	return &WNode{node: wn.node.Copy(), conversions: wn.conversions}
 }
 
 // GetAnnotations implements ifc.Kunstructured.
@@ -52,12 +73,56 @@ func (wn *WNode) GetAnnotations() map[string]string {
 	return wn.demandMetaData("GetAnnotations").Annotations
 }
 
+// lookup returns the node at the path, in the syntax
+// of kunstruct.UnstructAdapter, or a NoFieldError.
+func (wn *WNode) lookup(path string) (*yaml.Node, error) {
+	sections, err := parseFields(path)
+	if err != nil || len(sections) == 0 {
+		return nil, NoFieldError{Field: path}
+	}
+	n := wn.node.YNode()
+	for _, s := range sections {
+		for _, f := range s.fields {
+			n = field(n, f)
+		}
+		if s.idx == -1 {
+			continue
+		}
+		if n == nil || n.Kind != yaml.SequenceNode {
+			return nil, NoFieldError{Field: path}
+		}
+		if s.idx >= len(n.Content) {
+			return nil, fmt.Errorf("index %d is out of bounds", s.idx)
+		}
+		n = n.Content[s.idx]
+	}
+	if n == nil {
+		return nil, NoFieldError{Field: path}
+	}
+	return n, nil
+}
+
+// field returns the value of the field of the
+// mapping node n, or nil if it has none.
+func field(n *yaml.Node, name string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == name {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // GetFieldValue implements ifc.Kunstructured.
 func (wn *WNode) GetFieldValue(path string) (interface{}, error) {
-	// The argument is a json path, e.g. "metadata.name"
-	// fields := strings.Split(path, ".")
-	// return wn.node.Pipe(yaml.Lookup(fields...))
-	panic("TODO(#WNode): GetFieldValue; implement or drop from API")
+	n, err := wn.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	return decode(n)
 }
 
 // GetGvk implements ifc.Kunstructured.
@@ -83,61 +148,211 @@ func (wn *WNode) GetName() string {
 }
 
 // GetSlice implements ifc.Kunstructured.
-func (wn *WNode) GetSlice(string) ([]interface{}, error) {
-	panic("TODO(#WNode) GetSlice; implement or drop from API")
+func (wn *WNode) GetSlice(path string) ([]interface{}, error) {
+	v, err := wn.GetFieldValue(path)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf(
+			"%v accessor error: %v is of the type %T, expected []interface{}",
+			path, v, v)
+	}
+	return s, nil
 }
 
-// GetSlice implements ifc.Kunstructured.
-func (wn *WNode) GetString(string) (string, error) {
-	panic("TODO(#WNode) GetString; implement or drop from API")
+// GetString implements ifc.Kunstructured.
+func (wn *WNode) GetString(path string) (string, error) {
+	v, err := wn.GetFieldValue(path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf(
+			"%v accessor error: %v is of the type %T, expected string",
+			path, v, v)
+	}
+	return s, nil
 }
 
 // Map implements ifc.Kunstructured.
+// The map is a copy of the node, so changing it
+// doesn't change the node; it's recorded in the
+// conversions if it loses something of the node.
 func (wn *WNode) Map() map[string]interface{} {
-	panic("TODO(#WNode) Map; implement or drop from API")
+	if yaml.IsMissingOrNull(wn.node) {
+		return map[string]interface{}{}
+	}
+	wn.conversions.check("Map", wn.node)
+	v, err := decode(wn.node.YNode())
+	if err != nil {
+		log.Fatalf("for Map, expected valid resource: %v", err)
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return m
 }
 
 // MarshalJSON implements ifc.Kunstructured.
 func (wn *WNode) MarshalJSON() ([]byte, error) {
+	if yaml.IsMissingOrNull(wn.node) {
+		return []byte("{}"), nil
+	}
 	return wn.node.MarshalJSON()
 }
 
 // MatchesAnnotationSelector implements ifc.Kunstructured.
-func (wn *WNode) MatchesAnnotationSelector(string) (bool, error) {
-	panic("TODO(#WNode) MatchesAnnotationSelector; implement or drop from API")
+func (wn *WNode) MatchesAnnotationSelector(selector string) (bool, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(labels.Set(wn.GetAnnotations())), nil
 }
 
 // MatchesLabelSelector implements ifc.Kunstructured.
-func (wn *WNode) MatchesLabelSelector(string) (bool, error) {
-	panic("TODO(#WNode) MatchesLabelSelector; implement or drop from API")
+func (wn *WNode) MatchesLabelSelector(selector string) (bool, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(labels.Set(wn.GetLabels())), nil
 }
 
 // SetAnnotations implements ifc.Kunstructured.
-func (wn *WNode) SetAnnotations(map[string]string) {
-	panic("TODO(#WNode) SetAnnotations; implement or drop from API")
+func (wn *WNode) SetAnnotations(m map[string]string) {
+	wn.setMetaMap(yaml.AnnotationsField, m)
 }
 
 // SetGvk implements ifc.Kunstructured.
-func (wn *WNode) SetGvk(resid.Gvk) {
-	panic("TODO(#WNode) SetGvk; implement or drop from API")
+func (wn *WNode) SetGvk(gvk resid.Gvk) {
+	apiVersion := gvk.Version
+	if gvk.Group != "" {
+		apiVersion = gvk.Group + "/" + gvk.Version
+	}
+	wn.setString(apiVersion, yaml.APIVersionField)
+	wn.setString(gvk.Kind, yaml.KindField)
 }
 
 // SetLabels implements ifc.Kunstructured.
-func (wn *WNode) SetLabels(map[string]string) {
-	panic("TODO(#WNode) SetLabels; implement or drop from API")
+func (wn *WNode) SetLabels(m map[string]string) {
+	wn.setMetaMap(yaml.LabelsField, m)
 }
 
 // SetName implements ifc.Kunstructured.
-func (wn *WNode) SetName(string) {
-	panic("TODO(#WNode) SetName; implement or drop from API")
+func (wn *WNode) SetName(name string) {
+	wn.setString(name, yaml.MetadataField, yaml.NameField)
 }
 
 // SetNamespace implements ifc.Kunstructured.
-func (wn *WNode) SetNamespace(string) {
-	panic("TODO(#WNode) SetNamespace; implement or drop from API")
+func (wn *WNode) SetNamespace(ns string) {
+	wn.setString(ns, yaml.MetadataField, yaml.NamespaceField)
+}
+
+// setString sets the string field at the path, leaving it
+// as it is if it already has the value, or clears it if
+// the value is empty, as unstructured.Unstructured does.
+func (wn *WNode) setString(value string, path ...string) {
+	parent, name := path[:len(path)-1], path[len(path)-1]
+	if value == "" {
+		p, err := wn.HeldRNode().Pipe(yaml.Lookup(parent...))
+		if err == nil && p != nil {
+			_, err = p.Pipe(yaml.Clear(name))
+		}
+		if err != nil {
+			log.Fatalf("for %s, expected valid resource: %v", name, err)
+		}
+		return
+	}
+	if f, err := wn.HeldRNode().Pipe(yaml.Lookup(path...)); err == nil &&
+		f != nil && f.YNode().Kind == yaml.ScalarNode && f.YNode().Value == value {
+		return
+	}
+	err := wn.HeldRNode().PipeE(
+		yaml.LookupCreate(yaml.MappingNode, parent...),
+		yaml.SetField(name, newStringRNode(value)))
+	if err != nil {
+		log.Fatalf("for %s, expected valid resource: %v", name, err)
+	}
+}
+
+// setMetaMap sets the metadata map field to the given
+// map, keeping the order of the keys it already had,
+// and appending the new ones sorted, or clears it if
+// the map is empty, as unstructured.Unstructured does.
+func (wn *WNode) setMetaMap(name string, m map[string]string) {
+	if len(m) == 0 {
+		meta, err := wn.HeldRNode().Pipe(yaml.Lookup(yaml.MetadataField))
+		if err == nil && meta != nil {
+			_, err = meta.Pipe(yaml.Clear(name))
+		}
+		if err != nil {
+			log.Fatalf("for %s, expected valid resource: %v", name, err)
+		}
+		return
+	}
+	f, err := wn.HeldRNode().Pipe(
+		yaml.LookupCreate(yaml.MappingNode, yaml.MetadataField, name))
+	if err != nil {
+		log.Fatalf("for %s, expected valid resource: %v", name, err)
+	}
+	n := f.YNode()
+	seen := make(map[string]bool, len(m))
+	var content []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		value, ok := m[k.Value]
+		if !ok || seen[k.Value] {
+			continue
+		}
+		seen[k.Value] = true
+		if v.Kind != yaml.ScalarNode || v.Value != value {
+			v = newStringRNode(value).YNode()
+		}
+		content = append(content, k, v)
+	}
+	var keys []string
+	for k := range m {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		content = append(content,
+			newStringRNode(k).YNode(), newStringRNode(m[k]).YNode())
+	}
+	n.Content = content
+}
+
+// newStringRNode returns a node of the string value,
+// quoted as needed to remain a string.
+func newStringRNode(value string) *yaml.RNode {
+	return yaml.NewRNode(
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: value})
 }
 
 // UnmarshalJSON implements ifc.Kunstructured.
+// The node keeps the order of the fields of the JSON.
 func (wn *WNode) UnmarshalJSON(data []byte) error {
-	return wn.node.UnmarshalJSON(data)
+	n, err := parseJSON(data)
+	if err != nil {
+		return err
+	}
+	wn.conversions.check("UnmarshalJSON", wn.node)
+	wn.node.SetYNode(n.YNode())
+	return nil
+}
+
+// NoFieldError is returned when a field is expected, but missing.
+type NoFieldError struct {
+	Field string
+}
+
+func (e NoFieldError) Error() string {
+	return fmt.Sprintf("no field named '%s'", e.Field)
 }
@@ -337,3 +337,100 @@ func TestGettingFields(t *testing.T) {
 		t.Fatalf("unexpected annotations '%v'", actualMap)
 	}
 }
+
+const deploymentYaml = `# the deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: homer
+  labels:
+    fruit: apple
+spec:
+  replicas: 2 # two
+  template:
+    spec:
+      containers:
+      - name: main
+        image: "nginx:1.7"
+`
+
+func TestSettersKeepTheRestOfTheNode(t *testing.T) {
+	node, err := kyaml.Parse(deploymentYaml)
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	wn := FromRNode(node)
+	wn.SetName("marge")
+	wn.SetNamespace("simpsons")
+	wn.SetLabels(map[string]string{"fruit": "apple", "app": "donut"})
+	expected := `# the deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: marge
+  labels:
+    fruit: apple
+    app: donut
+  namespace: simpsons
+spec:
+  replicas: 2 # two
+  template:
+    spec:
+      containers:
+      - name: main
+        image: "nginx:1.7"
+`
+	actual := wn.HeldRNode().MustString()
+	if actual != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, actual)
+	}
+	wn.SetLabels(map[string]string{})
+	if _, found := wn.GetLabels()["fruit"]; found {
+		t.Fatalf("expected labels to be cleared, got %s", wn.HeldRNode().MustString())
+	}
+}
+
+func TestGetFieldValue(t *testing.T) {
+	node, err := kyaml.Parse(deploymentYaml)
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	wn := FromRNode(node)
+	s, err := wn.GetString("spec.template.spec.containers[0].image")
+	if err != nil || s != "nginx:1.7" {
+		t.Fatalf("unexpected image %q, err %v", s, err)
+	}
+	v, err := wn.GetFieldValue("spec.replicas")
+	if err != nil || v != int64(2) {
+		t.Fatalf("unexpected replicas %#v, err %v", v, err)
+	}
+	_, err = wn.GetFieldValue("spec.template.spec.containers[1].image")
+	if err == nil {
+		t.Fatalf("expected an out of bounds err")
+	}
+	_, err = wn.GetFieldValue("spec.paused")
+	if _, ok := err.(NoFieldError); !ok {
+		t.Fatalf("expected a NoFieldError, got %v", err)
+	}
+}
+
+func TestMatchesLabelSelector(t *testing.T) {
+	node, err := kyaml.Parse(deploymentYaml)
+	if err != nil {
+		t.Fatalf("unexpected parse err: %v", err)
+	}
+	wn := FromRNode(node)
+	for selector, expected := range map[string]bool{
+		"fruit=apple":          true,
+		"fruit in (pear, fig)": false,
+		"!veggie":              true,
+	} {
+		actual, err := wn.MatchesLabelSelector(selector)
+		if err != nil {
+			t.Fatalf("unexpected err for %q: %v", selector, err)
+		}
+		if actual != expected {
+			t.Fatalf("expected %v for %q", expected, selector)
+		}
+	}
+}
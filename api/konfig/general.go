@@ -28,6 +28,20 @@ const (
 	// Use this when XdgConfigHomeEnv not defined.
 	XdgConfigHomeEnvDefault = ".config"
 
+	// Environment variables naming the directories to
+	// consult for data, per the spec cited above, and
+	// the values to use when they're not defined.
+	XdgDataHomeEnv        = "XDG_DATA_HOME"
+	XdgDataHomeEnvDefault = ".local/share"
+	XdgDataDirsEnv        = "XDG_DATA_DIRS"
+	XdgDataDirsEnvDefault = "/usr/local/share:/usr/share"
+
+	// An environment variable naming the directory
+	// to cache data in, per the spec cited above,
+	// and the value to use when it's not defined.
+	XdgCacheHomeEnv        = "XDG_CACHE_HOME"
+	XdgCacheHomeEnvDefault = ".cache"
+
 	// A program name, for use in help, finding the XDG_CONFIG_DIR, etc.
 	ProgramName = "kustomize"
 
@@ -39,4 +53,14 @@ const (
 
 	// Label key that indicates the resources are validated by a validator
 	ValidatedByLabelKey = "validated-by"
+
+	// Annotation key listing the generators and transformers that
+	// made or changed a resource, per the transformerAnnotations
+	// buildMetadata option
+	TransformationsAnnotationKey = "alpha.config.kubernetes.io/transformations"
+
+	// Annotation key telling the file a resource was read from,
+	// or the generator that made it, per the originAnnotations
+	// buildMetadata option
+	OriginAnnotationKey = "config.kubernetes.io/origin"
 )
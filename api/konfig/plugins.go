@@ -16,6 +16,24 @@ const (
 	// Symbol that must be used inside Go plugins.
 	PluginSymbol = "KustomizePlugin"
 
+	// Suffix of the executables of plugins served
+	// over RPC, per the pluginrpc package.
+	RpcPluginSuffix = ".rpc"
+
+	// Suffix of the plugins compiled to WebAssembly,
+	// per the wasmplugin package.
+	WasmPluginSuffix = ".wasm"
+
+	// Suffix of the executables of plugins speaking
+	// version 2 of the exec plugin protocol, reading
+	// and writing ResourceLists, per the execplugin
+	// package.
+	ExecPluginV2Suffix = ".krm"
+
+	// Suffix of the manifest, next to the plugin, holding
+	// the OpenAPI definitions of the resources it handles.
+	PluginSchemaSuffix = ".openapi.yaml"
+
 	// Name of environment variable used to set AbsPluginHome.
 	// See that variable for an explanation.
 	KustomizePluginHomeEnv = "KUSTOMIZE_PLUGIN_HOME"
@@ -24,6 +42,18 @@ const (
 	// e.g. AbsPluginHome = XDG_CONFIG_HOME/kustomize/plugin
 	RelPluginHome = "plugin"
 
+	// Directory, below the working directory, holding
+	// the plugins vendored in a project.
+	ProjectPluginHome = "kustomize-plugins"
+
+	// Directory, below the XDG cache home, caching
+	// the output of container functions.
+	RelFnCacheHome = "fn-cache"
+
+	// Directory, below the XDG cache home, caching
+	// the output of incremental builds.
+	RelBuildCacheHome = "build-cache"
+
 	// Location of builtin plugins below AbsPluginHome.
 	BuiltinPluginPackage = "builtin"
 
@@ -36,17 +66,24 @@ const (
 	// plugin source code under $GOPATH when GOPATH is defined.
 	DomainName = "sigs.k8s.io"
 
+	// Image of the container in which git clones
+	// remote bases when building in a sandbox.
+	DefaultSandboxImage = "alpine/git:v2.26.2"
+
 	// Injected into plugin paths when plugins are disabled.
 	// Provides a clue in flows that shouldn't happen.
 	NoPluginHomeSentinal = "/No/non-builtin/plugins!"
 )
 
 func EnabledPluginConfig(b types.BuiltinPluginLoadingOptions) (*types.PluginConfig, error) {
-	dir, err := DefaultAbsPluginHome(filesys.MakeFsOnDisk())
+	fSys := filesys.MakeFsOnDisk()
+	dir, err := DefaultAbsPluginHome(fSys)
 	if err != nil {
 		return nil, err
 	}
-	return MakePluginConfig(types.PluginRestrictionsNone, b, dir), nil
+	c := MakePluginConfig(types.PluginRestrictionsNone, b, dir)
+	c.PluginSearchPath = PluginHomes(fSys)[1:]
+	return c, nil
 }
 
 func DisabledPluginConfig() *types.PluginConfig {
@@ -64,6 +101,7 @@ func MakePluginConfig(
 		PluginRestrictions: pr,
 		AbsPluginHome:      home,
 		BpLoadingOptions:   b,
+		Limits:             types.DefaultPluginLimits(),
 	}
 }
 
@@ -77,37 +115,109 @@ type NotedFunc struct {
 // the home of kustomize plugins.
 func DefaultAbsPluginHome(fSys filesys.FileSystem) (string, error) {
 	return FirstDirThatExistsElseError(
-		"plugin home directory", fSys, []NotedFunc{
-			{
-				Note: "homed in $" + KustomizePluginHomeEnv,
-				F: func() string {
-					return os.Getenv(KustomizePluginHomeEnv)
-				},
+		"plugin home directory", fSys, pluginHomes())
+}
+
+// PluginHomes returns the directories in the given file
+// system that exist among those searched for plugins,
+// in order of precedence.
+func PluginHomes(fSys filesys.FileSystem) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, dt := range pluginHomes() {
+		dir := dt.F()
+		if seen[dir] || !fSys.Exists(dir) {
+			continue
+		}
+		seen[dir] = true
+		result = append(result, dir)
+	}
+	return result
+}
+
+// pluginHomes returns the directories searched for
+// plugins, in order of precedence: $KUSTOMIZE_PLUGIN_HOME,
+// the project's plugins, then the XDG config and data
+// directories, then the home directory.
+func pluginHomes() []NotedFunc {
+	result := []NotedFunc{
+		{
+			Note: "homed in $" + KustomizePluginHomeEnv,
+			F: func() string {
+				return os.Getenv(KustomizePluginHomeEnv)
+			},
+		},
+		{
+			Note: "homed in the project",
+			F: func() string {
+				return filepath.Join(CurrentWorkingDir(), ProjectPluginHome)
 			},
-			{
-				Note: "homed in $" + XdgConfigHomeEnv,
-				F: func() string {
-					return filepath.Join(
-						os.Getenv(XdgConfigHomeEnv),
-						ProgramName, RelPluginHome)
-				},
+		},
+		{
+			Note: "homed in $" + XdgConfigHomeEnv,
+			F: func() string {
+				return filepath.Join(
+					os.Getenv(XdgConfigHomeEnv),
+					ProgramName, RelPluginHome)
 			},
-			{
-				Note: "homed in default value of $" + XdgConfigHomeEnv,
-				F: func() string {
-					return filepath.Join(
-						HomeDir(), XdgConfigHomeEnvDefault,
-						ProgramName, RelPluginHome)
-				},
+		},
+		{
+			Note: "homed in default value of $" + XdgConfigHomeEnv,
+			F: func() string {
+				return filepath.Join(
+					HomeDir(), XdgConfigHomeEnvDefault,
+					ProgramName, RelPluginHome)
 			},
-			{
-				Note: "homed in home directory",
-				F: func() string {
-					return filepath.Join(
-						HomeDir(), ProgramName, RelPluginHome)
-				},
+		},
+		{
+			Note: "homed in $" + XdgDataHomeEnv,
+			F: func() string {
+				home := os.Getenv(XdgDataHomeEnv)
+				if home == "" {
+					home = filepath.Join(HomeDir(), XdgDataHomeEnvDefault)
+				}
+				return filepath.Join(home, ProgramName, RelPluginHome)
 			},
+		},
+	}
+	dirs := os.Getenv(XdgDataDirsEnv)
+	if dirs == "" {
+		dirs = XdgDataDirsEnvDefault
+	}
+	for _, d := range filepath.SplitList(dirs) {
+		dir := filepath.Join(d, ProgramName, RelPluginHome)
+		result = append(result, NotedFunc{
+			Note: "homed in $" + XdgDataDirsEnv,
+			F:    func() string { return dir },
 		})
+	}
+	return append(result, NotedFunc{
+		Note: "homed in home directory",
+		F: func() string {
+			return filepath.Join(
+				HomeDir(), ProgramName, RelPluginHome)
+		},
+	})
+}
+
+// DefaultFnCacheDir returns the directory caching
+// the output of container functions.
+func DefaultFnCacheDir() string {
+	home := os.Getenv(XdgCacheHomeEnv)
+	if home == "" {
+		home = filepath.Join(HomeDir(), XdgCacheHomeEnvDefault)
+	}
+	return filepath.Join(home, ProgramName, RelFnCacheHome)
+}
+
+// DefaultBuildCacheDir returns the directory caching
+// the output of incremental builds.
+func DefaultBuildCacheDir() string {
+	home := os.Getenv(XdgCacheHomeEnv)
+	if home == "" {
+		home = filepath.Join(HomeDir(), XdgCacheHomeEnvDefault)
+	}
+	return filepath.Join(home, ProgramName, RelBuildCacheHome)
 }
 
 // FirstDirThatExistsElseError tests different path functions for
@@ -6,6 +6,7 @@ package konfig
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
@@ -124,3 +125,42 @@ func TestDefaultAbsPluginHomeNoXdgJustHomeDir(t *testing.T) {
 		t.Fatalf("unexpected config dir: %s", s)
 	}
 }
+
+func TestPluginHomes(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	for k, v := range map[string]string{
+		KustomizePluginHomeEnv: "/plugins",
+		XdgConfigHomeEnv:       "/config",
+		XdgDataHomeEnv:         "/data",
+		XdgDataDirsEnv:         "/share1:/share2",
+	} {
+		keep, isSet := os.LookupEnv(k)
+		os.Setenv(k, v)
+		defer func(k string) {
+			if isSet {
+				os.Setenv(k, keep)
+			} else {
+				_ = os.Unsetenv(k)
+			}
+		}(k)
+	}
+	project := filepath.Join(CurrentWorkingDir(), ProjectPluginHome)
+	for _, dir := range []string{
+		"/share2/kustomize/plugin",
+		"/data/kustomize/plugin",
+		project,
+		"/plugins",
+	} {
+		fSys.MkdirAll(dir)
+	}
+	expected := []string{
+		"/plugins",
+		project,
+		"/data/kustomize/plugin",
+		"/share2/kustomize/plugin",
+	}
+	actual := PluginHomes(fSys)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/applyset"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestApplySet(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: prod
+  labels:
+    tier: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: common
+`)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+`)
+	options := th.MakeDefaultOptions()
+	options.ApplySet = &applyset.Parent{Kind: "Secret", Name: "my-set", Namespace: "prod"}
+	options.PruneLabels = map[string]string{"owner": "ci"}
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    applyset.kubernetes.io/part-of: applyset-33jb7s1fLYA5Aiyr0Q3erXIf_pqlOUTv9GxRUYFRzNA-v1
+    owner: ci
+  name: app
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    applyset.kubernetes.io/part-of: applyset-33jb7s1fLYA5Aiyr0Q3erXIf_pqlOUTv9GxRUYFRzNA-v1
+    owner: ci
+    tier: web
+  name: app
+  namespace: prod
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    applyset.kubernetes.io/part-of: applyset-33jb7s1fLYA5Aiyr0Q3erXIf_pqlOUTv9GxRUYFRzNA-v1
+    owner: ci
+  name: shared
+  namespace: common
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  annotations:
+    applyset.kubernetes.io/additional-namespaces: common
+    applyset.kubernetes.io/contains-group-kinds: ConfigMap,Deployment.apps,Service
+    applyset.kubernetes.io/tooling: kubectl/v1.27.0
+  labels:
+    applyset.kubernetes.io/id: applyset-33jb7s1fLYA5Aiyr0Q3erXIf_pqlOUTv9GxRUYFRzNA-v1
+    owner: ci
+  name: my-set
+  namespace: prod
+`)
+}
+
+func TestApplySetBadParent(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", "")
+	options := th.MakeDefaultOptions()
+	options.ApplySet = &applyset.Parent{Kind: "Deployment", Name: "my-set"}
+	err := th.RunWithErr("/app", options)
+	if err == nil || err.Error() !=
+		"the parent of an applyset must be a Secret or ConfigMap, not Deployment" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
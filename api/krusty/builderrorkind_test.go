@@ -0,0 +1,78 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestBuildErrorKind(t *testing.T) {
+	testCases := map[string]struct {
+		files    map[string]string
+		expected types.BuildErrorKind
+	}{
+		"unparsable kustomization": {
+			files: map[string]string{
+				"kustomization.yaml": "resources: {",
+			},
+			expected: types.BuildErrorParse,
+		},
+		"illegal kustomization field": {
+			files: map[string]string{
+				"kustomization.yaml": "namePrefix: [dev-]",
+			},
+			expected: types.BuildErrorParse,
+		},
+		"unparsable resource": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml",
+				"cm.yaml":            "apiVersion: v1\nkind: ConfigMap\nmetadata: {",
+			},
+			expected: types.BuildErrorParse,
+		},
+		"missing resource": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- missing.yaml",
+			},
+			expected: types.BuildErrorMissingFile,
+		},
+		"missing kustomization in base": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- base",
+				"base/cm.yaml":       "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm",
+			},
+			expected: types.BuildErrorMissingFile,
+		},
+		"external plugin disabled": {
+			files: map[string]string{
+				"kustomization.yaml": "generators:\n- gen.yaml",
+				"gen.yaml": `
+apiVersion: someteam.example.com/v1
+kind: SomeGenerator
+metadata:
+  name: whatever
+`,
+			},
+			expected: types.BuildErrorPlugin,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			th := kusttest_test.MakeHarness(t)
+			for f, c := range tc.files {
+				th.WriteF("/app/"+f, c)
+			}
+			err := th.RunWithErr("/app", th.MakeDefaultOptions())
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if k := types.BuildErrorKindOf(err); k != tc.expected {
+				t.Fatalf("expected %v, got %v for error: %v", tc.expected, k, err)
+			}
+		})
+	}
+}
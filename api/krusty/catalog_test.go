@@ -0,0 +1,105 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/catalog"
+	"sigs.k8s.io/kustomize/api/konfig"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const approvedCatalog = `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Catalog
+metadata:
+  name: approved
+functions:
+- image: example.com/approved@sha256:4f03
+`
+
+func writeSignedCatalog(t *testing.T, th kusttest_test.Harness) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	th.WriteF("/keys/cosign.pub", string(
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	th.WriteF("/catalog.yaml", approvedCatalog)
+	th.WriteF("/catalog.yaml.sig", base64.StdEncoding.EncodeToString(
+		ed25519.Sign(priv, []byte(approvedCatalog))))
+}
+
+func TestRequireCatalog(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSignedCatalog(t, th)
+	th.WriteK("/app", `
+resources:
+- service.yaml
+generators:
+- gen.yaml
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`)
+	th.WriteF("/app/gen.yaml", `
+apiVersion: example.com/v1
+kind: Generator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/unapproved:v1
+`)
+	options := th.MakeDefaultOptions()
+	options.PluginConfig = konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked,
+		konfig.NoPluginHomeSentinal)
+	options.RequireCatalog = true
+
+	err := th.RunWithErr("/app", options)
+	if !strings.Contains(err.Error(), "a catalog is required, but none is configured") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options.Catalogs = []catalog.Source{
+		{URL: "/catalog.yaml", PublicKey: "/keys/cosign.pub"}}
+	err = th.RunWithErr("/app", options)
+	if !strings.Contains(err.Error(),
+		"function image example.com/unapproved:v1 is not in an approved catalog") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kind := types.BuildErrorKindOf(err); kind != types.BuildErrorPlugin {
+		t.Fatalf("expected a plugin error, got %v", kind)
+	}
+
+	// Builtins needn't be approved.
+	th.WriteK("/app", `
+resources:
+- service.yaml
+`)
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`)
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestErrorLocation(t *testing.T) {
+	testCases := map[string]struct {
+		files    map[string]string
+		expected types.ErrorLocation
+	}{
+		"unknown field": {
+			files: map[string]string{
+				"kustomization.yaml": "bogus: dev-",
+			},
+			expected: types.ErrorLocation{
+				File: "/app/kustomization.yaml", FieldPath: "bogus"},
+		},
+		"unparsable resource in base": {
+			files: map[string]string{
+				"kustomization.yaml":      "resources:\n- base",
+				"base/kustomization.yaml": "resources:\n- cm.yaml",
+				"base/cm.yaml":            "apiVersion: v1\nkind: ConfigMap\nmetadata: {",
+			},
+			expected: types.ErrorLocation{File: "/app/base/cm.yaml"},
+		},
+		"missing resource": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- missing.yaml",
+			},
+			expected: types.ErrorLocation{
+				File: "/app/kustomization.yaml", FieldPath: "resources"},
+		},
+		"duplicate resource": {
+			files: map[string]string{
+				"kustomization.yaml": "resources:\n- cm.yaml",
+				"cm.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`,
+			},
+			expected: types.ErrorLocation{
+				File: "/app/cm.yaml", ResourceId: "~G_v1_ConfigMap|~X|cm"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			th := kusttest_test.MakeHarness(t)
+			for f, c := range tc.files {
+				th.WriteF("/app/"+f, c)
+			}
+			err := th.RunWithErr("/app", th.MakeDefaultOptions())
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if loc := types.ErrorLocationOf(err); loc != tc.expected {
+				t.Fatalf("expected %v, got %v for error: %v", tc.expected, loc, err)
+			}
+		})
+	}
+}
+
+func TestAggregatedErrors(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+apiVersion: v2
+kind: Whatever
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	errs := types.AggregatedErrors(err)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	for i, field := range []string{"kind", "apiVersion"} {
+		if loc := types.ErrorLocationOf(errs[i]); loc.FieldPath != field {
+			t.Errorf("expected field %s, got %v", field, loc)
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package krusty_test
 
 import (
+	"io/ioutil"
 	"os/exec"
+	"strings"
 	"testing"
+	"time"
 
+	"sigs.k8s.io/kustomize/api/konfig"
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
 func TestFnExecGenerator(t *testing.T) {
@@ -83,6 +88,55 @@ spec:
 `)
 }
 
+// The container of a function out of time is killed
+// along with the docker client running it.
+func TestFnContainerKilledOnTimeout(t *testing.T) {
+	log, cleanup := installFakeDocker(t, "sleep 30")
+	defer cleanup()
+
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+generators:
+- gen.yaml
+`)
+	th.WriteF("/app/gen.yaml", `
+apiVersion: example.com/v1
+kind: Generator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/gen:v1
+`)
+	o := th.MakeDefaultOptions()
+	o.PluginConfig = konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked,
+		konfig.NoPluginHomeSentinal)
+	o.PluginConfig.Limits.Timeout = 100 * time.Millisecond
+	err := th.RunWithErr("/app", o)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	args, err := ioutil.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	for _, line := range strings.Split(string(args), "\n") {
+		if f := strings.Fields(line); len(f) > 2 && f[0] == "run" && f[1] == "--name" {
+			name = f[2]
+		}
+	}
+	if name == "" {
+		t.Fatalf("expected a named container, got %s", args)
+	}
+	if !strings.Contains(string(args), "kill "+name+"\n") {
+		t.Fatalf("expected the container to be killed, got %s", args)
+	}
+}
+
 func skipIfNoDocker(t *testing.T) {
 	if _, err := exec.LookPath("docker"); err != nil {
 		t.Skip("skipping because docker binary wasn't found in PATH")
@@ -0,0 +1,68 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeGoTemplateGenerator(th kusttest_test.Harness) {
+	th.WriteK("/app", `
+namePrefix: dev-
+generators:
+- gen.yaml
+`)
+	th.WriteF("/app/gen.yaml", `
+apiVersion: builtin
+kind: GoTemplateGenerator
+metadata:
+  name: app
+values:
+  name: app
+  env:
+    LOG_LEVEL: debug
+    MODE: fast
+templateFiles:
+- config.yaml.tmpl
+`)
+	th.WriteF("/app/config.yaml.tmpl", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .name }}
+data:
+{{- range $k := keys .env }}
+  {{ $k }}: {{ index $.env $k | quote }}
+{{- end }}
+`)
+}
+
+func TestGoTemplateGenerator(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeGoTemplateGenerator(th)
+	options := th.MakeDefaultOptions()
+	options.PluginConfig.EnableGoTemplate = true
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  LOG_LEVEL: debug
+  MODE: fast
+kind: ConfigMap
+metadata:
+  name: dev-app
+`)
+}
+
+func TestGoTemplateGeneratorDisabled(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeGoTemplateGenerator(th)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil || !strings.Contains(err.Error(), "GoTemplateGenerator is disabled") {
+		t.Fatalf("expected disabled error, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/buildcache"
+	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/api/provenance"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// cachedBuild is the output of a build, as cached.
+type cachedBuild struct {
+	Output []byte `json:"output"`
+	// Origins holds the origin of each resource
+	// of the output, in order.
+	Origins  []string        `json:"origins,omitempty"`
+	Findings []types.Finding `json:"findings,omitempty"`
+}
+
+// optionsKey returns a key telling apart builds whose
+// options or kustomize version differ, or "" if they
+// can't be told apart.
+func (b *Kustomizer) optionsKey() string {
+	o := b.options
+	k, err := json.Marshal(struct {
+		Version string
+		Options Options
+	}{
+		Version: provenance.GetProvenance().Version,
+		Options: Options{
+			DoLegacyResourceSort:   o.DoLegacyResourceSort,
+			AddManagedbyLabel:      o.AddManagedbyLabel,
+			LoadRestrictions:       o.LoadRestrictions,
+			DoPrune:                o.DoPrune,
+			PluginConfig:           o.PluginConfig,
+			UseKyaml:               o.UseKyaml,
+			DetectLossyConversions: o.DetectLossyConversions,
+			StrictPatch:            o.StrictPatch,
+			FailOn:                 o.FailOn,
+			ApplySet:               o.ApplySet,
+			PruneLabels:            o.PruneLabels,
+			Sandbox:                o.Sandbox,
+			SandboxImage:           o.SandboxImage,
+			RequireCatalog:         o.RequireCatalog,
+			Catalogs:               o.Catalogs,
+		},
+	})
+	if err != nil {
+		return ""
+	}
+	return string(k)
+}
+
+// buildKey returns the key under which the
+// output of the build of path is cached.
+func buildKey(optionsKey, path string) string {
+	if _, err := git.NewRepoSpecFromUrl(path); err != nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+	}
+	return optionsKey + "\x00" + path
+}
+
+// readCachedBuild returns the output of the build of
+// path, if cached and its inputs are unchanged, adding
+// the findings of its validators to the report.
+func (b *Kustomizer) readCachedBuild(
+	f *resmap.Factory, key string) (resmap.ResMap, bool) {
+	data, found := b.options.BuildCache.Read(key, b.fSys)
+	if !found {
+		return nil, false
+	}
+	var c cachedBuild
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, false
+	}
+	m, err := f.NewResMapFromBytes(c.Output)
+	if err != nil || m.Size() != len(c.Origins) {
+		return nil, false
+	}
+	for i, r := range m.Resources() {
+		r.SetOrigin(c.Origins[i])
+	}
+	if b.options.ValidationReport != nil {
+		b.options.ValidationReport.Findings = append(
+			b.options.ValidationReport.Findings, c.Findings...)
+	}
+	return m, true
+}
+
+// writeCachedBuild caches the output of a build, with
+// the findings its validators added to the report
+// since it held the given number of them, ignoring
+// failures, which only cost building again.
+func (b *Kustomizer) writeCachedBuild(
+	key string, rec *buildcache.Recorder, m resmap.ResMap, findings int) {
+	output, err := m.AsYaml()
+	if err != nil {
+		return
+	}
+	c := cachedBuild{Output: output}
+	for _, r := range m.Resources() {
+		c.Origins = append(c.Origins, r.GetOrigin())
+	}
+	if b.options.ValidationReport != nil {
+		c.Findings = b.options.ValidationReport.Findings[findings:]
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	_ = b.options.BuildCache.Write(key, rec.Manifest(), data)
+}
+
+// recordCatalogs records the catalogs of approved
+// plugins, which may change what a build may run.
+func (b *Kustomizer) recordCatalogs(rec *buildcache.Recorder) {
+	for _, s := range b.options.Catalogs {
+		for _, path := range []string{s.URL, s.PublicKey} {
+			if strings.HasPrefix(path, "http://") ||
+				strings.HasPrefix(path, "https://") {
+				rec.Volatile(path)
+				continue
+			}
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			content, err := b.fSys.ReadFile(path)
+			rec.File(path, content, err)
+		}
+	}
+}
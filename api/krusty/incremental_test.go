@@ -0,0 +1,155 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"os"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/buildcache"
+	"sigs.k8s.io/kustomize/api/profile"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// loaded returns the roots of the kustomizations
+// the build profiled by p loaded.
+func loaded(p *profile.Profile) map[string]bool {
+	result := make(map[string]bool)
+	for _, e := range p.Entries() {
+		if e.Phase == profile.PhaseLoad {
+			result[e.Root] = true
+		}
+	}
+	return result
+}
+
+func TestIncrementalBuild(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`)
+	th.WriteF("/app/base/app.env", "color=blue\n")
+	th.WriteK("/app/base", `
+resources:
+- service.yaml
+configMapGenerator:
+- name: cm
+  envs:
+  - app.env
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: dev-
+resources:
+- ../base
+`)
+	options := th.MakeDefaultOptions()
+	options.BuildCache = buildcache.New("")
+	run := func() (string, map[string]bool) {
+		o := options
+		o.Profile = profile.New()
+		m := th.Run("/app/overlay", o)
+		y, err := m.AsYaml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(y), loaded(o.Profile)
+	}
+	build := func() string {
+		m := th.Run("/app/overlay", th.MakeDefaultOptions())
+		y, err := m.AsYaml()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(y)
+	}
+
+	out, seen := run()
+	if out != build() {
+		t.Fatalf("expected\n%s\ngot\n%s", build(), out)
+	}
+	if !seen["/app/base"] || !seen["/app/overlay"] {
+		t.Fatalf("expected both kustomizations loaded, got %v", seen)
+	}
+
+	again, seen := run()
+	if again != out {
+		t.Fatalf("expected\n%s\ngot\n%s", out, again)
+	}
+	if len(seen) != 0 {
+		t.Fatalf("expected the output reused, got %v loaded", seen)
+	}
+
+	th.WriteK("/app/overlay", `
+namePrefix: prod-
+resources:
+- ../base
+`)
+	out, seen = run()
+	if out != build() {
+		t.Fatalf("expected\n%s\ngot\n%s", build(), out)
+	}
+	if seen["/app/base"] || !seen["/app/overlay"] {
+		t.Fatalf("expected only the overlay loaded, got %v", seen)
+	}
+
+	th.WriteF("/app/base/app.env", "color=red\n")
+	out, seen = run()
+	if out != build() {
+		t.Fatalf("expected\n%s\ngot\n%s", build(), out)
+	}
+	if !seen["/app/base"] {
+		t.Fatalf("expected the base loaded again, got %v", seen)
+	}
+	th.AssertActualEqualsExpected(th.Run("/app/overlay", options), `
+apiVersion: v1
+kind: Service
+metadata:
+  name: prod-myService
+---
+apiVersion: v1
+data:
+  color: red
+kind: ConfigMap
+metadata:
+  name: prod-cm-d7568b82h8
+`)
+}
+
+// A value read from the environment, by a line of an env
+// file without "=", is rebuilt once the variable changes.
+func TestIncrementalBuildEnv(t *testing.T) {
+	const name = "KUSTOMIZE_INCREMENTAL_TEST_COLOR"
+	defer os.Unsetenv(name)
+	os.Setenv(name, "blue")
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/app.env", name+"\n")
+	th.WriteK("/app", `
+configMapGenerator:
+- name: cm
+  envs:
+  - app.env
+`)
+	options := th.MakeDefaultOptions()
+	options.BuildCache = buildcache.New("")
+	th.AssertActualEqualsExpected(th.Run("/app", options), `
+apiVersion: v1
+data:
+  KUSTOMIZE_INCREMENTAL_TEST_COLOR: blue
+kind: ConfigMap
+metadata:
+  name: cm-4bcf99d4bm
+`)
+	os.Setenv(name, "red")
+	th.AssertActualEqualsExpected(th.Run("/app", options), `
+apiVersion: v1
+data:
+  KUSTOMIZE_INCREMENTAL_TEST_COLOR: red
+kind: ConfigMap
+metadata:
+  name: cm-6tf2466f88
+`)
+}
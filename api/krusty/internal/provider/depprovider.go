@@ -90,10 +90,14 @@ import (
 //            This adapts sigs.k8s.io/kustomize/kyaml/yaml.RNode
 //            to ifc.Unstructured.
 //
-//            At time of writing, implementation started.
+//            At time of writing, fully implemented.  Filters
+//            change the held RNode in place, keeping field
+//            order, comments and quoting.  What still goes
+//            through maps or JSON can be found with
+//            --detect-lossy-conversions.
 //            Further reducing the size of ifc.Kunstructed
-//            would really reduce the work
-//            (e.g. drop Vars, drop ReplacementTranformer).
+//            would remove the remaining conversions
+//            (e.g. drop Map, drop Vars).
 //
 //   - resmap.Merginator
 //
@@ -105,7 +109,9 @@ import (
 //
 //       2) api/internal/merge.Merginator
 //
-//            At time of writing, this is unimplemented.
+//            Uses sigs.k8s.io/kustomize/kyaml/yaml/merge2
+//            to merge resource.Resource instances held
+//            in yaml.RNodes.
 //
 //   - ifc.Validator
 //
@@ -120,7 +126,7 @@ import (
 //            validator as it's not critical to kustomize function.
 //
 // Proposed plan:
-//  [ ] Ship kustomize with the ability to switch from 1 to 2 via
+//  [x] Ship kustomize with the ability to switch from 1 to 2 via
 //      an --enable_kyaml flag.
 //  [ ] Make --enable_kyaml true by default.
 //  [ ] When 2 is not noticeably more buggy than 1, delete 1.
@@ -141,6 +147,7 @@ type DepProvider struct {
 	resourceFactory *resource.Factory
 	merginator      resmap.Merginator
 	fieldValidator  ifc.Validator
+	conversions     *wrappy.Conversions
 }
 
 func makeK8sdepBasedInstances() *DepProvider {
@@ -153,19 +160,27 @@ func makeK8sdepBasedInstances() *DepProvider {
 	}
 }
 
-func makeKyamlBasedInstances() *DepProvider {
+func makeKyamlBasedInstances(detectLossyConversions bool) *DepProvider {
 	kf := &wrappy.WNodeFactory{}
+	if detectLossyConversions {
+		kf.Conversions = &wrappy.Conversions{}
+	}
 	rf := resource.NewFactory(kf)
 	return &DepProvider{
 		resourceFactory: rf,
 		merginator:      kmerge.NewMerginator(rf),
 		fieldValidator:  validate.NewFieldValidator(),
+		conversions:     kf.Conversions,
 	}
 }
 
-func NewDepProvider(useKyaml bool) *DepProvider {
+// NewDepProvider returns the instances backed by kyaml
+// code if useKyaml is true, recording lossy conversions
+// of resources if detectLossyConversions is true too,
+// or those backed by k8sdeps code otherwise.
+func NewDepProvider(useKyaml, detectLossyConversions bool) *DepProvider {
 	if useKyaml {
-		return makeKyamlBasedInstances()
+		return makeKyamlBasedInstances(detectLossyConversions)
 	}
 	return makeK8sdepBasedInstances()
 }
@@ -181,3 +196,9 @@ func (dp *DepProvider) GetMerginator() resmap.Merginator {
 func (dp *DepProvider) GetFieldValidator() ifc.Validator {
 	return dp.fieldValidator
 }
+
+// GetConversions returns the record of lossy conversions
+// of resources, or nil if they aren't recorded.
+func (dp *DepProvider) GetConversions() *wrappy.Conversions {
+	return dp.conversions
+}
@@ -5,14 +5,21 @@ package krusty
 
 import (
 	"fmt"
+	"strings"
 
+	"sigs.k8s.io/kustomize/api/applyset"
+	"sigs.k8s.io/kustomize/api/buildcache"
 	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/catalog"
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/git"
 	pLdr "sigs.k8s.io/kustomize/api/internal/plugins/loader"
 	"sigs.k8s.io/kustomize/api/internal/target"
 	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/api/krusty/internal/provider"
 	fLdr "sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/profile"
 	"sigs.k8s.io/kustomize/api/provenance"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
@@ -35,7 +42,7 @@ func MakeKustomizer(fSys filesys.FileSystem, o *Options) *Kustomizer {
 	return &Kustomizer{
 		fSys:        fSys,
 		options:     o,
-		depProvider: provider.NewDepProvider(o.UseKyaml),
+		depProvider: provider.NewDepProvider(o.UseKyaml, o.DetectLossyConversions),
 	}
 }
 
@@ -51,6 +58,9 @@ func MakeKustomizer(fSys filesys.FileSystem, o *Options) *Kustomizer {
 // on any number of internal paths (e.g. the filesystem may contain
 // multiple overlays, and Run can be called on each of them).
 func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
+	if b.options.DetectLossyConversions && !b.options.UseKyaml {
+		return nil, fmt.Errorf("detecting lossy conversions requires UseKyaml")
+	}
 	resmapFactory := resmap.NewFactory(
 		b.depProvider.GetResourceFactory(),
 		b.depProvider.GetMerginator())
@@ -58,17 +68,86 @@ func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
 	if b.options.LoadRestrictions == types.LoadRestrictionsRootOnly {
 		lr = fLdr.RestrictionRootOnly
 	}
-	ldr, err := fLdr.NewLoader(lr, path, b.fSys)
+	var optionsKey, key string
+	var rec *buildcache.Recorder
+	if b.options.BuildCache != nil && b.options.Trace == nil {
+		if optionsKey = b.optionsKey(); optionsKey != "" {
+			key = buildKey(optionsKey, path)
+			if m, found := b.readCachedBuild(resmapFactory, key); found {
+				return m, nil
+			}
+			rec = buildcache.NewRecorder()
+		}
+	}
+	var ldr ifc.Loader
+	var err error
+	pc := b.options.PluginConfig
+	stopFetch := func() {}
+	if _, errGit := git.NewRepoSpecFromUrl(path); errGit == nil {
+		stopFetch = b.options.Profile.Start(profile.PhaseFetch, "", path)
+	}
+	switch {
+	case b.options.Sandbox:
+		image := b.options.SandboxImage
+		if image == "" {
+			image = konfig.DefaultSandboxImage
+		}
+		ldr, err = fLdr.NewSandboxedLoader(
+			lr, path, b.fSys, b.options.RepoCache, image)
+		pc = sandboxedPluginConfig(pc)
+	case b.options.RepoCache != nil:
+		ldr, err = fLdr.NewLoaderWithRepoCache(lr, path, b.fSys, b.options.RepoCache)
+	default:
+		ldr, err = fLdr.NewLoader(lr, path, b.fSys)
+	}
+	stopFetch()
 	if err != nil {
-		return nil, err
+		kind := types.BuildErrorMissingFile
+		if _, errGit := git.NewRepoSpecFromUrl(path); errGit == nil {
+			kind = types.BuildErrorRemoteFetch
+		}
+		return nil, types.NewErrBuild(kind, err)
 	}
 	defer ldr.Cleanup()
+	if rec != nil {
+		if _, errGit := git.NewRepoSpecFromUrl(path); errGit == nil {
+			ldr = rec.RemoteLoader(ldr, path)
+		} else {
+			ldr = rec.Loader(ldr)
+		}
+		if b.options.RequireCatalog {
+			b.recordCatalogs(rec)
+		}
+	}
+	if b.options.RequireCatalog {
+		pc, err = cataloguedPluginConfig(b.fSys, pc, b.options.Catalogs)
+		if err != nil {
+			return nil, types.NewErrBuild(types.BuildErrorPlugin, err)
+		}
+	}
 	kt := target.NewKustTarget(
 		ldr,
 		b.depProvider.GetFieldValidator(),
 		resmapFactory,
-		pLdr.NewLoader(b.options.PluginConfig, resmapFactory),
+		pLdr.NewLoader(pc, resmapFactory),
 	)
+	defer kt.RestoreSchemas()
+	kt.SetProfile(b.options.Profile)
+	kt.SetTrace(b.options.Trace)
+	kt.SetValidation(b.options.ValidationReport, b.options.FailOn)
+	kt.SetStrictPatch(b.options.StrictPatch)
+	findings := 0
+	if rec != nil {
+		kt.SetCache(b.options.BuildCache, b.fSys, rec, optionsKey)
+		if b.options.ValidationReport != nil {
+			findings = len(b.options.ValidationReport.Findings)
+		}
+	}
+	conversions := b.depProvider.GetConversions()
+	lossy := 0
+	if conversions != nil {
+		lossy = len(conversions.Lossy())
+	}
 	err = kt.Load()
 	if err != nil {
 		return nil, err
@@ -78,8 +157,17 @@ func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
 	if err != nil {
 		return nil, err
 	}
+	if b.options.ApplySet != nil {
+		err = applyset.Stamp(m, resmapFactory.RF(), *b.options.ApplySet)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if b.options.DoLegacyResourceSort {
+		stop := b.options.Profile.Start(
+			profile.PhaseTransformer, ldr.Root(), "LegacyOrderTransformer")
 		builtins.NewLegacyOrderTransformerPlugin().Transform(m)
+		stop()
 	}
 	if b.options.AddManagedbyLabel {
 		t := builtins.LabelTransformerPlugin{
@@ -91,7 +179,64 @@ func (b *Kustomizer) Run(path string) (resmap.ResMap, error) {
 				CreateIfNotPresent: true,
 			}},
 		}
+		record := b.options.Trace.Start(ldr.Root(), "LabelTransformer", m)
+		t.Transform(m)
+		record()
+	}
+	if len(b.options.PruneLabels) > 0 {
+		t := builtins.LabelTransformerPlugin{
+			Labels: b.options.PruneLabels,
+			FieldSpecs: []types.FieldSpec{{
+				Path:               "metadata/labels",
+				CreateIfNotPresent: true,
+			}},
+		}
+		record := b.options.Trace.Start(ldr.Root(), "LabelTransformer", m)
 		t.Transform(m)
+		record()
+	}
+	if conversions != nil {
+		if l := conversions.Lossy()[lossy:]; len(l) > 0 {
+			return nil, fmt.Errorf(
+				"lossy conversions of resources:\n  %s", strings.Join(l, "\n  "))
+		}
+	}
+	if rec != nil {
+		b.writeCachedBuild(key, rec, m, findings)
 	}
 	return m, nil
 }
+
+// sandboxedPluginConfig returns a copy of the config
+// letting plugins run only in containers without network
+// access or host mounts, leaving builtins-only configs
+// as they are.
+func sandboxedPluginConfig(pc *types.PluginConfig) *types.PluginConfig {
+	result := *pc
+	if result.PluginRestrictions == types.PluginRestrictionsNone {
+		result.PluginRestrictions = types.PluginRestrictionsContainersOnly
+	}
+	result.FnpLoadingOptions.EnableExec = false
+	result.FnpLoadingOptions.EnableStar = false
+	result.FnpLoadingOptions.Network = false
+	result.FnpLoadingOptions.Mounts = nil
+	return &result
+}
+
+// cataloguedPluginConfig returns a copy of the config
+// letting only the plugins approved by the catalogs
+// be loaded, besides builtins.
+func cataloguedPluginConfig(
+	fSys filesys.FileSystem, pc *types.PluginConfig,
+	sources []catalog.Source) (*types.PluginConfig, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("a catalog is required, but none is configured")
+	}
+	c, err := catalog.Load(fSys, sources)
+	if err != nil {
+		return nil, err
+	}
+	result := *pc
+	result.Catalog = c
+	return &result, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeKyamlBase(th kusttest_test.Harness) {
+	th.WriteF("/app/base/deployment.yaml", `
+# the app
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app # the main container
+        image: app
+        args: ["--port", "8080"]
+        env:
+        - name: MODE
+          value: "on"
+`)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+namePrefix: prod-
+commonLabels:
+  env: prod
+images:
+- name: app
+  newTag: v1
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/overlay/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+`)
+}
+
+func TestKyamlKeepsOrderAndComments(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeKyamlBase(th)
+	opts := th.MakeDefaultOptions()
+	opts.UseKyaml = true
+	opts.DetectLossyConversions = true
+	m := th.Run("/app/overlay", opts)
+	th.AssertActualEqualsExpected(m, `
+# the app
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-app
+  labels:
+    env: prod
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app # the main container
+        image: app:v1
+        args: ["--port", "8080"]
+        env:
+        - name: MODE
+          value: "on"
+    metadata:
+      labels:
+        env: prod
+  selector:
+    matchLabels:
+      env: prod
+`)
+}
+
+func TestDetectLossyConversionsRequiresKyaml(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeKyamlBase(th)
+	opts := th.MakeDefaultOptions()
+	opts.DetectLossyConversions = true
+	err := th.RunWithErr("/app/overlay", opts)
+	if !strings.Contains(err.Error(), "requires UseKyaml") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
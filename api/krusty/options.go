@@ -4,7 +4,13 @@
 package krusty
 
 import (
+	"sigs.k8s.io/kustomize/api/applyset"
+	"sigs.k8s.io/kustomize/api/buildcache"
+	"sigs.k8s.io/kustomize/api/catalog"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/profile"
+	"sigs.k8s.io/kustomize/api/trace"
 	"sigs.k8s.io/kustomize/api/types"
 )
 
@@ -36,6 +42,76 @@ type Options struct {
 	// When true, use kyaml/ packages to manipulate KRM yaml.
 	// When false, use k8sdeps/ instead (uses k8s.io/api* packages).
 	UseKyaml bool
+
+	// When true, with UseKyaml, the build fails if resources
+	// are converted to maps or JSON and back in a way losing
+	// the order of their fields, their comments or the style
+	// of their values, listing the conversions.
+	DetectLossyConversions bool
+
+	// When true, strategic merge patches fail, rather
+	// than being merged as well as can be, if they set
+	// fields unknown to the schema of the kind of what
+	// they patch, e.g. because of a typo, or if that
+	// schema is unknown.
+	StrictPatch bool
+
+	// When non-nil, the time spent in each phase of
+	// the build is recorded here, and its observers
+	// are told of each step as it starts and ends.
+	Profile *profile.Profile
+
+	// When non-nil, the changes each transformer makes
+	// to each resource are recorded here.
+	Trace *trace.Trace
+
+	// When non-nil, the findings of the validators
+	// are added here.
+	ValidationReport *types.ValidationReport
+
+	// The build fails on findings of the validators
+	// at least this severe; error, if empty.
+	FailOn types.Severity
+
+	// When non-nil, the resources are made members of
+	// the ApplySet of this parent, which is added to them.
+	ApplySet *applyset.Parent
+
+	// Labels added to all the resources, to select
+	// them with "kubectl apply --prune -l".
+	PruneLabels map[string]string
+
+	// When non-nil, the clones of remote bases are taken
+	// from here, to share them among several runs.
+	// The caller cleans it up.
+	RepoCache *loader.RepoCache
+
+	// When true, nothing touching the network or the
+	// filesystem outside the kustomization runs on the
+	// host: remote bases are cloned in a container of
+	// SandboxImage, files aren't loaded over HTTP, and
+	// only builtins and container functions, without
+	// mounts, may be used as plugins.
+	Sandbox bool
+
+	// The image in which remote bases are cloned when
+	// Sandbox is true.  Defaults to DefaultSandboxImage.
+	SandboxImage string
+
+	// When true, plugins other than builtins may only be
+	// loaded if approved by one of the Catalogs, whose
+	// signatures are verified first.
+	RequireCatalog bool
+
+	// The signed catalogs of approved functions and plugins.
+	Catalogs []catalog.Source
+
+	// When non-nil, builds are incremental: the output,
+	// and the bases accumulated along the way, are put
+	// here with what they were made from, and reused by
+	// later builds as long as that is unchanged.
+	// Ignored when Trace is non-nil.
+	BuildCache *buildcache.Cache
 }
 
 // MakeDefaultOptions returns a default instance of Options.
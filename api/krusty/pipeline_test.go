@@ -0,0 +1,126 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const pipelineNamespaces = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-namespace
+`
+
+func TestPipelineMutator(t *testing.T) {
+	skipIfNoDocker(t)
+
+	th := kusttest_test.MakeEnhancedHarness(t)
+	defer th.Reset()
+
+	th.WriteK("/app", `
+resources:
+- ns.yaml
+pipeline:
+  mutators:
+  - image: gcr.io/kpt-functions/label-namespace@sha256:4f030738d6d25a207641ca517916431517578bd0eb8d98a8bde04e3bb9315dcd
+    configMap:
+      label_name: my-ns-name
+      label_value: function-test
+`)
+	th.WriteF("/app/ns.yaml", pipelineNamespaces)
+	m := th.Run("/app", th.MakeOptionsPluginsEnabled())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  annotations:
+    config.kubernetes.io/path: namespace_my-namespace.yaml
+  labels:
+    my-ns-name: function-test
+  name: my-namespace
+`)
+}
+
+func TestPipelineWithPluginsDisabled(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- ns.yaml
+pipeline:
+  validators:
+  - image: gcr.io/kpt-functions/kubeval
+    configPath: kubeval.yaml
+`)
+	th.WriteF("/app/ns.yaml", pipelineNamespaces)
+	th.WriteF("/app/kubeval.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kubeval
+data:
+  strict: "true"
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if !strings.Contains(err.Error(), "external plugins disabled") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPipelineBadFunction(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- ns.yaml
+pipeline:
+  mutators:
+  - image: gcr.io/kpt-functions/label-namespace
+    configPath: config.yaml
+    configMap:
+      label_name: my-ns-name
+`)
+	th.WriteF("/app/ns.yaml", pipelineNamespaces)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if !strings.Contains(err.Error(),
+		"pipeline function gcr.io/kpt-functions/label-namespace "+
+			"may have a configPath or a configMap, not both") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// The image of a pipeline function can't inject
+// fields into the spec of the function.
+func TestPipelineImageInjection(t *testing.T) {
+	// The containers echo their input.
+	log, cleanup := installFakeDocker(t, "cat")
+	defer cleanup()
+
+	th := kusttest_test.MakeEnhancedHarness(t)
+	defer th.Reset()
+	th.WriteK("/app", `
+resources:
+- ns.yaml
+pipeline:
+  mutators:
+  - image: "example.com/fn:v1\n  envs:\n  - INJECTED=true"
+`)
+	th.WriteF("/app/ns.yaml", pipelineNamespaces)
+	th.Run("/app", th.MakeOptionsPluginsEnabled())
+
+	args, err := ioutil.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(args), "run ") {
+		t.Fatalf("expected the container to run, got %s", args)
+	}
+	if strings.Contains(string(args), "-e INJECTED=true") {
+		t.Fatalf("expected no injected variable, got %s", args)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const slowGenerator = `#!/bin/sh
+if [ -n "$SLOW" ]; then
+  sleep 10
+fi
+cat <<EOF
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: slow
+EOF
+`
+
+func TestPluginLimits(t *testing.T) {
+	home, err := ioutil.TempDir("", "kustomize-plugins-limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	dir := filepath.Join(home, "example.com", "v1", "slowgenerator")
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "SlowGenerator"), []byte(slowGenerator), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		annotations string
+		env         string
+		limits      types.PluginLimits
+		expectedErr string
+	}{
+		"within limits": {
+			limits: types.DefaultPluginLimits(),
+		},
+		"timed out": {
+			annotations: `
+  annotations:
+    kustomize.config.k8s.io/plugin-timeout: 500ms`,
+			env:         "1",
+			limits:      types.DefaultPluginLimits(),
+			expectedErr: "timed out after 500ms",
+		},
+		"too much output": {
+			limits:      types.PluginLimits{MaxOutput: 16},
+			expectedErr: "wrote more than the 16 bytes of output allowed",
+		},
+		"output allowed by annotation": {
+			annotations: `
+  annotations:
+    kustomize.config.k8s.io/plugin-max-output: 1Ki`,
+			limits: types.PluginLimits{MaxOutput: 16},
+		},
+		"illegal annotation": {
+			annotations: `
+  annotations:
+    kustomize.config.k8s.io/plugin-max-memory: lots`,
+			limits:      types.DefaultPluginLimits(),
+			expectedErr: `"lots" isn't a size`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv("SLOW", tc.env)
+			defer os.Unsetenv("SLOW")
+			th := kusttest_test.MakeHarness(t)
+			th.WriteK("/app", `
+generators:
+- generator.yaml
+`)
+			th.WriteF("/app/generator.yaml", `
+apiVersion: example.com/v1
+kind: SlowGenerator
+metadata:
+  name: gen`+tc.annotations+`
+`)
+			options := th.MakeDefaultOptions()
+			options.PluginConfig = konfig.MakePluginConfig(
+				types.PluginRestrictionsNone, types.BploUseStaticallyLinked, home)
+			options.PluginConfig.Limits = tc.limits
+			if tc.expectedErr == "" {
+				m := th.Run("/app", options)
+				th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: slow
+`)
+				return
+			}
+			err := th.RunWithErr("/app", options)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
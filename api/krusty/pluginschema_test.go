@@ -0,0 +1,206 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const routeGenerator = `#!/bin/sh
+cat <<EOF
+apiVersion: example.com/v1
+kind: Route
+metadata:
+  name: route
+spec:
+  configMapRef:
+    name: routes
+  rules:
+  - host: a.example.com
+    port: 80
+  - host: b.example.com
+    port: 80
+EOF
+`
+
+const routeSchema = `
+definitions:
+  com.example.v1.Route:
+    x-kubernetes-group-version-kind:
+    - group: example.com
+      version: v1
+      kind: Route
+    properties:
+      apiVersion:
+        type: string
+      kind:
+        type: string
+      metadata:
+        type: object
+      spec:
+        $ref: '#/definitions/com.example.v1.RouteSpec'
+  com.example.v1.RouteSpec:
+    properties:
+      configMapRef:
+        x-kubernetes-object-ref-api-version: v1
+        x-kubernetes-object-ref-kind: ConfigMap
+        type: object
+      rules:
+        type: array
+        x-kubernetes-patch-merge-key: host
+        x-kubernetes-patch-strategy: merge
+        items:
+          type: object
+`
+
+// makeRoutePluginHome returns a plugin home holding
+// the plugin generating Routes, and their schema.
+func makeRoutePluginHome(t *testing.T) string {
+	home, err := ioutil.TempDir("", "kustomize-plugins-schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(home, "example.com", "v1", "routegenerator")
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "RouteGenerator"), []byte(routeGenerator), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "RouteGenerator"+konfig.PluginSchemaSuffix),
+		[]byte(routeSchema), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+// The schema declared next to the plugin generating Routes
+// lets them refer to ConfigMaps by name and be patched.
+func TestPluginSchema(t *testing.T) {
+	home := makeRoutePluginHome(t)
+	defer os.RemoveAll(home)
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+namePrefix: x-
+configMapGenerator:
+- name: routes
+  literals:
+  - timeout=5s
+generators:
+- generator.yaml
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/generator.yaml", `
+apiVersion: example.com/v1
+kind: RouteGenerator
+metadata:
+  name: gen
+`)
+	th.WriteF("/app/patch.yaml", `
+apiVersion: example.com/v1
+kind: Route
+metadata:
+  name: route
+spec:
+  rules:
+  - host: b.example.com
+    port: 443
+`)
+	options := th.MakeDefaultOptions()
+	options.PluginConfig = konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked, home)
+	m := th.Run("/app", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  timeout: 5s
+kind: ConfigMap
+metadata:
+  name: x-routes-g2h2dkt9d2
+---
+apiVersion: example.com/v1
+kind: Route
+metadata:
+  name: x-route
+spec:
+  configMapRef:
+    name: x-routes-g2h2dkt9d2
+  rules:
+  - host: a.example.com
+    port: 80
+  - host: b.example.com
+    port: 443
+`)
+}
+
+// The schema applies only to the builds using the plugin.
+func TestPluginSchemaScopedToBuild(t *testing.T) {
+	home := makeRoutePluginHome(t)
+	defer os.RemoveAll(home)
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+generators:
+- generator.yaml
+`)
+	th.WriteF("/app/generator.yaml", `
+apiVersion: example.com/v1
+kind: RouteGenerator
+metadata:
+  name: gen
+`)
+	th.WriteK("/other", `
+resources:
+- route.yaml
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/other/route.yaml", `
+apiVersion: example.com/v1
+kind: Route
+metadata:
+  name: route
+spec:
+  rules:
+  - host: a.example.com
+    port: 80
+`)
+	th.WriteF("/other/patch.yaml", `
+apiVersion: example.com/v1
+kind: Route
+metadata:
+  name: route
+spec:
+  rules:
+  - host: b.example.com
+    port: 443
+`)
+	options := th.MakeDefaultOptions()
+	options.PluginConfig = konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked, home)
+	th.Run("/app", options)
+	// Without the schema, the patch replaces the rules.
+	m := th.Run("/other", options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: Route
+metadata:
+  name: route
+spec:
+  rules:
+  - host: b.example.com
+    port: 443
+`)
+}
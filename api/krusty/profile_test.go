@@ -0,0 +1,102 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/profile"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestProfile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`)
+	th.WriteK("/app/base", `
+resources:
+- service.yaml
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: dev-
+resources:
+- ../base
+configMapGenerator:
+- name: cm
+  literals:
+  - a=b
+`)
+	options := th.MakeDefaultOptions()
+	options.Profile = profile.New()
+	th.Run("/app/overlay", options)
+
+	seen := make(map[string]bool)
+	for _, e := range options.Profile.Entries() {
+		seen[string(e.Phase)+" "+e.Root+" "+e.Name] = true
+	}
+	for _, s := range []string{
+		"load /app/base ",
+		"load /app/overlay ",
+		"kustomization /app/base ",
+		"kustomization /app/overlay ",
+		"generator /app/overlay ConfigMapGenerator",
+		"transformer /app/overlay PrefixSuffixTransformer",
+		"transformer /app/base PrefixSuffixTransformer",
+		"finalize /app/overlay ",
+	} {
+		if !seen[s] {
+			t.Errorf("expected entry %q in %v", s, seen)
+		}
+	}
+}
+
+// countingObserver counts the steps started and
+// ended, and records the durations of generators.
+type countingObserver struct {
+	started, ended int
+	generators     map[string]time.Duration
+}
+
+func (o *countingObserver) Started(profile.Entry) {
+	o.started++
+}
+
+func (o *countingObserver) Ended(e profile.Entry) {
+	o.ended++
+	if e.Phase == profile.PhaseGenerator {
+		o.generators[e.Name] = e.Duration
+	}
+}
+
+func TestProfileObserver(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+configMapGenerator:
+- name: cm
+  literals:
+  - a=b
+`)
+	o := &countingObserver{generators: make(map[string]time.Duration)}
+	options := th.MakeDefaultOptions()
+	options.Profile = profile.New(o)
+	th.Run("/app", options)
+
+	if o.started == 0 || o.started != o.ended {
+		t.Errorf("expected as many steps ended as started, got %d and %d",
+			o.started, o.ended)
+	}
+	if o.ended != len(options.Profile.Entries()) {
+		t.Errorf("expected an event per entry, got %d events and %d entries",
+			o.ended, len(options.Profile.Entries()))
+	}
+	if _, ok := o.generators["ConfigMapGenerator"]; !ok {
+		t.Errorf("expected the ConfigMapGenerator to be observed, got %v",
+			o.generators)
+	}
+}
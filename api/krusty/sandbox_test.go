@@ -0,0 +1,172 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// countingServer serves the content, counting the requests.
+func countingServer(content string) (*httptest.Server, *int32) {
+	var hits int32
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			fmt.Fprint(w, content)
+		})), &hits
+}
+
+func makeSandboxOptions(th kusttest_test.Harness) (o krusty.Options) {
+	o = th.MakeDefaultOptions()
+	o.PluginConfig = konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked,
+		konfig.NoPluginHomeSentinal)
+	o.Sandbox = true
+	return o
+}
+
+func TestSandboxRefusesHTTPResources(t *testing.T) {
+	server, hits := countingServer(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: remote
+`)
+	defer server.Close()
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- `+server.URL+`/service.yaml
+`)
+
+	// Outside a sandbox, the file is fetched.
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: remote
+`)
+	atomic.StoreInt32(hits, 0)
+
+	err := th.RunWithErr("/app", makeSandboxOptions(th))
+	if err == nil || !strings.Contains(err.Error(), "over HTTP in a sandbox") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(hits); n != 0 {
+		t.Fatalf("expected no request, got %d", n)
+	}
+}
+
+func TestSandboxRefusesStarlark(t *testing.T) {
+	server, hits := countingServer(`
+def run(items):
+  pass
+run(ctx.resource_list["items"])
+`)
+	defer server.Close()
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+generators:
+- gen.yaml
+`)
+	th.WriteF("/app/gen.yaml", `
+apiVersion: example.com/v1
+kind: Generator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      starlark:
+        url: `+server.URL+`/gen.star
+        name: gen
+`)
+	o := makeSandboxOptions(th)
+	o.PluginConfig.FnpLoadingOptions.EnableStar = true
+
+	err := th.RunWithErr("/app", o)
+	if err == nil || !strings.Contains(err.Error(), "would run on the host") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(hits); n != 0 {
+		t.Fatalf("expected no request, got %d", n)
+	}
+}
+
+// installFakeDocker puts first on the PATH a fake docker
+// logging its arguments to the returned file, whose
+// containers run the script, returning a function
+// undoing it.
+func installFakeDocker(t *testing.T, script string) (string, func()) {
+	dir, err := ioutil.TempDir("", "kustomize-docker-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "docker.log")
+	err = ioutil.WriteFile(filepath.Join(dir, "docker"), []byte(`#!/bin/sh
+echo "$@" >> `+log+`
+if [ "$1" = run ]; then
+  `+script+`
+fi
+`), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	return log, func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSandboxDropsMounts(t *testing.T) {
+	// The containers echo their input.
+	log, cleanup := installFakeDocker(t, "cat")
+	defer cleanup()
+
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+generators:
+- gen.yaml
+`)
+	th.WriteF("/app/gen.yaml", `
+apiVersion: example.com/v1
+kind: Generator
+metadata:
+  name: gen
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/gen:v1
+`)
+	o := makeSandboxOptions(th)
+	o.PluginConfig.FnpLoadingOptions.Mounts = []string{
+		"type=bind,src=/etc,dst=/host-etc"}
+	th.Run("/app", o)
+
+	args, err := ioutil.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(args), "run ") {
+		t.Fatalf("expected the container to run, got %s", args)
+	}
+	if strings.Contains(string(args), "--mount") {
+		t.Fatalf("expected no mounts in a sandbox, got %s", args)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeTypoedPatch(th kusttest_test.Harness) {
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+`)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/overlay/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        imagePullPolicy: Always
+        resource:
+          limits:
+            cpu: 100m
+`)
+}
+
+func TestStrictPatchOff(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeTypoedPatch(th)
+	m := th.Run("/app/overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - image: app
+        imagePullPolicy: Always
+        name: app
+        resource:
+          limits:
+            cpu: 100m
+`)
+}
+
+func TestStrictPatch(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeTypoedPatch(th)
+	opts := th.MakeDefaultOptions()
+	opts.StrictPatch = true
+	err := th.RunWithErr("/app/overlay", opts)
+	if !strings.Contains(err.Error(),
+		"unknown field spec.template.spec.containers[0].resource") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStrictPatchInBase(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeTypoedPatch(th)
+	th.WriteK("/app/top", `
+resources:
+- ../overlay
+namePrefix: top-
+`)
+	opts := th.MakeDefaultOptions()
+	opts.StrictPatch = true
+	err := th.RunWithErr("/app/top", opts)
+	if !strings.Contains(err.Error(), "strict patch of Deployment app") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStrictPatchWithPatches(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeTypoedPatch(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+patches:
+- path: patch.yaml
+  target:
+    kind: Deployment
+`)
+	opts := th.MakeDefaultOptions()
+	opts.StrictPatch = true
+	err := th.RunWithErr("/app/overlay", opts)
+	if !strings.Contains(err.Error(), "unknown field") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func writeCrdPatch(th kusttest_test.Harness, patch string) {
+	th.WriteK("/app", `
+crds:
+- crd.json
+resources:
+- bee.yaml
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/crd.json", `
+{
+  "github.com/example/pkg/apis/jingfang/v1beta1.Bee": {
+    "Schema": {
+      "properties": {
+        "apiVersion": {
+          "type": "string"
+        },
+        "kind": {
+          "type": "string"
+        },
+        "metadata": {
+          "$ref": "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"
+        },
+        "spec": {
+          "$ref": "github.com/example/pkg/apis/jingfang/v1beta1.BeeSpec"
+        }
+      }
+    }
+  },
+  "github.com/example/pkg/apis/jingfang/v1beta1.BeeSpec": {
+    "Schema": {
+      "properties": {
+        "action": {
+          "type": "string"
+        }
+      }
+    }
+  }
+}
+`)
+	th.WriteF("/app/bee.yaml", `
+apiVersion: v1beta1
+kind: Bee
+metadata:
+  name: bee
+spec:
+  action: fly
+`)
+	th.WriteF("/app/patch.yaml", patch)
+}
+
+func TestStrictPatchOfCrd(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeCrdPatch(th, `
+apiVersion: v1beta1
+kind: Bee
+metadata:
+  name: bee
+  labels:
+    app: honey
+spec:
+  action: makehoney
+`)
+	opts := th.MakeDefaultOptions()
+	opts.StrictPatch = true
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1beta1
+kind: Bee
+metadata:
+  labels:
+    app: honey
+  name: bee
+spec:
+  action: makehoney
+`)
+}
+
+func TestStrictPatchOfCrdTypo(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeCrdPatch(th, `
+apiVersion: v1beta1
+kind: Bee
+metadata:
+  name: bee
+spec:
+  acton: makehoney
+`)
+	opts := th.MakeDefaultOptions()
+	opts.StrictPatch = true
+	err := th.RunWithErr("/app", opts)
+	if !strings.Contains(err.Error(), "unknown field spec.acton") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"bytes"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/trace"
+)
+
+func TestTrace(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    tier: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1
+        envFrom:
+        - configMapRef:
+            name: cm
+`)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: cm
+  literals:
+  - a=b
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: dev-
+commonLabels:
+  tier: backend
+resources:
+- ../base
+`)
+	options := th.MakeDefaultOptions()
+	options.Trace = trace.New()
+	th.Run("/app/overlay", options)
+
+	var b bytes.Buffer
+	if err := options.Trace.WriteText(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apps_v1_Deployment|~X|app
+  PrefixSuffixTransformer (/app/overlay)
+    metadata.name: app -> dev-app
+  LabelTransformer (/app/overlay)
+    metadata.labels.tier: web -> backend
+    spec.selector.matchLabels.tier: + backend
+    spec.template.metadata.labels.tier: + backend
+  NameReferenceTransformer (/app/overlay)
+    spec.template.spec.containers[0].envFrom[0].configMapRef.name: cm -> dev-cm-4h2mbtbbt6
+~G_v1_ConfigMap|~X|cm
+  PrefixSuffixTransformer (/app/overlay)
+    metadata.name: cm -> dev-cm
+  LabelTransformer (/app/overlay)
+    metadata.labels.tier: + backend
+  HashTransformer (/app/overlay)
+    metadata.name: dev-cm -> dev-cm-4h2mbtbbt6
+`
+	if b.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestTransformerAnnotations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app
+        envFrom:
+        - configMapRef:
+            name: config
+`)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+- service.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - a=b
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+namePrefix: prod-
+images:
+- name: app
+  newTag: v1
+buildMetadata:
+- transformerAnnotations
+`)
+	m := th.Run("/app/overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: .
+      - configuredBy: ImageTagTransformer
+        configuredIn: .
+      - configuredBy: NameReferenceTransformer
+        configuredIn: .
+  name: prod-app
+spec:
+  template:
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: prod-config-4h2mbtbbt6
+        image: app:v1
+        name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: .
+  name: prod-app
+---
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: ConfigMapGenerator
+        configuredIn: ../base
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: .
+      - configuredBy: HashTransformer
+        configuredIn: .
+  name: prod-config-4h2mbtbbt6
+`)
+}
+
+func TestOriginAndTransformerAnnotations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`)
+	th.WriteK("/app/base", `
+resources:
+- service.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - a=b
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+namePrefix: prod-
+buildMetadata:
+- originAnnotations
+- transformerAnnotations
+`)
+	m := th.Run("/app/overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: .
+    config.kubernetes.io/origin: |
+      path: ../base/service.yaml
+  name: prod-app
+---
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  annotations:
+    alpha.config.kubernetes.io/transformations: |
+      - configuredBy: ConfigMapGenerator
+        configuredIn: ../base
+      - configuredBy: PrefixSuffixTransformer
+        configuredIn: .
+      - configuredBy: HashTransformer
+        configuredIn: .
+    config.kubernetes.io/origin: |
+      configuredBy: ConfigMapGenerator
+      configuredIn: ../base
+  name: prod-config-4h2mbtbbt6
+`)
+}
+
+func TestOriginAnnotations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`)
+	th.WriteK("/app", `
+resources:
+- service.yaml
+namePrefix: prod-
+buildMetadata:
+- originAnnotations
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    config.kubernetes.io/origin: |
+      path: service.yaml
+  name: prod-app
+`)
+}
+
+func TestBuildMetadataUnknownOption(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+buildMetadata:
+- managedByLabel
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"buildMetadata option managedByLabel is unknown; "+
+			"legal options: [originAnnotations transformerAnnotations]") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// greetingGenerator is a plugin exercising the
+// ABI of plugins compiled to WebAssembly.
+const greetingGenerator = `package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+func main() {
+	in, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if strings.Contains(string(in), "spin: true") {
+		for {
+		}
+	}
+	greeting, err := ioutil.ReadFile("greeting.txt")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	writable := ioutil.WriteFile("written.txt", nil, 0644) == nil
+	fmt.Printf(` + "`" + `apiVersion: config.kubernetes.io/v1alpha1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: greeting
+  data:
+    greeting: %q
+    name: %q
+    root: %q
+    writable: "%v"
+` + "`" + `, strings.TrimSpace(string(greeting)), os.Args[0],
+		os.Getenv("KUSTOMIZE_PLUGIN_CONFIG_ROOT"), writable)
+}
+`
+
+// buildWasmPlugin compiles the source of a plugin to
+// WebAssembly at path, skipping the test if the Go
+// toolchain can't target WASI.
+func buildWasmPlugin(t *testing.T, src, path string) {
+	dir, err := ioutil.TempDir("", "kustomize-wasm-src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "go.mod"), []byte("module example.com/plugin\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "build", "-o", path, ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm", "GOFLAGS=")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if strings.Contains(string(out), "unsupported GOOS/GOARCH") {
+			t.Skip("the Go toolchain can't target WASI")
+		}
+		t.Fatalf("compiling plugin: %v\n%s", err, out)
+	}
+}
+
+func TestWasmPlugin(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-wasm-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	home := filepath.Join(dir, "plugins")
+	buildWasmPlugin(t, greetingGenerator, filepath.Join(
+		home, "example.com", "v1", "greetinggenerator",
+		"GreetingGenerator"+konfig.WasmPluginSuffix))
+
+	th := kusttest_test.MakeHarnessWithFs(t, filesys.MakeFsOnDisk())
+	app := filepath.Join(dir, "app")
+	if err = os.MkdirAll(app, 0755); err != nil {
+		t.Fatal(err)
+	}
+	th.WriteK(app, `
+generators:
+- generator.yaml
+`)
+	th.WriteF(filepath.Join(app, "greeting.txt"), "hello\n")
+	th.WriteF(filepath.Join(app, "generator.yaml"), `
+apiVersion: example.com/v1
+kind: GreetingGenerator
+metadata:
+  name: gen
+`)
+	options := th.MakeDefaultOptions()
+	options.PluginConfig = konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked, home)
+	m := th.Run(app, options)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  greeting: hello
+  name: GreetingGenerator
+  root: /
+  writable: "false"
+kind: ConfigMap
+metadata:
+  name: greeting
+`)
+	if _, err = os.Stat(filepath.Join(app, "written.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected the plugin not to write, got %v", err)
+	}
+
+	th.WriteF(filepath.Join(app, "generator.yaml"), `
+apiVersion: example.com/v1
+kind: GreetingGenerator
+metadata:
+  name: gen
+  annotations:
+    kustomize.config.k8s.io/plugin-timeout: 1s
+spin: true
+`)
+	err = th.RunWithErr(app, options)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 1s") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	th.WriteF(filepath.Join(app, "generator.yaml"), `
+apiVersion: example.com/v1
+kind: GreetingGenerator
+metadata:
+  name: gen
+  annotations:
+    kustomize.config.k8s.io/plugin-max-output: "16"
+`)
+	err = th.RunWithErr(app, options)
+	if err == nil || !strings.Contains(err.Error(),
+		"wrote more than the 16 bytes of output allowed") {
+		t.Fatalf("expected too much output, got %v", err)
+	}
+}
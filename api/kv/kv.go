@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"runtime"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
@@ -37,6 +39,16 @@ func (kvl *loader) Validator() ifc.Validator {
 	return kvl.validator
 }
 
+// getenv returns the value of the environment variable,
+// through the file loader if it reads the environment,
+// e.g. to record what the build depends on.
+func (kvl *loader) getenv(key string) string {
+	if e, ok := kvl.ldr.(interface{ Getenv(string) string }); ok {
+		return e.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
 func (kvl *loader) Load(
 	args types.KvPairSources) (all []types.Pair, err error) {
 	pairs, err := kvl.keyValuesFromEnvFiles(args.EnvSources)
@@ -75,28 +87,33 @@ func keyValuesFromLiteralSources(sources []string) ([]types.Pair, error) {
 }
 
 func (kvl *loader) keyValuesFromFileSources(sources []string) ([]types.Pair, error) {
-	var kvs []types.Pair
-	for _, s := range sources {
+	keys := make([]string, len(sources))
+	paths := make([]string, len(sources))
+	for i, s := range sources {
 		k, fPath, err := parseFileSource(s)
 		if err != nil {
 			return nil, err
 		}
-		content, err := kvl.ldr.Load(fPath)
-		if err != nil {
-			return nil, err
-		}
-		kvs = append(kvs, types.Pair{Key: k, Value: string(content)})
+		keys[i], paths[i] = k, fPath
+	}
+	contents, err := kvl.loadAll(paths)
+	if err != nil {
+		return nil, err
+	}
+	var kvs []types.Pair
+	for i, k := range keys {
+		kvs = append(kvs, types.Pair{Key: k, Value: string(contents[i])})
 	}
 	return kvs, nil
 }
 
 func (kvl *loader) keyValuesFromEnvFiles(paths []string) ([]types.Pair, error) {
+	contents, err := kvl.loadAll(paths)
+	if err != nil {
+		return nil, err
+	}
 	var kvs []types.Pair
-	for _, p := range paths {
-		content, err := kvl.ldr.Load(p)
-		if err != nil {
-			return nil, err
-		}
+	for _, content := range contents {
 		more, err := kvl.keyValuesFromLines(content)
 		if err != nil {
 			return nil, err
@@ -106,6 +123,37 @@ func (kvl *loader) keyValuesFromEnvFiles(paths []string) ([]types.Pair, error) {
 	return kvs, nil
 }
 
+// loadAll loads the files at the paths concurrently, as
+// generators may read many large ones, returning their
+// contents in order, or the error loading the first that
+// fails.
+func (kvl *loader) loadAll(paths []string) ([][]byte, error) {
+	contents := make([][]byte, len(paths))
+	errs := make([]error, len(paths))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU() && w < len(paths); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				contents[i], errs[i] = kvl.ldr.Load(paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return contents, nil
+}
+
 // keyValuesFromLines parses given content in to a list of key-value pairs.
 func (kvl *loader) keyValuesFromLines(content []byte) ([]types.Pair, error) {
 	var kvs []types.Pair
@@ -165,7 +213,7 @@ func (kvl *loader) keyValuesFromLine(line []byte, currentLine int) (types.Pair,
 	} else {
 		// No value (no `=` in the line) is a signal to obtain the value
 		// from the environment.
-		kv.Value = os.Getenv(key)
+		kv.Value = kvl.getenv(key)
 	}
 	kv.Key = key
 	return kv, nil
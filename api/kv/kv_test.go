@@ -4,7 +4,9 @@
 package kv
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
@@ -95,3 +97,38 @@ func TestKeyValuesFromFileSources(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadKeepsOrder(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	var sources, envs []string
+	var expected []types.Pair
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("file%02d", i)
+		fSys.WriteFile("/files/"+name, []byte(name))
+		sources = append(sources, "files/"+name)
+		fSys.WriteFile("/envs/"+name, []byte("KEY"+name+"="+name))
+		envs = append(envs, "envs/"+name)
+	}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("file%02d", i)
+		expected = append(expected, types.Pair{Key: "KEY" + name, Value: name})
+	}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("file%02d", i)
+		expected = append(expected, types.Pair{Key: name, Value: name})
+	}
+	kvl := makeKvLoader(fSys)
+	kvs, err := kvl.Load(types.KvPairSources{EnvSources: envs, FileSources: sources})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(kvs, expected) {
+		t.Fatalf("expected:\n%v\nbut got:\n%v", expected, kvs)
+	}
+
+	sources = append(sources[:10], "files/missing1", "files/missing2")
+	_, err = kvl.keyValuesFromFileSources(sources)
+	if err == nil || !strings.Contains(err.Error(), "missing1") {
+		t.Fatalf("expected an error loading missing1, got %v", err)
+	}
+}
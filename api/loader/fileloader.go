@@ -92,6 +92,10 @@ type fileLoader struct {
 	// Used to load from HTTP
 	http *http.Client
 
+	// If true, files are loaded only from the
+	// filesystem, never from HTTP.
+	noRemoteFiles bool
+
 	// Used to clone repositories.
 	cloner git.Cloner
 
@@ -175,6 +179,14 @@ func demandDirectoryRoot(
 // New returns a new Loader, rooted relative to current loader,
 // or rooted in a temp directory holding a git repo clone.
 func (fl *fileLoader) New(path string) (ifc.Loader, error) {
+	ldr, err := fl.newChild(path)
+	if child, ok := ldr.(*fileLoader); ok && child != nil {
+		child.noRemoteFiles = fl.noRemoteFiles
+	}
+	return ldr, err
+}
+
+func (fl *fileLoader) newChild(path string) (ifc.Loader, error) {
 	if path == "" {
 		return nil, fmt.Errorf("new root cannot be empty")
 	}
@@ -194,7 +206,7 @@ func (fl *fileLoader) New(path string) (ifc.Loader, error) {
 			repoSpec, fl.fSys, fl, fl.cloner, fl.getter)
 	}
 
-	if filepath.IsAbs(path) {
+	if isAbs(path) {
 		return nil, fmt.Errorf("new root '%s' cannot be absolute", path)
 	}
 	root, errDir := demandDirectoryRoot(fl.fSys, fl.root.Join(path))
@@ -318,6 +330,11 @@ func (fl *fileLoader) errIfRepoCycle(newRepoSpec *git.RepoSpec) error {
 // to the root.
 func (fl *fileLoader) Load(path string) ([]byte, error) {
 	if u, err := url.Parse(path); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		if fl.noRemoteFiles {
+			return nil, fmt.Errorf(
+				"not loading %s over HTTP in a sandbox; "+
+					"load it from a git repository or a local copy instead", path)
+		}
 		var hc *http.Client
 		if fl.http != nil {
 			hc = fl.http
@@ -336,7 +353,7 @@ func (fl *fileLoader) Load(path string) ([]byte, error) {
 		return body, nil
 	}
 
-	if !filepath.IsAbs(path) {
+	if !isAbs(path) {
 		path = fl.root.Join(path)
 	}
 	path, err := fl.loadRestrictor(fl.fSys, fl.root, path)
@@ -346,6 +363,20 @@ func (fl *fileLoader) Load(path string) ([]byte, error) {
 	return fl.fSys.ReadFile(path)
 }
 
+// isAbs returns true if the path is absolute on any
+// platform, e.g. C:\base on Linux or /base on Windows,
+// so that kustomizations load alike everywhere rather
+// than such paths being taken as relative to the root.
+func isAbs(path string) bool {
+	if filepath.IsAbs(path) ||
+		strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\`) {
+		return true
+	}
+	return len(path) > 2 && path[1] == ':' &&
+		(path[2] == '/' || path[2] == '\\') &&
+		('a' <= path[0] && path[0] <= 'z' || 'A' <= path[0] && path[0] <= 'Z')
+}
+
 // Cleanup runs the cleaner.
 func (fl *fileLoader) Cleanup() error {
 	return fl.cleaner()
@@ -118,6 +118,23 @@ func TestLoaderNewSubDir(t *testing.T) {
 	}
 }
 
+func TestIsAbs(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"/foo":      true,
+		`\foo`:      true,
+		`C:\foo`:    true,
+		"c:/foo":    true,
+		"foo":       false,
+		"../foo":    false,
+		"c:foo":     false,
+		"https://x": false,
+	} {
+		if actual := isAbs(path); actual != expected {
+			t.Errorf("isAbs(%q): expected %v, got %v", path, expected, actual)
+		}
+	}
+}
+
 func TestLoaderBadRelative(t *testing.T) {
 	l1, err := makeLoader().New("foo/project/subdir1")
 	if err != nil {
@@ -5,6 +5,7 @@ package loader
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 
@@ -52,6 +53,12 @@ func newLoaderAtGetter(raw string, fSys filesys.FileSystem, referrer *fileLoader
 	}, nil
 }
 
+// getNoRemoteTarget fetches nothing, leaving
+// remote targets to the git cloner.
+func getNoRemoteTarget(rs *remoteTargetSpec) error {
+	return fmt.Errorf("not fetching %s outside of git", rs.Raw)
+}
+
 func getRemoteTarget(rs *remoteTargetSpec) error {
 	var err error
 
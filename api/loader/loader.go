@@ -21,8 +21,46 @@ import (
 func NewLoader(
 	lr LoadRestrictorFunc,
 	target string, fSys filesys.FileSystem) (ifc.Loader, error) {
+	return newLoader(lr, target, fSys, git.ClonerUsingGitExec, getRemoteTarget)
+}
+
+// NewLoaderWithRepoCache is like NewLoader, but takes
+// the clones of git repositories from the cache.
+func NewLoaderWithRepoCache(
+	lr LoadRestrictorFunc,
+	target string, fSys filesys.FileSystem, cache *RepoCache) (ifc.Loader, error) {
+	return newLoader(
+		lr, target, fSys, cache.cloner(git.ClonerUsingGitExec), getRemoteTarget)
+}
 
-	ldr, errGet := newLoaderAtGetter(target, fSys, nil, git.ClonerUsingGitExec, getRemoteTarget)
+// NewSandboxedLoader is like NewLoaderWithRepoCache, but
+// clones git repositories in a container of the given
+// image, per git.SandboxedCloner, and fetches no other
+// kinds of remote targets, nor files over HTTP, since
+// those are fetched in process.  The cache may be nil.
+func NewSandboxedLoader(
+	lr LoadRestrictorFunc,
+	target string, fSys filesys.FileSystem,
+	cache *RepoCache, image string) (ifc.Loader, error) {
+	cloner := git.SandboxedCloner(image)
+	if cache != nil {
+		cloner = cache.cloner(cloner)
+	}
+	ldr, err := newLoader(lr, target, fSys, cloner, getNoRemoteTarget)
+	if err != nil {
+		return nil, err
+	}
+	if fl, ok := ldr.(*fileLoader); ok {
+		fl.noRemoteFiles = true
+	}
+	return ldr, nil
+}
+
+func newLoader(
+	lr LoadRestrictorFunc,
+	target string, fSys filesys.FileSystem,
+	cloner git.Cloner, getter remoteTargetGetter) (ifc.Loader, error) {
+	ldr, errGet := newLoaderAtGetter(target, fSys, nil, cloner, getter)
 	if errGet == nil {
 		return ldr, nil
 	}
@@ -31,12 +69,12 @@ func NewLoader(
 	if errGit == nil {
 		// The target qualifies as a remote git target.
 		return newLoaderAtGitClone(
-			repoSpec, fSys, nil, git.ClonerUsingGitExec, getRemoteTarget)
+			repoSpec, fSys, nil, cloner, getter)
 	}
 
 	root, errDir := demandDirectoryRoot(fSys, target)
 	if errDir == nil {
-		return newLoaderAtConfirmedDir(lr, root, fSys, nil, git.ClonerUsingGitExec, getRemoteTarget), nil
+		return newLoaderAtConfirmedDir(lr, root, fSys, nil, cloner, getter), nil
 	}
 
 	return nil, fmt.Errorf(
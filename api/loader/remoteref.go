@@ -0,0 +1,39 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"sigs.k8s.io/kustomize/api/internal/git"
+)
+
+// IsRemoteBase returns whether the argument is the url
+// of a kustomization in a git repository.
+func IsRemoteBase(url string) bool {
+	_, err := git.NewRepoSpecFromUrl(url)
+	return err == nil
+}
+
+// PinRemoteBase returns the url of a kustomization in a git
+// repository with its ref replaced by the commit the ref names,
+// failing if the repository has no such ref.  A url without a ref
+// is pinned to the commit that loading it would clone.  If keepTags
+// is true, a ref that is a tag is kept, since tags rarely move.
+func PinRemoteBase(url string, keepTags bool) (string, error) {
+	return pinRemoteBase(url, keepTags, git.RefListerUsingGitExec)
+}
+
+func pinRemoteBase(url string, keepTags bool, list git.RefLister) (string, error) {
+	repoSpec, err := git.NewRepoSpecFromUrl(url)
+	if err != nil {
+		return "", err
+	}
+	commit, isTag, err := repoSpec.ResolveRef(list)
+	if err != nil {
+		return "", err
+	}
+	if isTag && keepTags {
+		return url, nil
+	}
+	return repoSpec.RawWithRef(commit), nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	branchSha = "1111111111111111111111111111111111111111"
+	tagSha    = "2222222222222222222222222222222222222222"
+	peeledSha = "3333333333333333333333333333333333333333"
+)
+
+// fakeLsRemote lists the refs of github.com/org/repo
+// named exactly by the patterns.
+func fakeLsRemote(cloneSpec string, patterns ...string) (string, error) {
+	if cloneSpec != "https://github.com/org/repo.git" {
+		return "", fmt.Errorf("unexpected clone spec %s", cloneSpec)
+	}
+	refs := map[string]string{
+		"refs/heads/master":   branchSha,
+		"refs/heads/release":  branchSha,
+		"refs/tags/v1.0.0":    tagSha,
+		"refs/tags/v1.0.0^{}": peeledSha,
+		"refs/tags/v0.9":      tagSha,
+	}
+	var b strings.Builder
+	for _, p := range patterns {
+		for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
+			if sha, ok := refs[prefix+p]; ok {
+				b.WriteString(sha + "\t" + prefix + p + "\n")
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+func TestPinRemoteBase(t *testing.T) {
+	testCases := map[string]struct {
+		url      string
+		keepTags bool
+		expected string
+		errMsg   string
+	}{
+		"branch": {
+			url:      "github.com/org/repo//base?ref=release",
+			expected: "github.com/org/repo//base?ref=" + branchSha,
+		},
+		"noRef": {
+			url:      "github.com/org/repo//base",
+			expected: "github.com/org/repo//base?ref=" + branchSha,
+		},
+		"version": {
+			url:      "https://github.com/org/repo/base?version=release",
+			expected: "https://github.com/org/repo/base?version=" + branchSha,
+		},
+		"annotatedTag": {
+			url:      "github.com/org/repo//base?ref=v1.0.0",
+			expected: "github.com/org/repo//base?ref=" + peeledSha,
+		},
+		"lightweightTag": {
+			url:      "github.com/org/repo//base?ref=v0.9",
+			expected: "github.com/org/repo//base?ref=" + tagSha,
+		},
+		"keepTag": {
+			url:      "github.com/org/repo//base?ref=v1.0.0",
+			keepTags: true,
+			expected: "github.com/org/repo//base?ref=v1.0.0",
+		},
+		"keepTagNotBranch": {
+			url:      "github.com/org/repo//base?ref=release",
+			keepTags: true,
+			expected: "github.com/org/repo//base?ref=" + branchSha,
+		},
+		"commit": {
+			url:      "github.com/org/repo//base?ref=" + peeledSha,
+			expected: "github.com/org/repo//base?ref=" + peeledSha,
+		},
+		"missingRef": {
+			url:    "github.com/org/repo//base?ref=nope",
+			errMsg: "no branch or tag nope in git repo https://github.com/org/repo.git",
+		},
+		"notRemote": {
+			url:    "../base",
+			errMsg: "url lacks host",
+		},
+	}
+	for n, tc := range testCases {
+		pinned, err := pinRemoteBase(tc.url, tc.keepTags, fakeLsRemote)
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if pinned != tc.expected {
+			t.Errorf("%s: expected %s, got %s", n, tc.expected, pinned)
+		}
+	}
+}
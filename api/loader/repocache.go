@@ -0,0 +1,66 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/internal/git"
+)
+
+// RepoCache shares the clones of git repositories among
+// the loaders made with it, so that a repository that
+// several targets use, e.g. overlays built in one process,
+// is cloned once.  The clones outlive the loaders, until
+// Cleanup is called.
+type RepoCache struct {
+	fSys filesys.FileSystem
+	mu   sync.Mutex
+	// dirs maps a clone spec and ref to its clone.
+	dirs map[string]filesys.ConfirmedDir
+}
+
+// NewRepoCache returns an empty cache of clones.
+func NewRepoCache(fSys filesys.FileSystem) *RepoCache {
+	return &RepoCache{
+		fSys: fSys,
+		dirs: make(map[string]filesys.ConfirmedDir),
+	}
+}
+
+// cloner returns a git.Cloner cloning each repository
+// and ref once, with the given cloner.
+func (c *RepoCache) cloner(clone git.Cloner) git.Cloner {
+	return func(repoSpec *git.RepoSpec) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		key := repoSpec.CloneSpec() + "?ref=" + repoSpec.Ref
+		if dir, ok := c.dirs[key]; ok {
+			repoSpec.Dir = dir
+			repoSpec.Shared = true
+			return nil
+		}
+		if err := clone(repoSpec); err != nil {
+			return err
+		}
+		c.dirs[key] = repoSpec.Dir
+		repoSpec.Shared = true
+		return nil
+	}
+}
+
+// Cleanup removes the clones.
+func (c *RepoCache) Cleanup() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result error
+	for key, dir := range c.dirs {
+		if err := c.fSys.RemoveAll(dir.String()); err != nil && result == nil {
+			result = err
+		}
+		delete(c.dirs, key)
+	}
+	return result
+}
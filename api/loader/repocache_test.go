@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/internal/git"
+)
+
+func TestRepoCache(t *testing.T) {
+	topDir := "/whatever"
+	fSys := filesys.MakeFsInMemory()
+	fSys.MkdirAll(topDir)
+	clones := 0
+	cache := NewRepoCache(fSys)
+	cloner := cache.cloner(func(rs *git.RepoSpec) error {
+		clones++
+		dir := fmt.Sprintf("/clone%d", clones)
+		fSys.MkdirAll(dir + "/foo/base")
+		rs.Dir = filesys.ConfirmedDir(dir)
+		return nil
+	})
+	root, err := demandDirectoryRoot(fSys, topDir)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	l1 := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, root, fSys, nil, cloner, getNothing)
+	for _, url := range []string{
+		"github.com/someOrg/someRepo/foo/base",
+		"github.com/someOrg/someRepo/foo/base",
+		"github.com/someOrg/someRepo/foo/base?ref=v1",
+	} {
+		l2, err := l1.New(url)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if err = l2.Cleanup(); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	if clones != 2 {
+		t.Fatalf("expected 2 clones, got %d", clones)
+	}
+	if !fSys.Exists("/clone1") || !fSys.Exists("/clone2") {
+		t.Fatalf("expected clones to outlive their loaders")
+	}
+	if err = cache.Cleanup(); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if fSys.Exists("/clone1") || fSys.Exists("/clone2") {
+		t.Fatalf("expected clones to be removed")
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginrpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/kustomize/api/pluginrpc/pluginpb"
+)
+
+// Client is a connection to a plugin subprocess.
+type Client struct {
+	cmd     *exec.Cmd
+	conn    *grpc.ClientConn
+	plugin  pluginpb.PluginClient
+	version int
+	// drained is closed once the rest of the
+	// plugin's stdout has been read.
+	drained chan struct{}
+}
+
+// Start starts the command, a plugin executable,
+// and connects to it, failing if the plugin doesn't
+// shake hands within the timeout, unless that's zero.
+// The command's stdout must be unset; after the
+// handshake, whatever else the plugin writes there
+// is copied to its stderr.
+func Start(cmd *exec.Cmd, timeout time.Duration) (*Client, error) {
+	return StartWith(cmd, (*exec.Cmd).Start, timeout)
+}
+
+// StartWith is Start, starting the command with the
+// given function, e.g. one restricting the process.
+func StartWith(
+	cmd *exec.Cmd, start func(*exec.Cmd) error,
+	timeout time.Duration) (*Client, error) {
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env,
+		MagicCookieKey+"="+MagicCookieValue,
+		ProtocolVersionsKey+"="+formatVersions(ProtocolVersions))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = start(cmd); err != nil {
+		if cmd.Process != nil {
+			stop(cmd)
+		}
+		return nil, errors.Wrapf(err, "starting plugin %s", cmd.Path)
+	}
+	r := bufio.NewReader(stdout)
+	line, err := readHandshake(r, timeout)
+	if err != nil {
+		stop(cmd)
+		return nil, errors.Wrapf(err, "plugin %s", cmd.Path)
+	}
+	version, network, address, err := parseHandshake(line)
+	if err != nil {
+		stop(cmd)
+		return nil, errors.Wrapf(err, "plugin %s", cmd.Path)
+	}
+	conn, err := grpc.Dial(address,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(
+			func(ctx context.Context, addr string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			}))
+	if err != nil {
+		stop(cmd)
+		return nil, errors.Wrapf(err, "connecting to plugin %s", cmd.Path)
+	}
+	c := &Client{
+		cmd:     cmd,
+		conn:    conn,
+		plugin:  pluginpb.NewPluginClient(conn),
+		version: version,
+		drained: make(chan struct{}),
+	}
+	go func() {
+		w := cmd.Stderr
+		if w == nil {
+			w = ioutil.Discard
+		}
+		io.Copy(w, r)
+		close(c.drained)
+	}()
+	return c, nil
+}
+
+// readHandshake returns the first line the plugin writes.
+func readHandshake(r *bufio.Reader, timeout time.Duration) (string, error) {
+	type result struct {
+		line string
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		line, err := r.ReadString('\n')
+		ch <- result{line: line, err: err}
+	}()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			return "", errors.Wrap(res.err, "exited before the handshake")
+		}
+		return strings.TrimSpace(res.line), nil
+	case <-expired:
+		return "", fmt.Errorf("no handshake within %v", timeout)
+	}
+}
+
+func parseHandshake(line string) (version int, network, address string, err error) {
+	parts := strings.Split(line, "|")
+	if len(parts) != 5 {
+		return 0, "", "", fmt.Errorf("bad handshake %q", line)
+	}
+	core, err := strconv.Atoi(parts[0])
+	if err != nil || core != CoreProtocolVersion {
+		return 0, "", "", fmt.Errorf(
+			"handshake %q has core protocol version %s, not %d",
+			line, parts[0], CoreProtocolVersion)
+	}
+	version, err = strconv.Atoi(parts[1])
+	if err != nil || !speaks(version) {
+		return 0, "", "", fmt.Errorf(
+			"handshake %q has protocol version %s; kustomize speaks %v",
+			line, parts[1], ProtocolVersions)
+	}
+	if parts[4] != rpcProtocol {
+		return 0, "", "", fmt.Errorf(
+			"handshake %q has rpc protocol %s, not %s", line, parts[4], rpcProtocol)
+	}
+	return version, parts[2], parts[3], nil
+}
+
+func stop(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+	cmd.Wait()
+}
+
+// Version returns the protocol version the plugin speaks.
+func (c *Client) Version() int {
+	return c.version
+}
+
+// Capabilities returns what the plugin can do.
+func (c *Client) Capabilities() (Capabilities, error) {
+	reply, err := c.plugin.Capabilities(context.Background(), &pluginpb.Empty{})
+	if err != nil {
+		return Capabilities{}, callErr(err)
+	}
+	return Capabilities{
+		Generator:   reply.Generator,
+		Transformer: reply.Transformer,
+	}, nil
+}
+
+// Config configures the plugin.
+func (c *Client) Config(root string, config []byte) error {
+	_, err := c.plugin.Config(
+		context.Background(), &pluginpb.ConfigRequest{Config: config, Root: root})
+	return callErr(err)
+}
+
+// Generate returns the resources the plugin generates.
+func (c *Client) Generate() ([]byte, error) {
+	reply, err := c.plugin.Generate(context.Background(), &pluginpb.Empty{})
+	if err != nil {
+		return nil, callErr(err)
+	}
+	return reply.Resources, nil
+}
+
+// Transform returns the resources as the plugin transforms them.
+func (c *Client) Transform(resources []byte) ([]byte, error) {
+	reply, err := c.plugin.Transform(
+		context.Background(), &pluginpb.Resources{Resources: resources})
+	if err != nil {
+		return nil, callErr(err)
+	}
+	return reply.Resources, nil
+}
+
+// callErr returns the error a plugin's method
+// returned as is, rather than as a gRPC status.
+func callErr(err error) error {
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unknown {
+		return errors.New(s.Message())
+	}
+	return err
+}
+
+// Close disconnects from the plugin, and waits for it to exit.
+func (c *Client) Close() error {
+	c.conn.Close()
+	<-c.drained
+	return c.cmd.Wait()
+}
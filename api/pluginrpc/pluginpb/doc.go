@@ -0,0 +1,9 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pluginpb holds the gRPC service plugins serve,
+// generated from plugin.proto.  Plugins in languages
+// other than Go generate theirs from the same file.
+package pluginpb
+
+//go:generate protoc --go_out=plugins=grpc:. plugin.proto
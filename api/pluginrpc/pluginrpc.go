@@ -0,0 +1,104 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pluginrpc is a protocol for kustomize plugins that
+// run as subprocesses, in the style of hashicorp/go-plugin.
+//
+// kustomize starts the plugin executable with MagicCookieKey
+// set to MagicCookieValue in its environment, and with
+// ProtocolVersionsKey listing the protocol versions kustomize
+// speaks.  The plugin picks the newest version both speak,
+// listens on a socket, and writes one line to stdout:
+//
+//	CORE-VERSION|PROTOCOL-VERSION|NETWORK|ADDRESS|grpc
+//
+// kustomize then connects to the address, asks the plugin
+// for its capabilities, and calls the methods of the gRPC
+// service in pluginpb/plugin.proto.  Resources are exchanged
+// as YAML, so a plugin depends on the protocol version, not
+// on the version of kustomize it's built with, nor on the
+// language it's written in.
+//
+// A Go plugin's main function calls Serve; a plugin in
+// another language implements the handshake, and serves
+// the service generated from plugin.proto.
+package pluginrpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MagicCookieKey and MagicCookieValue tell a plugin
+	// that it's run by kustomize, rather than directly.
+	MagicCookieKey   = "KUSTOMIZE_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "b0a3e07c4ad36f0f9c0d2f8e5c1a7b96"
+
+	// ProtocolVersionsKey lists, comma separated,
+	// the protocol versions kustomize speaks.
+	ProtocolVersionsKey = "KUSTOMIZE_PLUGIN_PROTOCOL_VERSIONS"
+
+	// CoreProtocolVersion is the version of
+	// the handshake, rather than of the service.
+	CoreProtocolVersion = 1
+
+	rpcProtocol = "grpc"
+)
+
+// ProtocolVersions are the versions of the
+// Plugin service this package speaks.
+var ProtocolVersions = []int{1}
+
+// Capabilities are what a plugin can do.
+type Capabilities struct {
+	Generator   bool
+	Transformer bool
+}
+
+func formatVersions(versions []int) string {
+	s := make([]string, len(versions))
+	for i, v := range versions {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
+
+func parseVersions(s string) ([]int, error) {
+	var result []int
+	for _, x := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(x))
+		if err != nil {
+			return nil, fmt.Errorf("bad protocol version %q", x)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// negotiate returns the newest of this package's
+// protocol versions among the given ones.
+func negotiate(offered []int) (int, error) {
+	result := 0
+	for _, v := range offered {
+		if speaks(v) && v > result {
+			result = v
+		}
+	}
+	if result == 0 {
+		return 0, fmt.Errorf(
+			"no common protocol version; offered %v, spoken %v",
+			offered, ProtocolVersions)
+	}
+	return result, nil
+}
+
+func speaks(version int) bool {
+	for _, v := range ProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginrpc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPluginEnv makes the test binary serve testPlugin.
+const testPluginEnv = "PLUGINRPC_TEST_PLUGIN"
+
+// testPlugin generates a ConfigMap named by its config,
+// and transforms resources by upper-casing them.
+type testPlugin struct {
+	name string
+}
+
+func (p *testPlugin) Config(root string, config []byte) error {
+	p.name = strings.TrimSpace(string(config)) + "-" + root
+	return nil
+}
+
+func (p *testPlugin) Generate() ([]byte, error) {
+	return []byte(fmt.Sprintf(
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n", p.name)), nil
+}
+
+func (p *testPlugin) Transform(resources []byte) ([]byte, error) {
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no resources")
+	}
+	return bytes.ToUpper(resources), nil
+}
+
+func TestHelperPlugin(t *testing.T) {
+	switch os.Getenv(testPluginEnv) {
+	case "":
+		return
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+	if err := Serve(&testPlugin{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func startTestPlugin(t *testing.T) *Client {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperPlugin")
+	cmd.Env = append(os.Environ(), testPluginEnv+"=1")
+	cmd.Stderr = os.Stderr
+	c, err := Start(cmd, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestClient(t *testing.T) {
+	c := startTestPlugin(t)
+	if c.Version() != 1 {
+		t.Errorf("expected version 1, got %d", c.Version())
+	}
+	caps, err := c.Capabilities()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !caps.Generator || !caps.Transformer {
+		t.Errorf("unexpected capabilities %v", caps)
+	}
+	if err = c.Config("app", []byte("config\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := c.Generate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config-app\n"
+	if string(out) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out)
+	}
+	out, err = c.Transform([]byte("kind: Service\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "KIND: SERVICE\n" {
+		t.Errorf("unexpected output %s", out)
+	}
+	_, err = c.Transform(nil)
+	if err == nil || err.Error() != "no resources" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err = c.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestStartTimeout(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperPlugin")
+	cmd.Env = append(os.Environ(), testPluginEnv+"=hang")
+	_, err := Start(cmd, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "no handshake within 100ms") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestServeWithoutCookie(t *testing.T) {
+	var out bytes.Buffer
+	err := serve(&testPlugin{}, func(string) string { return "" }, &out)
+	if err == nil || !strings.Contains(err.Error(), "meant to be run by kustomize") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("unexpected handshake %s", out.String())
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	testCases := map[string]struct {
+		offered  []int
+		expected int
+		errMsg   string
+	}{
+		"same":  {offered: []int{1}, expected: 1},
+		"newer": {offered: []int{1, 2}, expected: 1},
+		"none": {
+			offered: []int{2, 3},
+			errMsg:  "no common protocol version; offered [2 3], spoken [1]",
+		},
+	}
+	for n, tc := range testCases {
+		v, err := negotiate(tc.offered)
+		if tc.errMsg != "" {
+			if err == nil || err.Error() != tc.errMsg {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil || v != tc.expected {
+			t.Errorf("%s: expected %d, got %d, %v", n, tc.expected, v, err)
+		}
+	}
+}
+
+func TestParseHandshake(t *testing.T) {
+	testCases := map[string]struct {
+		line   string
+		errMsg string
+	}{
+		"good":    {line: "1|1|unix|/tmp/p.sock|grpc"},
+		"short":   {line: "1|1|unix", errMsg: "bad handshake"},
+		"core":    {line: "2|1|unix|/tmp/p.sock|grpc", errMsg: "core protocol version 2"},
+		"version": {line: "1|7|unix|/tmp/p.sock|grpc", errMsg: "protocol version 7"},
+		"netrpc":  {line: "1|1|unix|/tmp/p.sock|netrpc", errMsg: "rpc protocol netrpc"},
+	}
+	for n, tc := range testCases {
+		_, network, address, err := parseHandshake(tc.line)
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil || network != "unix" || address != "/tmp/p.sock" {
+			t.Errorf("%s: unexpected %s %s %v", n, network, address, err)
+		}
+	}
+}
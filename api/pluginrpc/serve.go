@@ -0,0 +1,183 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"google.golang.org/grpc"
+	"sigs.k8s.io/kustomize/api/pluginrpc/pluginpb"
+)
+
+// Plugin is implemented by the plugins given to Serve.
+// A plugin also implements Generator, Transformer or both.
+type Plugin interface {
+	// Config configures the plugin, given the root of
+	// the kustomization configuring it, against which
+	// the paths in the config are resolved.
+	Config(root string, config []byte) error
+}
+
+// Generator is a plugin that generates resources.
+type Generator interface {
+	Plugin
+	// Generate returns the resources, as a YAML stream.
+	Generate() ([]byte, error)
+}
+
+// Transformer is a plugin that transforms resources.
+type Transformer interface {
+	Plugin
+	// Transform returns the resources, as a YAML
+	// stream, changed as the plugin does.
+	Transform(resources []byte) ([]byte, error)
+}
+
+// Serve serves the plugin to the kustomize that started
+// the process, returning once kustomize disconnects.
+func Serve(p Plugin) error {
+	return serve(p, os.Getenv, os.Stdout)
+}
+
+func serve(p Plugin, getenv func(string) string, out io.Writer) error {
+	if getenv(MagicCookieKey) != MagicCookieValue {
+		return fmt.Errorf(
+			"this executable is a kustomize plugin, meant to be run by kustomize")
+	}
+	offered, err := parseVersions(getenv(ProtocolVersionsKey))
+	if err != nil {
+		return err
+	}
+	version, err := negotiate(offered)
+	if err != nil {
+		return err
+	}
+	l, cleanup, err := listen()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	server := grpc.NewServer()
+	pluginpb.RegisterPluginServer(server, &service{p: p})
+	_, err = fmt.Fprintf(out, "%d|%d|%s|%s|%s\n",
+		CoreProtocolVersion, version,
+		l.Addr().Network(), l.Addr().String(), rpcProtocol)
+	if err != nil {
+		return err
+	}
+	err = server.Serve(&connListener{Listener: l})
+	if err == errDisconnected {
+		return nil
+	}
+	return err
+}
+
+var errDisconnected = errors.New("kustomize disconnected")
+
+// connListener accepts the one connection kustomize makes,
+// then fails once that's closed, so the server stops.
+type connListener struct {
+	net.Listener
+	closed   chan struct{}
+	accepted bool
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	if l.accepted {
+		<-l.closed
+		return nil, errDisconnected
+	}
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.accepted = true
+	l.closed = make(chan struct{})
+	return &closeConn{Conn: conn, closed: l.closed}, nil
+}
+
+// closeConn closes its channel once closed.
+type closeConn struct {
+	net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (c *closeConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return c.Conn.Close()
+}
+
+// listen listens on a unix socket in a new temporary
+// directory, or on a local TCP port where unix sockets
+// aren't available.
+func listen() (net.Listener, func(), error) {
+	if runtime.GOOS == "windows" {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, nil, err
+		}
+		return l, func() { l.Close() }, nil
+	}
+	dir, err := ioutil.TempDir("", "kustomize-plugin-")
+	if err != nil {
+		return nil, nil, err
+	}
+	l, err := net.Listen("unix", filepath.Join(dir, "plugin.sock"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	return l, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}, nil
+}
+
+// service adapts a plugin to gRPC.
+type service struct {
+	p Plugin
+}
+
+func (s *service) Capabilities(
+	context.Context, *pluginpb.Empty) (*pluginpb.CapabilitiesResponse, error) {
+	var reply pluginpb.CapabilitiesResponse
+	_, reply.Generator = s.p.(Generator)
+	_, reply.Transformer = s.p.(Transformer)
+	return &reply, nil
+}
+
+func (s *service) Config(
+	_ context.Context, args *pluginpb.ConfigRequest) (*pluginpb.Empty, error) {
+	return &pluginpb.Empty{}, s.p.Config(args.Root, args.Config)
+}
+
+func (s *service) Generate(
+	context.Context, *pluginpb.Empty) (*pluginpb.Resources, error) {
+	g, ok := s.p.(Generator)
+	if !ok {
+		return nil, fmt.Errorf("plugin isn't a generator")
+	}
+	resources, err := g.Generate()
+	return &pluginpb.Resources{Resources: resources}, err
+}
+
+func (s *service) Transform(
+	_ context.Context, args *pluginpb.Resources) (*pluginpb.Resources, error) {
+	t, ok := s.p.(Transformer)
+	if !ok {
+		return nil, fmt.Errorf("plugin isn't a transformer")
+	}
+	resources, err := t.Transform(args.Resources)
+	return &pluginpb.Resources{Resources: resources}, err
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package profile records how long the phases of a build
+// take, telling observers of each step as it starts and
+// ends, so that programs embedding kustomize may export
+// them as metrics or trace spans.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Phase names a part of a build.
+type Phase string
+
+const (
+	// Accumulation of one kustomization root, including
+	// everything it loads, so phases nest inside it.
+	PhaseKustomization Phase = "kustomization"
+	// Reading and parsing a kustomization file.
+	PhaseLoad Phase = "load"
+	// Cloning a remote base.
+	PhaseFetch Phase = "fetch"
+	// Running one generator.
+	PhaseGenerator Phase = "generator"
+	// Running one transformer.
+	PhaseTransformer Phase = "transformer"
+	// Running one validator.
+	PhaseValidator Phase = "validator"
+	// Adding hash suffixes, fixing name references
+	// and resolving vars once all resources are known.
+	PhaseFinalize Phase = "finalize"
+	// Converting the resources to YAML.
+	PhaseMarshal Phase = "marshal"
+)
+
+// Entry records one timed step of a build.
+type Entry struct {
+	Phase Phase `json:"phase"`
+	// Root is the kustomization root the step ran in.
+	Root string `json:"root,omitempty"`
+	// Name identifies the step within the phase,
+	// e.g. the kind of a transformer.
+	Name     string        `json:"name,omitempty"`
+	Start    time.Time     `json:"-"`
+	Duration time.Duration `json:"-"`
+}
+
+// Observer is told of the steps of a build as they start
+// and end.  Steps may nest, e.g. fetches and generators
+// run within kustomizations, and its methods must return
+// promptly, as the build waits for them.
+type Observer interface {
+	// Started is called as a step starts,
+	// with the entry's Duration zero.
+	Started(e Entry)
+	// Ended is called as the step ends.
+	Ended(e Entry)
+}
+
+// Profile collects entries.  A nil *Profile is valid
+// and records nothing, so callers needn't check.
+type Profile struct {
+	mu        sync.Mutex
+	entries   []Entry
+	now       func() time.Time
+	observers []Observer
+}
+
+// New returns an empty Profile telling the observers,
+// if any, of the steps it records.
+func New(observers ...Observer) *Profile {
+	return &Profile{now: time.Now, observers: observers}
+}
+
+// Start begins timing a step, returning the
+// function that ends it and records the entry.
+func (p *Profile) Start(phase Phase, root, name string) func() {
+	if p == nil {
+		return func() {}
+	}
+	e := Entry{Phase: phase, Root: root, Name: name, Start: p.now()}
+	for _, o := range p.observers {
+		o.Started(e)
+	}
+	return func() {
+		e.Duration = p.now().Sub(e.Start)
+		p.mu.Lock()
+		p.entries = append(p.entries, e)
+		p.mu.Unlock()
+		for _, o := range p.observers {
+			o.Ended(e)
+		}
+	}
+}
+
+// Entries returns the recorded entries in the order they completed.
+func (p *Profile) Entries() []Entry {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result := make([]Entry, len(p.entries))
+	copy(result, p.entries)
+	return result
+}
+
+// PhaseTotals returns the total duration of each phase.
+// Nested kustomization entries are not summed, as the
+// outermost one already includes the others; the
+// kustomization total is that of the outermost entry.
+func (p *Profile) PhaseTotals() map[Phase]time.Duration {
+	result := make(map[Phase]time.Duration)
+	for _, e := range p.Entries() {
+		if e.Phase == PhaseKustomization {
+			if e.Duration > result[e.Phase] {
+				result[e.Phase] = e.Duration
+			}
+			continue
+		}
+		result[e.Phase] += e.Duration
+	}
+	return result
+}
+
+// WriteText writes a table of the entries, slowest first,
+// followed by the phase totals.
+func (p *Profile) WriteText(w io.Writer) error {
+	entries := p.Entries()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Duration > entries[j].Duration
+	})
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tROOT\tNAME\tDURATION")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Phase, e.Root, e.Name, e.Duration)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "PHASE\tTOTAL")
+	totals := p.PhaseTotals()
+	for _, phase := range sortedPhases(totals) {
+		fmt.Fprintf(tw, "%s\t%s\n", phase, totals[phase])
+	}
+	return tw.Flush()
+}
+
+type jsonEntry struct {
+	Entry
+	Millis float64 `json:"durationMs"`
+}
+
+type jsonProfile struct {
+	Entries []jsonEntry       `json:"entries"`
+	Totals  map[Phase]float64 `json:"totalsMs"`
+}
+
+// WriteJSON writes the entries and phase totals as a JSON object.
+func (p *Profile) WriteJSON(w io.Writer) error {
+	out := jsonProfile{
+		Entries: []jsonEntry{},
+		Totals:  make(map[Phase]float64),
+	}
+	for _, e := range p.Entries() {
+		out.Entries = append(out.Entries, jsonEntry{Entry: e, Millis: millis(e.Duration)})
+	}
+	for phase, d := range p.PhaseTotals() {
+		out.Totals[phase] = millis(d)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func sortedPhases(m map[Phase]time.Duration) []Phase {
+	var result []Phase
+	for phase := range m {
+		result = append(result, phase)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if m[result[i]] != m[result[j]] {
+			return m[result[i]] > m[result[j]]
+		}
+		return result[i] < result[j]
+	})
+	return result
+}
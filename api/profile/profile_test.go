@@ -0,0 +1,128 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package profile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// makeProfile returns a Profile whose clock
+// advances by one millisecond per reading.
+func makeProfile() *Profile {
+	p := New()
+	var t time.Time
+	p.now = func() time.Time {
+		t = t.Add(time.Millisecond)
+		return t
+	}
+	return p
+}
+
+func TestNilProfile(t *testing.T) {
+	var p *Profile
+	p.Start(PhaseLoad, "/app", "")()
+	if len(p.Entries()) != 0 {
+		t.Fatalf("expected no entries")
+	}
+}
+
+func TestPhaseTotals(t *testing.T) {
+	p := makeProfile()
+	outer := p.Start(PhaseKustomization, "/overlay", "")
+	inner := p.Start(PhaseKustomization, "/base", "")
+	p.Start(PhaseTransformer, "/base", "LabelTransformer")()
+	inner()
+	p.Start(PhaseTransformer, "/overlay", "LabelTransformer")()
+	outer()
+	totals := p.PhaseTotals()
+	if totals[PhaseTransformer] != 2*time.Millisecond {
+		t.Errorf("unexpected transformer total %s", totals[PhaseTransformer])
+	}
+	if totals[PhaseKustomization] != 7*time.Millisecond {
+		t.Errorf("unexpected kustomization total %s", totals[PhaseKustomization])
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	p := makeProfile()
+	stop := p.Start(PhaseKustomization, "/app", "")
+	p.Start(PhaseGenerator, "/app", "ConfigMapGenerator")()
+	stop()
+	var b bytes.Buffer
+	if err := p.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := `PHASE          ROOT  NAME                DURATION
+kustomization  /app                      3ms
+generator      /app  ConfigMapGenerator  1ms
+
+PHASE          TOTAL
+kustomization  3ms
+generator      1ms
+`
+	if b.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	p := makeProfile()
+	p.Start(PhaseMarshal, "", "")()
+	var b bytes.Buffer
+	if err := p.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`"phase": "marshal"`,
+		`"durationMs": 1`,
+		`"marshal": 1`,
+	} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("expected %s in\n%s", s, b.String())
+		}
+	}
+}
+
+// recorder is an Observer recording what it's told.
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) Started(e Entry) {
+	r.events = append(r.events, "started "+string(e.Phase)+" "+e.Name)
+}
+
+func (r *recorder) Ended(e Entry) {
+	r.events = append(r.events,
+		"ended "+string(e.Phase)+" "+e.Name+" "+e.Duration.String())
+}
+
+func TestObservers(t *testing.T) {
+	r := &recorder{}
+	p := New(r)
+	var now time.Time
+	p.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+	stop := p.Start(PhaseKustomization, "/app", "")
+	p.Start(PhaseFetch, "/app", "github.com/example/base")()
+	stop()
+	expected := []string{
+		"started kustomization ",
+		"started fetch github.com/example/base",
+		"ended fetch github.com/example/base 1ms",
+		"ended kustomization  3ms",
+	}
+	if strings.Join(r.events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected\n%s\ngot\n%s",
+			strings.Join(expected, "\n"), strings.Join(r.events, "\n"))
+	}
+	if len(p.Entries()) != 2 {
+		t.Errorf("expected the entries recorded too, got %v", p.Entries())
+	}
+}
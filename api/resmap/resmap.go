@@ -19,6 +19,13 @@ type Transformer interface {
 	Transform(m ResMap) error
 }
 
+// A Validator checks an instance of ResMap without
+// modifying it, reporting what it finds.  An error
+// means the checks couldn't be made.
+type Validator interface {
+	Validate(m ResMap) ([]types.Finding, error)
+}
+
 // A Generator creates an instance of ResMap.
 type Generator interface {
 	Generate() (ResMap, error)
@@ -62,8 +62,9 @@ func (m *resWrangler) Resources() []*resource.Resource {
 func (m *resWrangler) Append(res *resource.Resource) error {
 	id := res.CurId()
 	if r := m.GetMatchingResourcesByCurrentId(id.Equals); len(r) > 0 {
-		return fmt.Errorf(
-			"may not add resource with an already registered id: %s", id)
+		return types.NewErrLocated(fmt.Errorf(
+			"may not add resource with an already registered id: %s", id),
+			types.ErrorLocation{ResourceId: id.String()})
 	}
 	m.rList = append(m.rList, res)
 	return nil
@@ -118,7 +119,7 @@ func (m *resWrangler) Debug(title string) {
 			fmt.Println("---")
 		}
 		fmt.Printf("# %d  %s\n", i, r.OrgId())
-		blob, err := yaml.Marshal(r.Map())
+		blob, err := resourceYaml(r)
 		if err != nil {
 			panic(err)
 		}
@@ -265,13 +266,23 @@ func (m *resWrangler) groupedByOriginalNamespace() map[string][]*resource.Resour
 	return byNamespace
 }
 
+// resourceYaml returns the resource as YAML, keeping the
+// order of its fields and its comments if it's held in a
+// yaml.RNode, or sorting them otherwise.
+func resourceYaml(res *resource.Resource) ([]byte, error) {
+	if res.PeekRNode() != nil {
+		return res.AsYAML()
+	}
+	return yaml.Marshal(res.PeekMap())
+}
+
 // AsYaml implements ResMap.
 func (m *resWrangler) AsYaml() ([]byte, error) {
 	firstObj := true
 	var b []byte
 	buf := bytes.NewBuffer(b)
 	for _, res := range m.Resources() {
-		out, err := yaml.Marshal(res.Map())
+		out, err := resourceYaml(res)
 		if err != nil {
 			return nil, err
 		}
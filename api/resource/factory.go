@@ -12,6 +12,7 @@ import (
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/kusterr"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // Factory makes instances of Resource.
@@ -70,6 +71,7 @@ func (rf *Factory) makeOne(
 	}
 	r := &Resource{
 		kunStr:  u,
+		holders: newHolders(),
 		options: o,
 	}
 	return r.setOriginalName(r.kunStr.GetName()).setOriginalNs(r.GetNamespace())
@@ -118,6 +120,14 @@ func (rf *Factory) SliceFromBytes(in []byte) ([]*Resource, error) {
 		u := kunStructs[0]
 		kunStructs = kunStructs[1:]
 		if strings.HasSuffix(u.GetKind(), "List") {
+			if h, ok := u.(rNodeHolder); ok {
+				innerU, err := rf.sliceFromListNode(h.HeldRNode())
+				if err != nil {
+					return nil, err
+				}
+				kunStructs = append(kunStructs, innerU...)
+				continue
+			}
 			items := u.Map()["items"]
 			itemsSlice, ok := items.([]interface{})
 			if !ok {
@@ -146,6 +156,35 @@ func (rf *Factory) SliceFromBytes(in []byte) ([]*Resource, error) {
 	return result, nil
 }
 
+// sliceFromListNode returns the items of the List,
+// read from YAML rather than JSON, so that they keep
+// the order of their fields and their comments.
+func (rf *Factory) sliceFromListNode(
+	list *yaml.RNode) ([]ifc.Kunstructured, error) {
+	f := list.Field("items")
+	if f == nil || yaml.IsMissingOrNull(f.Value) {
+		// an empty list
+		return nil, nil
+	}
+	items, err := f.Value.Elements()
+	if err != nil {
+		return nil, fmt.Errorf("items in List is not an array: %v", err)
+	}
+	var result []ifc.Kunstructured
+	for _, item := range items {
+		s, err := item.String()
+		if err != nil {
+			return nil, err
+		}
+		innerU, err := rf.kf.SliceFromBytes([]byte(s))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, innerU...)
+	}
+	return result, nil
+}
+
 // SliceFromBytesWithNames unmarshals bytes into a Resource slice with specified original
 // name.
 func (rf *Factory) SliceFromBytesWithNames(names []string, in []byte) ([]*Resource, error) {
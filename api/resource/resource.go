@@ -7,18 +7,28 @@ package resource
 import (
 	"reflect"
 	"strings"
+	"sync/atomic"
 
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filtersutil"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 	"sigs.k8s.io/yaml"
 )
 
 // Resource is a representation of a Kubernetes Resource Model (KRM) object
 // paired with metadata used by kustomize.
 // For more history, see sigs.k8s.io/kustomize/api/ifc.Unstructured
+//
+// Copies of a resource share its data until either changes
+// it, so that copying the resources of large builds, e.g. to
+// tell which ones a transformer changed, costs little.
 type Resource struct {
-	kunStr       ifc.Kunstructured
+	kunStr ifc.Kunstructured
+	// holders counts the resources sharing kunStr, which
+	// they must not change while they share it.
+	holders      *int32
 	originalName string
 	originalNs   string
 	options      *types.GenArgs
@@ -26,10 +36,43 @@ type Resource struct {
 	refVarNames  []string
 	namePrefixes []string
 	nameSuffixes []string
+	origin       string
+	// transformations made or changed the resource, in order.
+	transformations []Transformation
+}
+
+// Transformation records a generator or transformer
+// that made or changed a resource.
+type Transformation struct {
+	// ConfiguredIn is the kustomization root
+	// the generator or transformer ran in.
+	ConfiguredIn string `json:"configuredIn"`
+	// ConfiguredBy is the kind of the generator or transformer.
+	ConfiguredBy string `json:"configuredBy"`
 }
 
 func (r *Resource) ResetPrimaryData(incoming *Resource) {
-	r.kunStr = incoming.Copy()
+	atomic.AddInt32(incoming.holders, 1)
+	atomic.AddInt32(r.holders, -1)
+	r.kunStr, r.holders = incoming.kunStr, incoming.holders
+}
+
+func newHolders() *int32 {
+	n := int32(1)
+	return &n
+}
+
+// mutable returns the data of the resource, first
+// copying it if it's shared, to change it.
+func (r *Resource) mutable() ifc.Kunstructured {
+	if atomic.LoadInt32(r.holders) > 1 {
+		// the other holders may change the data
+		// once this one lets go of it, not before
+		k := r.kunStr.Copy()
+		atomic.AddInt32(r.holders, -1)
+		r.kunStr, r.holders = k, newHolders()
+	}
+	return r.kunStr
 }
 
 func (r *Resource) GetAnnotations() map[string]string {
@@ -68,7 +111,67 @@ func (r *Resource) GetString(p string) (string, error) {
 	return r.kunStr.GetString(p)
 }
 
+// Map returns the data of the resource, which the
+// caller may change; see PeekMap.
+// Data held in a yaml.RNode is returned as a copy,
+// which changes don't reach; see HeldRNode.
 func (r *Resource) Map() map[string]interface{} {
+	return r.mutable().Map()
+}
+
+// HeldRNode returns the node holding the data of the
+// resource, which the caller may change in place, or
+// nil if the data isn't held in a yaml.RNode.
+// It implements filtersutil.RNodeHolder, so that
+// filters change resources without converting them.
+func (r *Resource) HeldRNode() *kyaml.RNode {
+	if _, ok := r.kunStr.(rNodeHolder); !ok {
+		return nil
+	}
+	return r.mutable().(rNodeHolder).HeldRNode()
+}
+
+// rNodeHolder matches filtersutil.RNodeHolder, which
+// isn't in the kyaml release Go plugins build against.
+type rNodeHolder interface {
+	HeldRNode() *kyaml.RNode
+}
+
+// PeekRNode returns the node holding the data of the
+// resource, as HeldRNode does, to a caller that won't
+// change it, sparing a copy of data shared with copies
+// of the resource.
+func (r *Resource) PeekRNode() *kyaml.RNode {
+	if h, ok := r.kunStr.(rNodeHolder); ok {
+		return h.HeldRNode()
+	}
+	return nil
+}
+
+// CopyRNode returns a copy of the data of the resource
+// in a node the caller may change.  Unlike GetRNode of
+// filtersutil, it doesn't first copy data shared with
+// copies of the resource.
+func (r *Resource) CopyRNode() (*kyaml.RNode, error) {
+	if node := r.PeekRNode(); node != nil {
+		return node.Copy(), nil
+	}
+	return filtersutil.GetRNode(r)
+}
+
+// IsEmpty returns true if the resource has no data,
+// e.g. once a patch deleted it.
+func (r *Resource) IsEmpty() bool {
+	if node := r.PeekRNode(); node != nil {
+		return len(node.Content()) == 0
+	}
+	return len(r.kunStr.Map()) == 0
+}
+
+// PeekMap returns the data of the resource, as Map does,
+// to a caller that won't change it, sparing a copy of data
+// shared with copies of the resource.
+func (r *Resource) PeekMap() map[string]interface{} {
 	return r.kunStr.Map()
 }
 
@@ -85,27 +188,27 @@ func (r *Resource) MatchesAnnotationSelector(selector string) (bool, error) {
 }
 
 func (r *Resource) SetAnnotations(m map[string]string) {
-	r.kunStr.SetAnnotations(m)
+	r.mutable().SetAnnotations(m)
 }
 
 func (r *Resource) SetGvk(gvk resid.Gvk) {
-	r.kunStr.SetGvk(gvk)
+	r.mutable().SetGvk(gvk)
 }
 
 func (r *Resource) SetLabels(m map[string]string) {
-	r.kunStr.SetLabels(m)
+	r.mutable().SetLabels(m)
 }
 
 func (r *Resource) SetName(n string) {
-	r.kunStr.SetName(n)
+	r.mutable().SetName(n)
 }
 
 func (r *Resource) SetNamespace(n string) {
-	r.kunStr.SetNamespace(n)
+	r.mutable().SetNamespace(n)
 }
 
 func (r *Resource) UnmarshalJSON(s []byte) error {
-	return r.kunStr.UnmarshalJSON(s)
+	return r.mutable().UnmarshalJSON(s)
 }
 
 // ResCtx is an interface describing the contextual added
@@ -124,12 +227,17 @@ type ResCtx interface {
 // modified in the same kustomize context.
 type ResCtxMatcher func(ResCtx) bool
 
-// DeepCopy returns a new copy of resource
+// DeepCopy returns a new copy of resource, sharing
+// its data until either changes it.
 func (r *Resource) DeepCopy() *Resource {
+	atomic.AddInt32(r.holders, 1)
 	rc := &Resource{
-		kunStr: r.Copy(),
+		kunStr:  r.kunStr,
+		holders: r.holders,
 	}
 	rc.copyOtherFields(r)
+	rc.origin = r.origin
+	rc.transformations = append([]Transformation(nil), r.transformations...)
 	return rc
 }
 
@@ -150,11 +258,13 @@ func (r *Resource) copyOtherFields(other *Resource) {
 	r.refVarNames = copyStringSlice(other.refVarNames)
 	r.namePrefixes = copyStringSlice(other.namePrefixes)
 	r.nameSuffixes = copyStringSlice(other.nameSuffixes)
+	// The transformations of other happened first.
+	r.transformations = append(
+		append([]Transformation(nil), other.transformations...), r.transformations...)
 }
 
 func (r *Resource) Equals(o *Resource) bool {
-	return r.ReferencesEqual(o) &&
-		reflect.DeepEqual(r.kunStr, o.kunStr)
+	return r.ReferencesEqual(o) && r.KunstructEqual(o)
 }
 
 func (r *Resource) ReferencesEqual(o *Resource) bool {
@@ -172,13 +282,29 @@ func (r *Resource) ReferencesEqual(o *Resource) bool {
 	return len(setSelf) == len(setOther)
 }
 
+// KunstructEqual returns true if the resources have the
+// same data.  Data held in yaml.RNodes is compared as
+// YAML, as the nodes also hold where they were read from.
 func (r *Resource) KunstructEqual(o *Resource) bool {
-	return reflect.DeepEqual(r.kunStr, o.kunStr)
+	if r.kunStr == o.kunStr {
+		return true
+	}
+	n1, n2 := r.PeekRNode(), o.PeekRNode()
+	if n1 == nil || n2 == nil {
+		return reflect.DeepEqual(r.kunStr, o.kunStr)
+	}
+	s1, err1 := n1.String()
+	s2, err2 := n2.String()
+	return err1 == nil && err2 == nil && s1 == s2
 }
 
 // Merge performs merge with other resource.
 func (r *Resource) Merge(other *Resource) {
 	r.Replace(other)
+	if node := r.HeldRNode(); node != nil {
+		mergeConfigmapNode(node, other.PeekRNode())
+		return
+	}
 	mergeConfigmap(r.Map(), other.Map(), r.Map())
 }
 
@@ -297,6 +423,33 @@ func (r *Resource) setOriginalNs(n string) *Resource {
 	return r
 }
 
+// GetOrigin returns the path of the file or kustomization
+// root the resource was read from, or the empty string
+// if unknown.
+func (r *Resource) GetOrigin() string {
+	return r.origin
+}
+
+// SetOrigin records where the resource was read from.
+func (r *Resource) SetOrigin(o string) {
+	r.origin = o
+}
+
+// GetTransformations returns the generators and transformers
+// recorded as having made or changed the resource.
+func (r *Resource) GetTransformations() []Transformation {
+	return r.transformations
+}
+
+// AddTransformation records that a generator or transformer made
+// or changed the resource, unless it's the last one recorded.
+func (r *Resource) AddTransformation(t Transformation) {
+	if n := len(r.transformations); n > 0 && r.transformations[n-1] == t {
+		return
+	}
+	r.transformations = append(r.transformations, t)
+}
+
 // String returns resource as JSON.
 func (r *Resource) String() string {
 	bs, err := r.MarshalJSON()
@@ -308,7 +461,13 @@ func (r *Resource) String() string {
 
 // AsYAML returns the resource in Yaml form.
 // Easier to read than JSON.
+// Data held in a yaml.RNode keeps the order of its
+// fields, its comments and the style of its values.
 func (r *Resource) AsYAML() ([]byte, error) {
+	if node := r.PeekRNode(); node != nil {
+		s, err := node.String()
+		return []byte(s), err
+	}
 	json, err := r.MarshalJSON()
 	if err != nil {
 		return nil, err
@@ -391,6 +550,23 @@ func mergeConfigmap(
 	mergedTo["data"] = mergedMap
 }
 
+// mergeConfigmapNode merges the data of other into that
+// of node, whose values win, keeping the order of the
+// data of other, followed by the new keys of node.
+func mergeConfigmapNode(node, other *kyaml.RNode) {
+	data := &kyaml.Node{Kind: kyaml.MappingNode}
+	if f := other.Field("data"); f != nil && f.Value.YNode().Kind == kyaml.MappingNode {
+		data.Content = append(data.Content, kyaml.CopyYNode(f.Value.YNode()).Content...)
+	}
+	if f := node.Field("data"); f != nil && f.Value.YNode().Kind == kyaml.MappingNode {
+		d := kyaml.NewRNode(data)
+		_ = f.Value.VisitFields(func(n *kyaml.MapNode) error {
+			return d.PipeE(kyaml.SetField(n.Key.YNode().Value, n.Value))
+		})
+	}
+	_ = node.PipeE(kyaml.SetField("data", kyaml.NewRNode(data)))
+}
+
 func mergeStringMaps(maps ...map[string]string) map[string]string {
 	result := map[string]string{}
 	for _, m := range maps {
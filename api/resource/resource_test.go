@@ -17,13 +17,17 @@ limitations under the License.
 package resource_test
 
 import (
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
+	"sigs.k8s.io/kustomize/api/internal/wrappy"
 	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/api/resid"
 	. "sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 var factory = NewFactory(
@@ -137,3 +141,99 @@ func TestDeepCopy(t *testing.T) {
 		t.Errorf("expected %v\nbut got%v", r, cr)
 	}
 }
+
+func TestDeepCopySharesDataUntilChanged(t *testing.T) {
+	r := factory.FromMap(
+		map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "pooh",
+			},
+		})
+	cr := r.DeepCopy()
+	if !r.KunstructEqual(cr) {
+		t.Fatalf("expected %v\nbut got %v", r, cr)
+	}
+
+	cr.SetName("tigger")
+	if r.GetName() != "pooh" {
+		t.Fatalf("renaming the copy renamed the original to %s", r.GetName())
+	}
+	r.SetNamespace("hundred-acre-wood")
+	if cr.GetNamespace() != "" {
+		t.Fatalf("changing the original changed the copy's namespace to %s",
+			cr.GetNamespace())
+	}
+
+	cr = r.DeepCopy()
+	cr.Map()["spec"] = map[string]interface{}{"replicas": 3}
+	if _, ok := r.PeekMap()["spec"]; ok {
+		t.Fatalf("changing the map of the copy changed the original")
+	}
+
+	o := factory.FromMap(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "owl"},
+	})
+	r.ResetPrimaryData(o)
+	r.SetName("eeyore")
+	if o.GetName() != "owl" {
+		t.Fatalf("changing the reset resource renamed its source to %s",
+			o.GetName())
+	}
+}
+
+func TestDeepCopyConcurrently(t *testing.T) {
+	r := factory.FromMap(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "pooh",
+			},
+		})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cr := r.DeepCopy()
+			cr.SetName(fmt.Sprintf("pooh-%d", i))
+		}(i)
+	}
+	wg.Wait()
+	if r.GetName() != "pooh" {
+		t.Fatalf("renaming the copies renamed the original to %s", r.GetName())
+	}
+}
+
+func TestCopyRNode(t *testing.T) {
+	f := NewFactory(&wrappy.WNodeFactory{})
+	r := f.FromMap(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "pooh",
+			},
+		})
+	cr := r.DeepCopy()
+	node, err := cr.CopyRNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = node.PipeE(
+		kyaml.Lookup(kyaml.MetadataField),
+		kyaml.SetField(kyaml.NameField, kyaml.NewScalarRNode("tigger")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.GetName() != "pooh" || cr.GetName() != "pooh" {
+		t.Fatalf("changing the copied node renamed the resources")
+	}
+	if cr.PeekRNode() != r.PeekRNode() {
+		t.Fatalf("copying the node of the copy copied its data")
+	}
+}
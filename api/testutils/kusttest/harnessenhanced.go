@@ -37,6 +37,9 @@ type HarnessEnhanced struct {
 
 	// A plugin loader that loads plugins from a (real) file system.
 	pl *pLdr.Loader
+
+	// The config of the plugin loader.
+	pc *types.PluginConfig
 }
 
 func MakeEnhancedHarness(t *testing.T) *HarnessEnhanced {
@@ -56,7 +59,8 @@ func MakeEnhancedHarness(t *testing.T) *HarnessEnhanced {
 		Harness: MakeHarness(t),
 		pte:     pte,
 		rf:      resmapFactory,
-		pl:      pLdr.NewLoader(pc, resmapFactory)}
+		pl:      pLdr.NewLoader(pc, resmapFactory),
+		pc:      pc}
 
 	// Point the file loader to the root ('/') of the in-memory file system.
 	result.ResetLoaderRoot(filesys.Separator)
@@ -72,6 +76,12 @@ func (th *HarnessEnhanced) PrepBuiltin(k string) *HarnessEnhanced {
 	return th.BuildGoPlugin(konfig.BuiltinPluginPackage, "", k)
 }
 
+// EnableGoTemplate lets the GoTemplateGenerator builtin be loaded.
+func (th *HarnessEnhanced) EnableGoTemplate() *HarnessEnhanced {
+	th.pc.EnableGoTemplate = true
+	return th
+}
+
 func (th *HarnessEnhanced) BuildGoPlugin(g, v, k string) *HarnessEnhanced {
 	th.pte.prepareGoPlugin(g, v, k)
 	return th
@@ -0,0 +1,223 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package trace records the changes each transformer
+// makes to each resource during a build.
+package trace
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// maxValueLen is the length beyond which values
+// are abbreviated in the text output.
+const maxValueLen = 60
+
+// Change is the change of one leaf field of a resource.
+type Change struct {
+	// Path is the dot separated path to the field,
+	// with list indices in brackets.
+	Path string
+	// Before and After are the formatted field values,
+	// empty if the field is absent.
+	Before string
+	After  string
+}
+
+// Entry records the changes one transformer
+// made to one resource.
+type Entry struct {
+	// Resource is the original id of the resource,
+	// unaffected by name prefixes and the like.
+	Resource string
+	// Root is the kustomization root the transformer ran in.
+	Root        string
+	Transformer string
+	Changes     []Change
+}
+
+// Trace collects entries.  A nil *Trace is valid
+// and records nothing, so callers needn't check.
+type Trace struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// New returns an empty Trace.
+func New() *Trace {
+	return &Trace{}
+}
+
+type snapshot struct {
+	byResource map[*resource.Resource]map[string]string
+	byId       map[string]map[string]string
+}
+
+// Start snapshots the given resources before a transformer
+// runs, returning the function that compares them to the
+// snapshot afterwards and records the differences.
+func (t *Trace) Start(root, transformer string, m resmap.ResMap) func() {
+	if t == nil {
+		return func() {}
+	}
+	before := snapshot{
+		byResource: make(map[*resource.Resource]map[string]string),
+		byId:       make(map[string]map[string]string),
+	}
+	for _, r := range m.Resources() {
+		f := flatten(r.PeekMap())
+		before.byResource[r] = f
+		before.byId[r.OrgId().String()] = f
+	}
+	return func() {
+		for _, r := range m.Resources() {
+			id := r.OrgId().String()
+			old, ok := before.byResource[r]
+			if !ok {
+				old = before.byId[id]
+			}
+			changes := diff(old, flatten(r.PeekMap()))
+			if len(changes) == 0 {
+				continue
+			}
+			t.add(Entry{
+				Resource:    id,
+				Root:        root,
+				Transformer: transformer,
+				Changes:     changes,
+			})
+		}
+	}
+}
+
+func (t *Trace) add(e Entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, e)
+}
+
+// Entries returns the recorded entries in the order they were made.
+func (t *Trace) Entries() []Entry {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	result := make([]Entry, len(t.entries))
+	copy(result, t.entries)
+	return result
+}
+
+// WriteText writes the entries grouped by resource, with the
+// transformers affecting each resource in the order they ran.
+func (t *Trace) WriteText(w io.Writer) error {
+	var ids []string
+	byId := make(map[string][]Entry)
+	for _, e := range t.Entries() {
+		if _, ok := byId[e.Resource]; !ok {
+			ids = append(ids, e.Resource)
+		}
+		byId[e.Resource] = append(byId[e.Resource], e)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, err := fmt.Fprintf(w, "%s\n", id); err != nil {
+			return err
+		}
+		for _, e := range byId[id] {
+			fmt.Fprintf(w, "  %s (%s)\n", e.Transformer, e.Root)
+			for _, c := range e.Changes {
+				fmt.Fprintf(w, "    %s\n", c)
+			}
+		}
+	}
+	return nil
+}
+
+// String formats the change compactly, e.g.
+// "metadata.labels.app: + bingo".
+func (c Change) String() string {
+	switch {
+	case c.Before == "":
+		return c.Path + ": + " + abbreviate(c.After)
+	case c.After == "":
+		return c.Path + ": - " + abbreviate(c.Before)
+	default:
+		return c.Path + ": " + abbreviate(c.Before) + " -> " + abbreviate(c.After)
+	}
+}
+
+func abbreviate(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > maxValueLen {
+		return s[:maxValueLen-3] + "..."
+	}
+	return s
+}
+
+// diff returns the changes from one flattened
+// resource to another, sorted by path.
+func diff(before, after map[string]string) []Change {
+	var result []Change
+	for p, b := range before {
+		if a, ok := after[p]; !ok || a != b {
+			result = append(result, Change{Path: p, Before: b, After: after[p]})
+		}
+	}
+	for p, a := range after {
+		if _, ok := before[p]; !ok {
+			result = append(result, Change{Path: p, After: a})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+// flatten maps the path of each leaf field
+// of a resource to its formatted value.
+func flatten(m map[string]interface{}) map[string]string {
+	result := make(map[string]string)
+	flattenInto(result, "", m)
+	return result
+}
+
+func flattenInto(result map[string]string, path string, v interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(x) == 0 {
+			result[path] = "{}"
+		}
+		for k, e := range x {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			flattenInto(result, p, e)
+		}
+	case []interface{}:
+		if len(x) == 0 {
+			result[path] = "[]"
+		}
+		for i, e := range x {
+			flattenInto(result, fmt.Sprintf("%s[%d]", path, i), e)
+		}
+	case nil:
+		result[path] = "null"
+	case string:
+		if x == "" {
+			x = `""`
+		}
+		result[path] = x
+	default:
+		result[path] = fmt.Sprintf("%v", x)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNilTrace(t *testing.T) {
+	var tr *Trace
+	tr.Start("/app", "LabelTransformer", nil)()
+	if len(tr.Entries()) != 0 {
+		t.Fatalf("expected no entries")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := flatten(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":   "cm",
+			"labels": map[string]interface{}{"app": "a", "old": "x"},
+		},
+		"data": map[string]interface{}{"k": ""},
+	})
+	after := flatten(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":   "dev-cm",
+			"labels": map[string]interface{}{"app": "a", "new": "y"},
+		},
+		"data":  map[string]interface{}{"k": ""},
+		"items": []interface{}{int64(3), nil},
+	})
+	expected := []Change{
+		{Path: "items[0]", After: "3"},
+		{Path: "items[1]", After: "null"},
+		{Path: "metadata.labels.new", After: "y"},
+		{Path: "metadata.labels.old", Before: "x"},
+		{Path: "metadata.name", Before: "cm", After: "dev-cm"},
+	}
+	if actual := diff(before, after); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	for c, expected := range map[Change]string{
+		{Path: "a", After: "1"}:              "a: + 1",
+		{Path: "a", Before: "1"}:             "a: - 1",
+		{Path: "a", Before: "1", After: "2"}: "a: 1 -> 2",
+		{Path: "a", After: "line1\nline2 and some more words that " +
+			"make this value rather long"}: "a: + line1 line2 and some more words that make this value rath...",
+	} {
+		if actual := c.String(); actual != expected {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// The class of a build failure, allowing callers
+// to react to it without parsing error text.
+//
+//go:generate stringer -type=BuildErrorKind
+type BuildErrorKind int
+
+const (
+	BuildErrorUnknown BuildErrorKind = iota
+
+	// A kustomization file, or a file of resources,
+	// couldn't be parsed, or holds illegal values.
+	BuildErrorParse
+
+	// A file or directory referred to by a kustomization
+	// file doesn't exist (or isn't of the expected type).
+	BuildErrorMissingFile
+
+	// A remote base couldn't be fetched.
+	BuildErrorRemoteFetch
+
+	// A generator or transformer plugin couldn't
+	// be loaded, or failed when run.
+	BuildErrorPlugin
+
+	// A validator rejected the resources, or
+	// tried to modify them.
+	BuildErrorValidation
+)
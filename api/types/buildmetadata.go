@@ -0,0 +1,39 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+const (
+	// OriginAnnotations, as a buildMetadata option, annotates
+	// each resource with the file it was read from, or with
+	// the generator that made it.
+	OriginAnnotations = "originAnnotations"
+
+	// TransformerAnnotations, as a buildMetadata option, annotates
+	// each resource with the generators and transformers that made
+	// or changed it.
+	TransformerAnnotations = "transformerAnnotations"
+)
+
+// BuildMetadataOptions are the legal buildMetadata options.
+var BuildMetadataOptions = []string{OriginAnnotations, TransformerAnnotations}
+
+func isBuildMetadataOption(o string) bool {
+	for _, legal := range BuildMetadataOptions {
+		if o == legal {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBuildMetadata returns whether the kustomization
+// lists the given buildMetadata option.
+func (k *Kustomization) HasBuildMetadata(o string) bool {
+	for _, x := range k.BuildMetadata {
+		if x == o {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	CatalogVersion = "kustomize.config.k8s.io/v1alpha1"
+	CatalogKind    = "Catalog"
+)
+
+// Catalog lists the functions and plugins approved to
+// run.  Builtins needn't be listed.
+type Catalog struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Functions are the approved functions and plugins.
+	Functions []CatalogEntry `json:"functions,omitempty" yaml:"functions,omitempty"`
+}
+
+// CatalogEntry approves a container image, or an executable
+// by its digest.  The executable may be that of an exec
+// function, or of an exec, Go or RPC plugin.
+type CatalogEntry struct {
+	// Name describes the function.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Image of a container function, matched exactly;
+	// pin it by digest.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+
+	// Sha256 is the hex SHA256 digest of an executable.
+	Sha256 string `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+}
+
+// Validate returns an error if the catalog is malformed.
+func (c *Catalog) Validate() error {
+	if c.Kind != CatalogKind {
+		return fmt.Errorf("expected kind %s, got %q", CatalogKind, c.Kind)
+	}
+	for i, e := range c.Functions {
+		if (e.Image == "") == (e.Sha256 == "") {
+			return fmt.Errorf(
+				"function %d of catalog %s must have exactly one of image and sha256",
+				i, c.Name)
+		}
+	}
+	return nil
+}
+
+// Merge adds the functions of the other catalog.
+func (c *Catalog) Merge(other *Catalog) {
+	c.Functions = append(c.Functions, other.Functions...)
+}
+
+// ApprovesImage returns true if the image is approved.
+func (c *Catalog) ApprovesImage(image string) bool {
+	for _, e := range c.Functions {
+		if e.Image != "" && e.Image == image {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovesSha256 returns true if the executable
+// of the given hex digest is approved.
+func (c *Catalog) ApprovesSha256(digest string) bool {
+	for _, e := range c.Functions {
+		if e.Sha256 != "" && strings.EqualFold(e.Sha256, digest) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"strings"
+)
+
+// errAggregate reports several errors found at once.
+type errAggregate struct {
+	msg  string
+	errs []error
+}
+
+func (e *errAggregate) Error() string {
+	m := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		m[i] = err.Error()
+	}
+	return e.msg + ":\n" + strings.Join(m, "\n")
+}
+
+// NewErrAggregate returns an error with the given summary
+// message, followed by the messages of the given errors,
+// one per line.
+func NewErrAggregate(msg string, errs []error) error {
+	return &errAggregate{msg: msg, errs: errs}
+}
+
+// AggregatedErrors returns the errors aggregated by err,
+// or by one of its causes, or nil if there are none.
+func AggregatedErrors(err error) []error {
+	var result []error
+	walkCauses(err, func(e error) {
+		if a, ok := e.(*errAggregate); ok && result == nil {
+			result = a.errs
+		}
+	})
+	return result
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// errBuild attaches a BuildErrorKind to an error,
+// without changing its message.
+type errBuild struct {
+	kind BuildErrorKind
+	err  error
+}
+
+func (e *errBuild) Error() string {
+	return e.err.Error()
+}
+
+// Cause lets errors.Cause see through the classification.
+func (e *errBuild) Cause() error {
+	return e.err
+}
+
+// NewErrBuild classifies the given error.  If the error
+// is already classified, the existing kind is kept.
+func NewErrBuild(k BuildErrorKind, err error) error {
+	if err == nil || BuildErrorKindOf(err) != BuildErrorUnknown {
+		return err
+	}
+	return &errBuild{kind: k, err: err}
+}
+
+// BuildErrorKindOf returns the kind of the outermost
+// classified error in the chain of causes of err,
+// or BuildErrorUnknown if there is none.
+func BuildErrorKindOf(err error) BuildErrorKind {
+	result := BuildErrorUnknown
+	walkCauses(err, func(e error) {
+		if b, ok := e.(*errBuild); ok && result == BuildErrorUnknown {
+			result = b.kind
+		}
+	})
+	return result
+}
+
+// walkCauses calls f on err and each of its causes,
+// outermost first.
+func walkCauses(err error, f func(error)) {
+	for err != nil {
+		f(err)
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			return
+		}
+		err = c.Cause()
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestBuildErrorKindOf(t *testing.T) {
+	plain := fmt.Errorf("boom")
+	if k := BuildErrorKindOf(plain); k != BuildErrorUnknown {
+		t.Fatalf("expected unknown, got %v", k)
+	}
+	err := errors.Wrap(NewErrBuild(BuildErrorPlugin, plain), "running plugin")
+	if k := BuildErrorKindOf(err); k != BuildErrorPlugin {
+		t.Fatalf("expected plugin, got %v", k)
+	}
+	if err.Error() != "running plugin: boom" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if errors.Cause(err) != plain {
+		t.Fatalf("expected cause to be the original error")
+	}
+	// An existing classification isn't replaced.
+	err = NewErrBuild(BuildErrorValidation, err)
+	if k := BuildErrorKindOf(err); k != BuildErrorPlugin {
+		t.Fatalf("expected plugin, got %v", k)
+	}
+	if NewErrBuild(BuildErrorPlugin, nil) != nil {
+		t.Fatalf("expected nil")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// ErrorLocation says where in the input of a build
+// an error was found.  Any of the fields may be empty.
+type ErrorLocation struct {
+	// File is the path of the file at fault.
+	File string `json:"file,omitempty"`
+	// ResourceId identifies the resource at fault.
+	ResourceId string `json:"resourceId,omitempty"`
+	// FieldPath is the path to the field at fault,
+	// within the file or resource.
+	FieldPath string `json:"fieldPath,omitempty"`
+}
+
+// errLocated attaches an ErrorLocation to an error,
+// without changing its message.
+type errLocated struct {
+	loc ErrorLocation
+	err error
+}
+
+func (e *errLocated) Error() string {
+	return e.err.Error()
+}
+
+// Cause lets errors.Cause see through the location.
+func (e *errLocated) Cause() error {
+	return e.err
+}
+
+// NewErrLocated attaches the location to the given error,
+// unless the location is empty.
+func NewErrLocated(err error, loc ErrorLocation) error {
+	if err == nil || loc == (ErrorLocation{}) {
+		return err
+	}
+	return &errLocated{loc: loc, err: err}
+}
+
+// ErrorLocationOf returns the location of the error.  As
+// errors are wrapped on the way up from where they occur,
+// the innermost location naming a file is the most precise,
+// and is used along with the innermost resource id.
+func ErrorLocationOf(err error) ErrorLocation {
+	var result ErrorLocation
+	walkCauses(err, func(e error) {
+		l, ok := e.(*errLocated)
+		if !ok {
+			return
+		}
+		if l.loc.File != "" {
+			result.File = l.loc.File
+			result.FieldPath = l.loc.FieldPath
+		}
+		if l.loc.ResourceId != "" {
+			result.ResourceId = l.loc.ResourceId
+		}
+	})
+	return result
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestErrorLocationOf(t *testing.T) {
+	plain := fmt.Errorf("boom")
+	if NewErrLocated(plain, ErrorLocation{}) != plain {
+		t.Fatalf("expected an empty location to be dropped")
+	}
+	err := NewErrLocated(plain, ErrorLocation{ResourceId: "cm"})
+	err = NewErrLocated(errors.Wrap(err, "merging"), ErrorLocation{File: "/base/cm.yaml"})
+	err = NewErrLocated(err, ErrorLocation{File: "/kustomization.yaml", FieldPath: "resources"})
+	expected := ErrorLocation{File: "/base/cm.yaml", ResourceId: "cm"}
+	if loc := ErrorLocationOf(err); loc != expected {
+		t.Fatalf("expected %v, got %v", expected, loc)
+	}
+	if err.Error() != "merging: boom" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+}
+
+func TestAggregatedErrors(t *testing.T) {
+	errs := []error{fmt.Errorf("one"), fmt.Errorf("two")}
+	err := errors.Wrap(NewErrAggregate("problems", errs), "loading")
+	if err.Error() != "loading: problems:\none\ntwo" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if actual := AggregatedErrors(err); len(actual) != 2 || actual[1] != errs[1] {
+		t.Fatalf("unexpected errors %v", actual)
+	}
+	if AggregatedErrors(fmt.Errorf("x")) != nil {
+		t.Fatalf("expected nil")
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// ExecSandbox is the policy restricting what exec plugins
+// and exec functions may do on the host.
+type ExecSandbox string
+
+const (
+	// No restrictions; the default.
+	ExecSandboxNone ExecSandbox = "none"
+
+	// The filesystem is confined to the working directory,
+	// the directory of the executable and the temporary
+	// directory, besides the system directories needed to
+	// run programs, which are read-only.
+	ExecSandboxWorkdir ExecSandbox = "workdir"
+
+	// As ExecSandboxWorkdir, and furthermore network access
+	// and syscalls administering the host or inspecting
+	// other processes are refused.
+	ExecSandboxStrict ExecSandbox = "strict"
+)
+
+// ExecSandboxes are the legal policies, least restrictive first.
+var ExecSandboxes = []ExecSandbox{
+	ExecSandboxNone, ExecSandboxWorkdir, ExecSandboxStrict}
+
+// IsExecSandbox returns true if the string is a legal policy.
+func IsExecSandbox(s string) bool {
+	for _, x := range ExecSandboxes {
+		if string(x) == s {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ package types
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 
 	"sigs.k8s.io/yaml"
 )
@@ -134,9 +135,17 @@ type Kustomization struct {
 	// Validators is a list of files containing validators
 	Validators []string `json:"validators,omitempty" yaml:"validators,omitempty"`
 
+	// Pipeline lists KRM functions run on the resources
+	// after the transformers.
+	Pipeline *Pipeline `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
+
 	// Inventory appends an object that contains the record
 	// of all other objects, which can be used in apply, prune and delete
 	Inventory *Inventory `json:"inventory,omitempty" yaml:"inventory,omitempty"`
+
+	// BuildMetadata is a list of options for recording
+	// how the resources were built in their metadata.
+	BuildMetadata []string `json:"buildMetadata,omitempty" yaml:"buildMetadata,omitempty"`
 }
 
 // FixKustomizationPostUnmarshalling fixes things
@@ -182,6 +191,21 @@ func (k *Kustomization) EnforceFields() []string {
 	if k.APIVersion != "" && k.APIVersion != requiredVersion {
 		errs = append(errs, "apiVersion for "+k.Kind+" should be "+requiredVersion)
 	}
+	for _, o := range k.BuildMetadata {
+		if !isBuildMetadataOption(o) {
+			errs = append(errs, fmt.Sprintf(
+				"buildMetadata option %s is unknown; legal options: %v", o, BuildMetadataOptions))
+		}
+	}
+	if k.Pipeline != nil {
+		for _, fns := range [][]Function{k.Pipeline.Mutators, k.Pipeline.Validators} {
+			for _, f := range fns {
+				if err := f.Validate(); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+		}
+	}
 	return errs
 }
 
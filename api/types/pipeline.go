@@ -0,0 +1,45 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "fmt"
+
+// Pipeline lists KRM functions run on the resources once
+// they're generated and transformed.  The functions run
+// as plugins, subject to the same restrictions.
+type Pipeline struct {
+	// Mutators change the resources, in order,
+	// after the transformers.
+	Mutators []Function `json:"mutators,omitempty" yaml:"mutators,omitempty"`
+
+	// Validators check the resources, in order, after
+	// the mutators, and mustn't change them.
+	Validators []Function `json:"validators,omitempty" yaml:"validators,omitempty"`
+}
+
+// Function is a KRM function run in a container.
+type Function struct {
+	// Image is the container image of the function.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+
+	// ConfigPath is the relative path to a file
+	// holding the function config.
+	ConfigPath string `json:"configPath,omitempty" yaml:"configPath,omitempty"`
+
+	// ConfigMap is the data of a ConfigMap passed as
+	// the function config, instead of a file.
+	ConfigMap map[string]string `json:"configMap,omitempty" yaml:"configMap,omitempty"`
+}
+
+// Validate returns an error if the function is ill-formed.
+func (f Function) Validate() error {
+	if f.Image == "" {
+		return fmt.Errorf("pipeline function must have an image")
+	}
+	if f.ConfigPath != "" && len(f.ConfigMap) > 0 {
+		return fmt.Errorf(
+			"pipeline function %s may have a configPath or a configMap, not both", f.Image)
+	}
+	return nil
+}
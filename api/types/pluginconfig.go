@@ -24,9 +24,27 @@ type PluginConfig struct {
 	// The value of AbsPluginHome can be any absolute path.
 	AbsPluginHome string
 
+	// PluginSearchPath lists further directories, laid
+	// out like AbsPluginHome, searched in order for the
+	// plugins missing from AbsPluginHome.
+	PluginSearchPath []string
+
 	// PluginRestrictions distinguishes plugin restrictions.
 	PluginRestrictions PluginRestrictions
 
+	// When true, the GoTemplateGenerator builtin may be used.
+	// It's disabled by default, since its templates can
+	// generate anything.
+	EnableGoTemplate bool
+
+	// When non-nil, plugins other than builtins may be
+	// loaded only if approved by this catalog.
+	Catalog *Catalog
+
+	// Limits bound the resources of plugins run in their
+	// own processes, unless their configs override them.
+	Limits PluginLimits
+
 	// BpLoadingOptions distinguishes builtin plugin behaviors.
 	BpLoadingOptions BuiltinPluginLoadingOptions
 
@@ -0,0 +1,86 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// Annotations on the config of a plugin overriding
+	// its limits, e.g. "90s", "512Mi" and "16Mi".
+	PluginTimeoutAnnotation   = "kustomize.config.k8s.io/plugin-timeout"
+	PluginMaxMemoryAnnotation = "kustomize.config.k8s.io/plugin-max-memory"
+	PluginMaxOutputAnnotation = "kustomize.config.k8s.io/plugin-max-output"
+)
+
+// PluginLimits bound the resources a plugin running in
+// its own process or container may use, so that a hung
+// or runaway plugin fails the build.  Zero means no bound.
+type PluginLimits struct {
+	// Timeout is the time the plugin may run.
+	Timeout time.Duration
+
+	// MaxMemory is the memory, in bytes,
+	// the plugin's process may allocate.
+	MaxMemory int64
+
+	// MaxOutput is the size, in bytes, of the
+	// output the plugin may write.
+	MaxOutput int64
+}
+
+// DefaultPluginLimits returns the limits of plugins
+// whose configs and the command line don't say otherwise.
+func DefaultPluginLimits() PluginLimits {
+	return PluginLimits{
+		Timeout:   5 * time.Minute,
+		MaxMemory: 4 << 30,
+		MaxOutput: 256 << 20,
+	}
+}
+
+// Override returns the limits, overridden
+// per the annotations of a plugin config.
+func (l PluginLimits) Override(annotations map[string]string) (PluginLimits, error) {
+	if s, ok := annotations[PluginTimeoutAnnotation]; ok {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return l, fmt.Errorf(
+				"illegal %s annotation %q; expected a duration such as 90s",
+				PluginTimeoutAnnotation, s)
+		}
+		l.Timeout = d
+	}
+	for _, x := range []struct {
+		key  string
+		size *int64
+	}{
+		{PluginMaxMemoryAnnotation, &l.MaxMemory},
+		{PluginMaxOutputAnnotation, &l.MaxOutput},
+	} {
+		s, ok := annotations[x.key]
+		if !ok {
+			continue
+		}
+		size, err := ParseSize(s)
+		if err != nil {
+			return l, fmt.Errorf("illegal %s annotation: %v", x.key, err)
+		}
+		*x.size = size
+	}
+	return l, nil
+}
+
+// ParseSize returns the bytes in a size such as "512Mi".
+func ParseSize(s string) (int64, error) {
+	q, err := resource.ParseQuantity(s)
+	if err != nil || q.Sign() < 0 {
+		return 0, fmt.Errorf("%q isn't a size such as 512Mi", s)
+	}
+	return q.Value(), nil
+}
@@ -25,6 +25,10 @@ const (
 
 	// No restrictions, do whatever you want.
 	PluginRestrictionsNone
+
+	// Besides builtins, only plugins that run in
+	// containers, rather than on the host, are enabled.
+	PluginRestrictionsContainersOnly
 )
 
 // BuiltinPluginLoadingOptions distinguish ways in which builtin plugins are used.
@@ -53,4 +57,16 @@ type FnPluginLoadingOptions struct {
 	NetworkName string
 	// list of mounts
 	Mounts []string
+	// Directory caching the output of container
+	// functions; nothing is cached when empty
+	CacheDir string
+	// Run the functions even when their output is
+	// cached, replacing the cached output
+	RefreshCache bool
+	// Restrictions on exec functions, and exec
+	// and rpc plugins; none, if empty
+	ExecSandbox ExecSandbox
+	// Platform, os/arch[/variant], of the images
+	// of container functions; the host's, if empty
+	Platform string
 }
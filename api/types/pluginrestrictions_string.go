@@ -11,11 +11,12 @@ func _() {
 	_ = x[PluginRestrictionsUnknown-0]
 	_ = x[PluginRestrictionsBuiltinsOnly-1]
 	_ = x[PluginRestrictionsNone-2]
+	_ = x[PluginRestrictionsContainersOnly-3]
 }
 
-const _PluginRestrictions_name = "PluginRestrictionsUnknownPluginRestrictionsBuiltinsOnlyPluginRestrictionsNone"
+const _PluginRestrictions_name = "PluginRestrictionsUnknownPluginRestrictionsBuiltinsOnlyPluginRestrictionsNonePluginRestrictionsContainersOnly"
 
-var _PluginRestrictions_index = [...]uint8{0, 25, 55, 77}
+var _PluginRestrictions_index = [...]uint8{0, 25, 55, 77, 109}
 
 func (i PluginRestrictions) String() string {
 	if i < 0 || i >= PluginRestrictions(len(_PluginRestrictions_index)-1) {
@@ -0,0 +1,141 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is how bad a finding of a validator is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+
+	// SeverityNone is more than any finding; as a
+	// threshold, it means no finding fails the build.
+	SeverityNone Severity = "none"
+)
+
+// Severities are the legal thresholds, most severe first.
+var Severities = []Severity{
+	SeverityNone, SeverityError, SeverityWarning, SeverityInfo}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityNone:
+		return 4
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// AtLeast returns true if the severity is at
+// least the given threshold.  An empty threshold
+// is taken to be SeverityError.
+func (s Severity) AtLeast(threshold Severity) bool {
+	if threshold == "" {
+		threshold = SeverityError
+	}
+	return s.rank() >= threshold.rank()
+}
+
+// IsSeverity returns true if the string is one of the Severities.
+func IsSeverity(s string) bool {
+	for _, x := range Severities {
+		if string(x) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Finding is a problem a validator found with the resources.
+type Finding struct {
+	// Validator is the name of the validator.
+	Validator string `json:"validator,omitempty" yaml:"validator,omitempty"`
+
+	Severity Severity `json:"severity" yaml:"severity"`
+
+	Message string `json:"message" yaml:"message"`
+
+	// Resource identifies the resource at fault, if any.
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
+
+	// Field is the path to the field at fault, if any.
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%s: %s", f.Severity, f.Message)
+	var where []string
+	if f.Validator != "" {
+		where = append(where, "validator "+f.Validator)
+	}
+	if f.Resource != "" {
+		where = append(where, f.Resource)
+	}
+	if f.Field != "" {
+		where = append(where, "field "+f.Field)
+	}
+	if len(where) > 0 {
+		s += " (" + strings.Join(where, ", ") + ")"
+	}
+	return s
+}
+
+// ValidationReport aggregates the findings of the
+// validators run during a build.  The zero value
+// is an empty report.
+type ValidationReport struct {
+	Findings []Finding `json:"findings,omitempty" yaml:"findings,omitempty"`
+}
+
+// Add adds the findings of the validator to the report.
+func (r *ValidationReport) Add(validator string, findings []Finding) {
+	for _, f := range findings {
+		if f.Validator == "" {
+			f.Validator = validator
+		}
+		if f.Severity == "" {
+			f.Severity = SeverityError
+		}
+		r.Findings = append(r.Findings, f)
+	}
+}
+
+// AtLeast returns the findings at least as
+// severe as the threshold.
+func (r *ValidationReport) AtLeast(threshold Severity) []Finding {
+	var result []Finding
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(threshold) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// ErrIfAtLeast returns an error listing the findings
+// at least as severe as the threshold, if any.
+func (r *ValidationReport) ErrIfAtLeast(threshold Severity) error {
+	bad := r.AtLeast(threshold)
+	if len(bad) == 0 {
+		return nil
+	}
+	lines := make([]string, len(bad))
+	for i, f := range bad {
+		lines[i] = f.String()
+	}
+	return fmt.Errorf(
+		"validators reported %d finding(s) failing the build:\n  %s",
+		len(bad), strings.Join(lines, "\n  "))
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+)
+
+func TestValidationReport(t *testing.T) {
+	var r ValidationReport
+	r.Add("replicas", []Finding{
+		{Message: "too many replicas", Resource: "Deployment app", Field: "spec.replicas"},
+		{Severity: SeverityWarning, Message: "no limits"},
+	})
+	r.Add("labels", []Finding{{Severity: SeverityInfo, Message: "fine"}})
+	testCases := map[Severity]string{
+		"": "validators reported 1 finding(s) failing the build:\n" +
+			"  error: too many replicas (validator replicas, Deployment app, field spec.replicas)",
+		SeverityWarning: "validators reported 2 finding(s) failing the build:\n" +
+			"  error: too many replicas (validator replicas, Deployment app, field spec.replicas)\n" +
+			"  warning: no limits (validator replicas)",
+		SeverityInfo: "validators reported 3 finding(s) failing the build:\n" +
+			"  error: too many replicas (validator replicas, Deployment app, field spec.replicas)\n" +
+			"  warning: no limits (validator replicas)\n" +
+			"  info: fine (validator labels)",
+		SeverityNone: "",
+	}
+	for threshold, expected := range testCases {
+		err := r.ErrIfAtLeast(threshold)
+		if expected == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", threshold, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != expected {
+			t.Errorf("%q: expected %q, got %v", threshold, expected, err)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/cmd/config/internal/generateddocs/commands"
 	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
 	"sigs.k8s.io/kustomize/kyaml/runfn"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
@@ -67,6 +68,9 @@ func GetRunFnRunner(name string) *RunFnRunner {
 		"a list of storage options read from the filesystem")
 	r.Command.Flags().StringVar(
 		&r.User, "fn-user", "nobody", "the username/uid used to run function in container")
+	r.Command.Flags().StringVar(
+		&r.Platform, "fn-platform", "",
+		"the platform, os/arch[/variant], of the variant of the function images to run; if empty, the local images are run whatever their platform")
 	r.Command.Flags().StringArrayVar(
 		&r.Env, "fn-env", []string{},
 		"a list of environment variables that will be exposed to container. Each item can be key=value pair or a key name of exported env.")
@@ -98,6 +102,7 @@ type RunFnRunner struct {
 	Mounts             []string
 	User               string
 	Env                []string
+	Platform           string
 }
 
 func (r *RunFnRunner) runE(c *cobra.Command, args []string) error {
@@ -260,6 +265,10 @@ func (r *RunFnRunner) preRunE(c *cobra.Command, args []string) error {
 		return errors.Errorf("must specify --enable-exec with --exec-path")
 	}
 
+	if r.Platform != "" && !container.IsPlatform(r.Platform) {
+		return errors.Errorf("--fn-platform must have the form os/arch[/variant]")
+	}
+
 	if c.ArgsLenAtDash() >= 0 && r.Image == "" &&
 		!(r.EnableStar && (r.StarPath != "" || r.StarURL != "")) && !(r.EnableExec && r.ExecPath != "") {
 		return errors.Errorf("must specify --image")
@@ -300,20 +309,22 @@ func (r *RunFnRunner) preRunE(c *cobra.Command, args []string) error {
 	storageMounts := toStorageMounts(r.Mounts)
 
 	r.RunFns = runfn.RunFns{
-		FunctionPaths:  r.FnPaths,
-		GlobalScope:    r.GlobalScope,
-		Functions:      fns,
-		Output:         output,
-		Input:          input,
-		Path:           path,
-		Network:        r.Network,
-		NetworkName:    r.NetworkName,
-		EnableStarlark: r.EnableStar,
-		EnableExec:     r.EnableExec,
-		StorageMounts:  storageMounts,
-		ResultsDir:     r.ResultsDir,
-		User:           runtimeutil.ContainerUser(r.User),
-		Env:            r.Env,
+		FunctionPaths:         r.FnPaths,
+		GlobalScope:           r.GlobalScope,
+		Functions:             fns,
+		Output:                output,
+		Input:                 input,
+		Path:                  path,
+		Network:               r.Network,
+		NetworkName:           r.NetworkName,
+		EnableStarlark:        r.EnableStar,
+		EnableExec:            r.EnableExec,
+		StorageMounts:         storageMounts,
+		ResultsDir:            r.ResultsDir,
+		User:                  runtimeutil.ContainerUser(r.User),
+		Env:                   r.Env,
+		ContainerPlatform:     r.Platform,
+		ContainerPullPlatform: r.Platform != "",
 	}
 
 	// don't consider args for the function
@@ -329,6 +329,34 @@ kind: ConfigMap
 apiVersion: v1
 `,
 		},
+		{
+			name: "specify platform",
+			args: []string{"run", "dir", "--fn-platform", "linux/arm64", "--image", "foo:bar"},
+			path: "dir",
+			expectedStruct: &runfn.RunFns{
+				Path:                  "dir",
+				NetworkName:           "bridge",
+				User:                  "nobody",
+				Env:                   []string{},
+				ContainerPlatform:     "linux/arm64",
+				ContainerPullPlatform: true,
+			},
+			expected: `
+metadata:
+  name: function-input
+  annotations:
+    config.kubernetes.io/function: |
+      container: {image: 'foo:bar'}
+data: {}
+kind: ConfigMap
+apiVersion: v1
+`,
+		},
+		{
+			name: "bad platform",
+			args: []string{"run", "dir", "--fn-platform", "arm64", "--image", "foo:bar"},
+			err:  "--fn-platform must have the form os/arch[/variant]",
+		},
 	}
 
 	for i := range tests {
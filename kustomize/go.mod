@@ -10,6 +10,7 @@ require (
 	k8s.io/client-go v0.17.3
 	sigs.k8s.io/kustomize/api v0.5.1
 	sigs.k8s.io/kustomize/cmd/config v0.5.0
+	sigs.k8s.io/kustomize/kyaml v0.6.1
 	sigs.k8s.io/yaml v1.2.0
 )
 
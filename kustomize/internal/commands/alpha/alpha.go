@@ -0,0 +1,28 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package alpha holds commands whose behavior
+// may still change from release to release.
+package alpha
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+// NewCmdAlpha returns an instance of 'alpha' subcommand.
+func NewCmdAlpha(out io.Writer, fSys filesys.FileSystem) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "alpha",
+		Short: "Commands that may change from release to release",
+		Example: `
+	# Migrates the plugins in the plugin home to KRM functions
+	kustomize alpha migrate-plugins ./overlays/prod
+`,
+		Args: cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(newCmdMigratePlugins(out, fSys))
+	return c
+}
@@ -0,0 +1,604 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package alpha
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const (
+	dockerfileName = "Dockerfile"
+	// wrapperName is the file holding the main function
+	// running a Go or exec plugin as a function.
+	wrapperName = "krmfunction.go"
+	// execWrapperName is the executable built from
+	// the wrapper of an exec plugin.
+	execWrapperName = "krmfunction"
+)
+
+const dockerfile = `FROM golang:1.14-alpine
+ENV CGO_ENABLED=0
+WORKDIR /go/src/
+COPY . .
+RUN go build -v -o /usr/local/bin/function ./
+
+FROM alpine:latest
+COPY --from=0 /usr/local/bin/function /usr/local/bin/function
+CMD ["function"]
+`
+
+const wrapper = `// Code generated by kustomize alpha migrate-plugins.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/api/k8sdeps/validator"
+	"sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/yaml"
+)
+
+// main runs KustomizePlugin as a KRM function, configured
+// by the functionConfig, on the items of a ResourceList.
+func main() {
+	rl := framework.ResourceList{}
+	cmd := framework.Command(&rl, func() error { return run(&rl) })
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(rl *framework.ResourceList) error {
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	ldr, err := loader.NewLoader(
+		loader.RestrictionRootOnly, filesys.SelfDir, filesys.MakeFsOnDisk())
+	if err != nil {
+		return err
+	}
+	config, err := yaml.Marshal(rl.FunctionConfig)
+	if err != nil {
+		return err
+	}
+	err = KustomizePlugin.Config(
+		resmap.NewPluginHelpers(ldr, validator.NewKustValidator(), rf), config)
+	if err != nil {
+		return err
+	}
+	var in bytes.Buffer
+	if err = (kio.ByteWriter{Writer: &in}).Write(rl.Items); err != nil {
+		return err
+	}
+	m, err := rf.NewResMapFromBytes(in.Bytes())
+	if err != nil {
+		return err
+	}
+	switch p := interface{}(&KustomizePlugin).(type) {
+	case resmap.Generator:
+		g, err := p.Generate()
+		if err != nil {
+			return err
+		}
+		if err = m.AppendAll(g); err != nil {
+			return err
+		}
+	case resmap.Transformer:
+		if err = p.Transform(m); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("plugin is neither a generator nor a transformer")
+	}
+	out, err := m.AsYaml()
+	if err != nil {
+		return err
+	}
+	rl.Items, err = (&kio.ByteReader{
+		Reader:                bytes.NewReader(out),
+		OmitReaderAnnotations: true,
+	}).Read()
+	return err
+}
+`
+
+// execWrapper is the wrapper of an exec plugin, given
+// the file name of the plugin.
+const execWrapper = `// Code generated by kustomize alpha migrate-plugins.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/shlex"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// plugin is the file name of the exec plugin, next to
+// the executable built from this file.
+const plugin = %q
+
+// main runs the exec plugin, speaking version 1 of the exec
+// plugin protocol, as a KRM function, configured by the
+// functionConfig.  Given items that are all local configs, as
+// kustomize gives generators, its output is added to them;
+// otherwise, it's given the items, and its output replaces them.
+func main() {
+	rl := framework.ResourceList{}
+	cmd := framework.Command(&rl, func() error { return run(&rl) })
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(rl *framework.ResourceList) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	fc, ok := rl.FunctionConfig.(*yaml.RNode)
+	if !ok {
+		return fmt.Errorf("missing functionConfig")
+	}
+	config, err := fc.String()
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile("", "kust-plugin-config-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.WriteString(config); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	args, err := pluginArgs(fc)
+	if err != nil {
+		return err
+	}
+	generator := true
+	for _, item := range rl.Items {
+		meta, err := item.GetMeta()
+		if err != nil {
+			return err
+		}
+		if meta.Annotations["config.kubernetes.io/local-config"] != "true" {
+			generator = false
+		}
+	}
+	var in bytes.Buffer
+	if !generator {
+		if err = (kio.ByteWriter{Writer: &in}).Write(rl.Items); err != nil {
+			return err
+		}
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(
+		filepath.Join(filepath.Dir(self), plugin), append([]string{f.Name()}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"KUSTOMIZE_PLUGIN_CONFIG_STRING="+config,
+		"KUSTOMIZE_PLUGIN_CONFIG_ROOT="+root)
+	cmd.Stdin = &in
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+	items, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(out),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return err
+	}
+	if generator {
+		items = append(rl.Items, items...)
+	}
+	rl.Items = items
+	return nil
+}
+
+// pluginArgs returns the arguments the plugin gets from the
+// argsOneLiner and argsFromFile fields of its config, the
+// file being relative to the current directory.
+func pluginArgs(config *yaml.RNode) ([]string, error) {
+	var args []string
+	if f := config.Field("argsOneLiner"); !f.IsNilOrEmpty() {
+		a, err := shlex.Split(yaml.GetValue(f.Value))
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a...)
+	}
+	if f := config.Field("argsFromFile"); !f.IsNilOrEmpty() {
+		b, err := ioutil.ReadFile(yaml.GetValue(f.Value))
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range strings.Split(string(b), "\n") {
+			if x = strings.TrimLeft(x, " "); x != "" {
+				args = append(args, x)
+			}
+		}
+	}
+	return args, nil
+}
+`
+
+type migrateOptions struct {
+	pluginHome  string
+	imagePrefix string
+	dryRun      bool
+}
+
+// legacyPlugin is a plugin found in the plugin home.
+type legacyPlugin struct {
+	// dir is the directory of the plugin.
+	dir        string
+	apiVersion string
+	kind       string
+	// The files found for the plugin.
+	exec   bool
+	krm    bool
+	rpc    bool
+	object bool
+	source bool
+	// function is the function annotation its
+	// configs get, if it's migrated.
+	function string
+}
+
+func (p *legacyPlugin) name() string {
+	return p.apiVersion + "/" + p.kind
+}
+
+func newCmdMigratePlugins(out io.Writer, fSys filesys.FileSystem) *cobra.Command {
+	var o migrateOptions
+	cmd := &cobra.Command{
+		Use:   "migrate-plugins [{dir}...]",
+		Short: "Migrates exec and Go plugins to KRM functions",
+		Long: `Inspects the plugins in the plugin home, and migrates them to KRM
+functions:
+
+  exec plugins speaking protocol version 2, named {kind}` + konfig.ExecPluginV2Suffix + `, read
+  and write ResourceLists, so they're run as exec functions, by their
+  absolute path, which builds must enable with --enable-exec.
+
+  exec plugins speaking version 1 get a ` + wrapperName + ` running them as
+  functions, to build into ` + execWrapperName + ` next to them, which is run as
+  the exec function.  The plugin gets its config and arguments as before,
+  but KUSTOMIZE_PLUGIN_CONFIG_ROOT is then the current directory of the
+  build, which argsFromFile is relative to, not the kustomization root.
+
+  Go plugins get a ` + wrapperName + ` running them as functions, and a
+  ` + dockerfileName + ` to build them into the image IMAGE_PREFIX/{lowercase kind}:{version}.
+  The images must be built and pushed before the kustomizations are built.
+
+  Go plugins without source, and plugins served over RPC, aren't migrated.
+
+The generators, transformers and validators listed in the kustomizations
+in the given directories are then annotated to run as the functions.
+`,
+		Example: `
+	# Migrates the plugins, and the kustomizations using them
+	kustomize alpha migrate-plugins ./base ./overlays/prod --image-prefix example.com/plugins
+
+	# Tells what would be done
+	kustomize alpha migrate-plugins ./base --dry-run
+`,
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if o.pluginHome == "" {
+				home, err := konfig.DefaultAbsPluginHome(fSys)
+				if err != nil {
+					return err
+				}
+				o.pluginHome = home
+			}
+			home, err := filepath.Abs(o.pluginHome)
+			if err != nil {
+				return err
+			}
+			o.pluginHome = home
+			return o.run(out, fSys, args)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.pluginHome, "plugin-home", "",
+		"The directory holding the plugins.  Defaults to the plugin home kustomize uses.")
+	cmd.Flags().StringVar(
+		&o.imagePrefix, "image-prefix", "",
+		"The registry and path prefixed to the names of the images of Go plugins.")
+	cmd.Flags().BoolVar(
+		&o.dryRun, "dry-run", false,
+		"Tell what would be done, without writing anything.")
+	return cmd
+}
+
+func (o *migrateOptions) run(
+	out io.Writer, fSys filesys.FileSystem, dirs []string) error {
+	plugins, err := findLegacyPlugins(fSys, o.pluginHome)
+	if err != nil {
+		return err
+	}
+	byType := make(map[string]*legacyPlugin)
+	for _, p := range plugins {
+		if err = o.migrate(out, fSys, p); err != nil {
+			return err
+		}
+		if p.function != "" {
+			byType[p.name()] = p
+		}
+	}
+	for _, dir := range dirs {
+		if err = o.rewriteKustomization(out, fSys, dir, byType); err != nil {
+			return err
+		}
+	}
+	if o.dryRun {
+		fmt.Fprintln(out, "dry run; nothing written")
+	}
+	return nil
+}
+
+// findLegacyPlugins returns the plugins in the plugin home,
+// i.e. the files named like their directory, apart from case
+// and a ".so", ".krm", ".rpc" or ".go" suffix, at
+// {home}/{group}/{version}/{lowercase kind}/{kind}.
+func findLegacyPlugins(
+	fSys filesys.FileSystem, home string) ([]*legacyPlugin, error) {
+	var result []*legacyPlugin
+	byDir := make(map[string]*legacyPlugin)
+	err := fSys.Walk(home, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(info.Name())
+		switch ext {
+		case ".so", konfig.ExecPluginV2Suffix, konfig.RpcPluginSuffix, ".go":
+		default:
+			ext = ""
+		}
+		kind := strings.TrimSuffix(info.Name(), ext)
+		dir := filepath.Dir(path)
+		if strings.ToLower(kind) != filepath.Base(dir) {
+			return nil
+		}
+		rel, err := filepath.Rel(home, filepath.Dir(dir))
+		if err != nil {
+			return err
+		}
+		// Either {version} or {group}/{version}.
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if rel == "." || len(parts) > 2 {
+			return nil
+		}
+		p, ok := byDir[dir]
+		if !ok {
+			p = &legacyPlugin{
+				dir:        dir,
+				apiVersion: strings.Join(parts, "/"),
+				kind:       kind,
+			}
+			byDir[dir] = p
+			result = append(result, p)
+		}
+		switch ext {
+		case ".so":
+			p.object = true
+		case konfig.ExecPluginV2Suffix:
+			p.krm = info.Mode()&0111 != 0
+		case konfig.RpcPluginSuffix:
+			p.rpc = true
+		case ".go":
+			p.source = true
+		default:
+			p.exec = info.Mode()&0111 != 0
+		}
+		return nil
+	})
+	return result, err
+}
+
+// migrate sets the function the plugin is run as, if it
+// can be, writing the files needed to build its image.
+// The plugin is taken to be what kustomize would load,
+// trying an executable speaking version 1 of the exec
+// plugin protocol, then version 2, then RPC, then Go.
+func (o *migrateOptions) migrate(
+	out io.Writer, fSys filesys.FileSystem, p *legacyPlugin) error {
+	switch {
+	case p.exec:
+		f := filepath.Join(p.dir, wrapperName)
+		if err := o.writeScaffold(
+			out, fSys, p, f, fmt.Sprintf(execWrapper, p.kind)); err != nil {
+			return err
+		}
+		bin := filepath.Join(p.dir, execWrapperName)
+		p.function = "exec:\n  path: " + filepath.ToSlash(bin) + "\n"
+		fmt.Fprintf(out,
+			"%s: runs as the exec function %s; build it with: go build -o %s %s\n",
+			p.name(), bin, bin, f)
+	case p.krm:
+		// Exec functions run in the current directory
+		// of the build, wherever it is.
+		path := filepath.Join(p.dir, p.kind+konfig.ExecPluginV2Suffix)
+		p.function = "exec:\n  path: " + filepath.ToSlash(path) + "\n"
+		fmt.Fprintf(out, "%s: runs as an exec function\n", p.name())
+	case p.rpc:
+		fmt.Fprintf(out,
+			"%s: skipped; plugins served over RPC can't run as functions\n",
+			p.name())
+	case p.source:
+		image := path.Join(o.imagePrefix, strings.ToLower(p.kind)) + ":" +
+			path.Base(p.apiVersion)
+		for name, content := range map[string]string{
+			dockerfileName: dockerfile,
+			wrapperName:    wrapper,
+		} {
+			err := o.writeScaffold(out, fSys, p, filepath.Join(p.dir, name), content)
+			if err != nil {
+				return err
+			}
+		}
+		p.function = "container:\n  image: " + image + "\n"
+		fmt.Fprintf(out,
+			"%s: runs as the function image %s; build it with: docker build -t %s %s\n",
+			p.name(), image, image, p.dir)
+	case p.object:
+		fmt.Fprintf(out,
+			"%s: skipped; Go plugins without source can't be built into functions\n",
+			p.name())
+	default:
+		fmt.Fprintf(out, "%s: skipped; the plugin isn't executable\n", p.name())
+	}
+	return nil
+}
+
+// writeScaffold writes the file f the plugin needs to run
+// as a function, unless it exists.
+func (o *migrateOptions) writeScaffold(
+	out io.Writer, fSys filesys.FileSystem, p *legacyPlugin, f, content string) error {
+	if fSys.Exists(f) {
+		fmt.Fprintf(out, "%s: kept existing %s\n", p.name(), f)
+		return nil
+	}
+	if o.dryRun {
+		return nil
+	}
+	return fSys.WriteFile(f, []byte(content))
+}
+
+// rewriteKustomization annotates the configs of the migrated
+// plugins listed in the kustomization in dir to run them as
+// functions.  Configs that aren't local files are left alone.
+func (o *migrateOptions) rewriteKustomization(
+	out io.Writer, fSys filesys.FileSystem, dir string,
+	byType map[string]*legacyPlugin) error {
+	var content []byte
+	var err error
+	for _, n := range konfig.RecognizedKustomizationFileNames() {
+		if f := filepath.Join(dir, n); fSys.Exists(f) {
+			content, err = fSys.ReadFile(f)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if content == nil {
+		return fmt.Errorf("no kustomization file in %s", dir)
+	}
+	var k types.Kustomization
+	if err = k8syaml.Unmarshal(content, &k); err != nil {
+		return errors.Wrapf(err, "reading kustomization in %s", dir)
+	}
+	var configs []string
+	configs = append(configs, k.Generators...)
+	configs = append(configs, k.Transformers...)
+	configs = append(configs, k.Validators...)
+	for _, c := range configs {
+		f := filepath.Join(dir, c)
+		if !fSys.Exists(f) || fSys.IsDir(f) {
+			continue
+		}
+		if err = o.rewriteConfig(out, fSys, f, byType); err != nil {
+			return errors.Wrapf(err, "rewriting %s", f)
+		}
+	}
+	return nil
+}
+
+// rewriteConfig annotates the configs of migrated
+// plugins in the file f.
+func (o *migrateOptions) rewriteConfig(
+	out io.Writer, fSys filesys.FileSystem, f string,
+	byType map[string]*legacyPlugin) error {
+	content, err := fSys.ReadFile(f)
+	if err != nil {
+		return err
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(content),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, node := range nodes {
+		meta, err := node.GetMeta()
+		if err != nil {
+			return err
+		}
+		p, ok := byType[meta.APIVersion+"/"+meta.Kind]
+		if !ok || runtimeutil.GetFunctionSpec(node) != nil {
+			continue
+		}
+		// Written as a literal, as function specs are by hand.
+		v := yaml.NewScalarRNode(p.function)
+		v.YNode().Style = yaml.LiteralStyle
+		err = node.PipeE(
+			yaml.LookupCreate(yaml.MappingNode, yaml.MetadataField, yaml.AnnotationsField),
+			yaml.SetField(runtimeutil.FunctionAnnotationKey, v))
+		if err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	fmt.Fprintf(out, "%s: annotated to run functions\n", f)
+	if o.dryRun {
+		return nil
+	}
+	var b bytes.Buffer
+	if err = (kio.ByteWriter{Writer: &b}).Write(nodes); err != nil {
+		return err
+	}
+	return fSys.WriteFile(f, b.Bytes())
+}
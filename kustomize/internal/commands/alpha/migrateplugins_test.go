@@ -0,0 +1,280 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package alpha
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// krmGen is an exec plugin speaking version 2 of the
+// protocol, generating a ConfigMap.
+const krmGen = `#!/bin/sh
+cat <<EOF
+apiVersion: config.kubernetes.io/v1alpha1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: krm
+EOF
+`
+
+func TestMigratePlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-migrate-plugins-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	home := filepath.Join(dir, "plugins")
+	writeFile(t, filepath.Join(home, "example.com/v1/execgen/ExecGen"), "#!/bin/sh\n", 0755)
+	writeFile(t, filepath.Join(home, "example.com/v1/krmgen/KrmGen.krm"), krmGen, 0755)
+	writeFile(t, filepath.Join(home, "example.com/v1/gogen/GoGen.go"), "package main\n", 0644)
+	writeFile(t, filepath.Join(home, "example.com/v1/gogen/GoGen_test.go"), "package main\n", 0644)
+	writeFile(t, filepath.Join(home, "v1/rpcgen/RpcGen.rpc"), "#!/bin/sh\n", 0755)
+	writeFile(t, filepath.Join(home, "v1/objectgen/ObjectGen.so"), "", 0644)
+	writeFile(t, filepath.Join(home, "v1/plaingen/PlainGen"), "", 0644)
+
+	app := filepath.Join(dir, "app")
+	writeFile(t, filepath.Join(app, "kustomization.yaml"), `
+generators:
+- gen.yaml
+transformers:
+- https://example.com/transformer.yaml
+`, 0644)
+	writeFile(t, filepath.Join(app, "gen.yaml"), `# Generators of the app.
+apiVersion: example.com/v1
+kind: ExecGen
+metadata:
+  name: exec
+---
+apiVersion: example.com/v1
+kind: KrmGen
+metadata:
+  name: krm
+---
+apiVersion: example.com/v1
+kind: GoGen
+metadata:
+  name: go
+---
+apiVersion: v1
+kind: RpcGen
+metadata:
+  name: rpc
+---
+apiVersion: example.com/v1
+kind: GoGen
+metadata:
+  name: migrated
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/gogen:v2
+`, 0644)
+
+	var out bytes.Buffer
+	cmd := newCmdMigratePlugins(&out, filesys.MakeFsOnDisk())
+	cmd.SetArgs([]string{
+		app, "--plugin-home", home, "--image-prefix", "example.com/plugins"})
+	if err = cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	goDir := filepath.Join(home, "example.com/v1/gogen")
+	execDir := filepath.Join(home, "example.com/v1/execgen")
+	execBin := filepath.Join(execDir, execWrapperName)
+	expected := `example.com/v1/ExecGen: runs as the exec function ` + execBin +
+		`; build it with: go build -o ` + execBin + ` ` + filepath.Join(execDir, wrapperName) + `
+example.com/v1/GoGen: runs as the function image example.com/plugins/gogen:v1; build it with: docker build -t example.com/plugins/gogen:v1 ` + goDir + `
+example.com/v1/KrmGen: runs as an exec function
+v1/ObjectGen: skipped; Go plugins without source can't be built into functions
+v1/PlainGen: skipped; the plugin isn't executable
+v1/RpcGen: skipped; plugins served over RPC can't run as functions
+` + filepath.Join(app, "gen.yaml") + `: annotated to run functions
+`
+	if out.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	if s := readFile(t, filepath.Join(goDir, dockerfileName)); s != dockerfile {
+		t.Fatalf("unexpected %s:\n%s", dockerfileName, s)
+	}
+	if s := readFile(t, filepath.Join(goDir, wrapperName)); s != wrapper {
+		t.Fatalf("unexpected %s:\n%s", wrapperName, s)
+	}
+	s := readFile(t, filepath.Join(execDir, wrapperName))
+	if !strings.Contains(s, `const plugin = "ExecGen"`) {
+		t.Fatalf("unexpected %s:\n%s", wrapperName, s)
+	}
+	expected = `# Generators of the app.
+apiVersion: example.com/v1
+kind: ExecGen
+metadata:
+  name: exec
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: ` + filepath.ToSlash(execBin) + `
+---
+apiVersion: example.com/v1
+kind: KrmGen
+metadata:
+  name: krm
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: ` + filepath.ToSlash(filepath.Join(home, "example.com/v1/krmgen/KrmGen.krm")) + `
+---
+apiVersion: example.com/v1
+kind: GoGen
+metadata:
+  name: go
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/plugins/gogen:v1
+---
+apiVersion: v1
+kind: RpcGen
+metadata:
+  name: rpc
+---
+apiVersion: example.com/v1
+kind: GoGen
+metadata:
+  name: migrated
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/gogen:v2
+`
+	if s := readFile(t, filepath.Join(app, "gen.yaml")); s != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+}
+
+// The migrated kustomization builds, wherever it's built from.
+func TestMigratePluginsBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-migrate-plugins-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	home := filepath.Join(dir, "plugins")
+	writeFile(t, filepath.Join(home, "v1/krmgen/KrmGen.krm"), krmGen, 0755)
+	app := filepath.Join(dir, "app")
+	writeFile(t, filepath.Join(app, "kustomization.yaml"), `
+generators:
+- gen.yaml
+`, 0644)
+	writeFile(t, filepath.Join(app, "gen.yaml"), `apiVersion: v1
+kind: KrmGen
+metadata:
+  name: krm
+`, 0644)
+
+	fSys := filesys.MakeFsOnDisk()
+	var out bytes.Buffer
+	cmd := newCmdMigratePlugins(&out, fSys)
+	cmd.SetArgs([]string{app, "--plugin-home", home})
+	if err = cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := krusty.MakeDefaultOptions()
+	o.PluginConfig = konfig.MakePluginConfig(
+		types.PluginRestrictionsNone, types.BploUseStaticallyLinked,
+		konfig.NoPluginHomeSentinal)
+	o.PluginConfig.FnpLoadingOptions.EnableExec = true
+	m, err := krusty.MakeKustomizer(fSys, o).Run(app)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	yml, err := m.AsYaml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    config.kubernetes.io/path: configmap_krm.yaml
+  name: krm
+`
+	if string(yml) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, yml)
+	}
+}
+
+func TestMigratePluginsDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-migrate-plugins-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	home := filepath.Join(dir, "plugins")
+	writeFile(t, filepath.Join(home, "v1/gogen/GoGen.go"), "package main\n", 0644)
+	writeFile(t, filepath.Join(home, "v1/gogen/"+dockerfileName), "FROM scratch\n", 0644)
+	app := filepath.Join(dir, "app")
+	writeFile(t, filepath.Join(app, "kustomization.yaml"), `
+generators:
+- gen.yaml
+`, 0644)
+	config := `apiVersion: v1
+kind: GoGen
+metadata:
+  name: go
+`
+	writeFile(t, filepath.Join(app, "gen.yaml"), config, 0644)
+
+	var out bytes.Buffer
+	cmd := newCmdMigratePlugins(&out, filesys.MakeFsOnDisk())
+	cmd.SetArgs([]string{app, "--plugin-home", home, "--dry-run"})
+	if err = cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		"v1/GoGen: kept existing " + filepath.Join(home, "v1/gogen", dockerfileName),
+		"v1/GoGen: runs as the function image gogen:v1",
+		"gen.yaml: annotated to run functions",
+		"dry run; nothing written",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Fatalf("expected %q in:\n%s", s, out.String())
+		}
+	}
+	if _, err = os.Stat(filepath.Join(home, "v1/gogen", wrapperName)); !os.IsNotExist(err) {
+		t.Fatalf("expected no %s, got %v", wrapperName, err)
+	}
+	if s := readFile(t, filepath.Join(app, "gen.yaml")); s != config {
+		t.Fatalf("expected unchanged config, got:\n%s", s)
+	}
+}
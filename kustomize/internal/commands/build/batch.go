@@ -0,0 +1,187 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/profile"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+const flagAllOverlaysName = "all-overlays"
+
+var flagAllOverlaysValue = ""
+
+func addFlagAllOverlays(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagAllOverlaysValue, flagAllOverlaysName, "",
+		"Build every kustomization found under this directory, "+
+			"other than components, each to its own file in --output.")
+}
+
+// validateBatch validates building several targets
+// in one process, given as args or by --all-overlays.
+func (o *Options) validateBatch(args []string) error {
+	if flagAllOverlaysValue != "" && len(args) > 0 {
+		return fmt.Errorf(
+			"flag --%s may not be used with paths to build", flagAllOverlaysName)
+	}
+	for _, arg := range args {
+		if arg == stdinArg {
+			return fmt.Errorf("'%s' may not be built with other paths", stdinArg)
+		}
+	}
+	if o.outputPath == "" {
+		return fmt.Errorf(
+			"building several paths requires --output naming a directory")
+	}
+	if flagBaseDirValue != "" {
+		return fmt.Errorf(
+			"flag --%s may only be used when building '%s'", flagBaseDirName, stdinArg)
+	}
+	if len(flagSetValue) > 0 || flagApplySetValue != "" {
+		return fmt.Errorf(
+			"flags --%s and --%s may only be used when building one path",
+			flagSetName, flagApplySetName)
+	}
+	o.targets = args
+	o.allOverlaysDir = flagAllOverlaysValue
+	return nil
+}
+
+// isBatch returns whether several targets are built.
+func (o *Options) isBatch() bool {
+	return len(o.targets) > 0 || o.allOverlaysDir != ""
+}
+
+// runBatch builds each target to its own file in the output
+// directory.  The targets share one Kustomizer, and the
+// clones of the remote bases they use.
+func (o *Options) runBatch(fSys filesys.FileSystem) error {
+	targets := o.targets
+	if o.allOverlaysDir != "" {
+		var err error
+		targets, err = findKustomizations(fSys, o.allOverlaysDir)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no kustomizations found under %s", o.allOverlaysDir)
+		}
+	}
+	if !fSys.IsDir(o.outputPath) {
+		if err := fSys.MkdirAll(o.outputPath); err != nil {
+			return err
+		}
+	}
+	names := make(map[string]string)
+	for _, t := range targets {
+		name := o.batchFileName(t)
+		if other, ok := names[name]; ok {
+			return fmt.Errorf(
+				"paths %s and %s would both be written to %s", other, t, name)
+		}
+		names[name] = t
+	}
+	opts := o.makeOptions()
+	opts.RepoCache = loader.NewRepoCache(fSys)
+	defer opts.RepoCache.Cleanup()
+	defer writeFindings(os.Stderr, o.validation)
+	k := krusty.MakeKustomizer(fSys, opts)
+	for _, t := range targets {
+		m, err := k.Run(t)
+		if err != nil {
+			return errors.Wrapf(err, "building %s", t)
+		}
+		stop := o.profile.Start(profile.PhaseMarshal, "", "")
+		res, err := o.makeDocumentFormat(t).asYaml(m)
+		if err == nil {
+			err = fSys.WriteFile(
+				filepath.Join(o.outputPath, o.batchFileName(t)), res)
+		}
+		stop()
+		if err != nil {
+			return err
+		}
+	}
+	if err := writeTrace(os.Stderr, o.trace); err != nil {
+		return err
+	}
+	return writeProfile(os.Stderr, o.profile)
+}
+
+// findKustomizations returns the directories under dir
+// holding a kustomization that isn't a component.
+func findKustomizations(fSys filesys.FileSystem, dir string) ([]string, error) {
+	if !fSys.IsDir(dir) {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	var result []string
+	err := fSys.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		for _, n := range konfig.RecognizedKustomizationFileNames() {
+			content, err := fSys.ReadFile(filepath.Join(path, n))
+			if err != nil {
+				continue
+			}
+			var k struct {
+				Kind string `json:"kind"`
+			}
+			if err = yaml.Unmarshal(content, &k); err != nil {
+				return errors.Wrapf(err, "reading %s", filepath.Join(path, n))
+			}
+			if k.Kind != types.ComponentKind {
+				result = append(result, path)
+			}
+			break
+		}
+		return nil
+	})
+	sort.Strings(result)
+	return result, err
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// batchFileName returns the name of the file in the
+// output directory that the target is written to.
+func (o *Options) batchFileName(target string) string {
+	name := target
+	if o.allOverlaysDir != "" {
+		if rel, err := filepath.Rel(o.allOverlaysDir, target); err == nil {
+			name = rel
+		}
+	}
+	name = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(name)), "/")
+	for strings.HasPrefix(name, "../") {
+		name = strings.TrimPrefix(name, "../")
+	}
+	if name == "." || name == ".." {
+		if abs, err := filepath.Abs(target); err == nil {
+			name = filepath.Base(abs)
+		}
+	}
+	return unsafeFileNameChars.ReplaceAllString(name, "_") + ".yaml"
+}
@@ -4,20 +4,24 @@
 package build
 
 import (
+	"bytes"
 	"io"
 	"log"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/applyset"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/profile"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/trace"
 	"sigs.k8s.io/kustomize/api/types"
-	"sigs.k8s.io/yaml"
 )
 
 // Options contain the options for running a build
@@ -26,6 +30,27 @@ type Options struct {
 	outputPath        string
 	outOrder          reorderOutput
 	fnOptions         types.FnPluginLoadingOptions
+	profile           *profile.Profile
+	trace             *trace.Trace
+	validation        *types.ValidationReport
+	// fromStdin is true if the kustomization
+	// file is to be read from in.
+	fromStdin bool
+	in        io.Reader
+	// setters maps the names of setters to the values
+	// overriding those declared by the target.
+	setters map[string]string
+	// applySet is the parent of the ApplySet the
+	// resources are made members of, if any.
+	applySet *applyset.Parent
+	// targets are the paths built when building
+	// several, each to its own file in outputPath.
+	targets []string
+	// allOverlaysDir, if set, is searched for
+	// the kustomizations to build.
+	allOverlaysDir string
+	// pluginLimits bound the processes running plugins.
+	pluginLimits types.PluginLimits
 }
 
 // NewOptions creates a Options object
@@ -51,13 +76,58 @@ with a kustomization.yaml file, e.g.
 
 The URL should be formulated as described at
 https://github.com/hashicorp/go-getter#url-format
+
+If the argument is '-', the kustomization file is read from stdin,
+and treated as if it were in the directory given by --base-dir, e.g.
+
+  generate-overlay | kustomize build - --base-dir someDir
+
+The values of setters declared in the Krmfile of the target may be
+overridden for one build, without changing any file, e.g.
+
+  kustomize build someDir --set replicas=5 --set image-tag=1.8.2
+
+With --applyset, the output is ready for pruning with kubectl, e.g.
+
+  kustomize build someDir --applyset=my-app |
+    kubectl apply --prune --applyset=my-app -f -
+
+Several paths may be built in one run, each to its own file in
+the --output directory, sharing the clones of remote bases, e.g.
+
+  kustomize build overlays/dev overlays/prod -o out
+  kustomize build --all-overlays overlays -o out
+
+Repeated builds may skip running container functions whose output
+is cached, e.g. in CI, with
+
+  kustomize build someDir --enable_alpha_plugins --fn-cache=on
+
+To build an untrusted kustomization, --sandbox clones remote bases
+in a container and refuses plugins that would run on the host, e.g.
+
+  kustomize build github.com/someone/config/app --sandbox
+
+Validators report findings as errors, warnings or info; by default
+only errors fail the build.  To fail on warnings too, run
+
+  kustomize build someDir --fail-on=warning
+
+On failure, the exit code identifies the class of problem:
+
+  1  other errors (e.g. bad flags)
+  2  a kustomization or resource file couldn't be parsed
+  3  a file or directory doesn't exist
+  4  a remote base couldn't be fetched
+  5  a plugin couldn't be loaded, or failed
+  6  a validator rejected the resources
 `
 
 // NewCmdBuild creates a new build command.
 func NewCmdBuild(out io.Writer) *cobra.Command {
 	var o Options
 	cmd := &cobra.Command{
-		Use: "build {path}",
+		Use: "build {path}...",
 		Short: "Print configuration per contents of " +
 			konfig.DefaultKustomizationFileName(),
 		Example:      examples,
@@ -67,6 +137,7 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			o.in = cmd.InOrStdin()
 			return o.RunBuild(out)
 		},
 	}
@@ -95,27 +166,105 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 	addFlagEnablePlugins(cmd.Flags())
 	addFlagReorderOutput(cmd.Flags())
 	addFlagEnableManagedbyLabel(cmd.Flags())
+	addFlagEnableGoTemplate(cmd.Flags())
 	addFlagEnableKyaml(cmd.Flags())
+	addFlagStrictPatch(cmd.Flags())
+	addFlagDocumentFormat(cmd.Flags())
+	addFlagProfile(cmd.Flags())
+	addFlagTrace(cmd.Flags())
+	addFlagBaseDir(cmd.Flags())
+	addFlagSet(cmd.Flags())
+	addFlagApplySet(cmd.Flags())
+	addFlagAllOverlays(cmd.Flags())
+	addFlagSandbox(cmd.Flags())
+	addFlagFnCache(cmd.Flags())
+	addFlagFnPlatform(cmd.Flags())
+	addFlagIncremental(cmd.Flags())
+	addFlagExecSandbox(cmd.Flags())
+	addFlagCatalog(cmd.Flags())
+	addFlagPluginLimits(cmd.Flags())
+	addFlagFailOn(cmd.Flags())
 
 	return cmd
 }
 
 // Validate validates build command.
 func (o *Options) Validate(args []string) (err error) {
-	if len(args) > 1 {
-		return errors.New(
-			"specify one path to " +
-				konfig.DefaultKustomizationFileName())
-	}
-	if len(args) == 0 {
+	if len(args) > 1 || flagAllOverlaysValue != "" {
+		err = o.validateBatch(args)
+		if err != nil {
+			return err
+		}
+		o.kustomizationPath = filesys.SelfDir
+	} else if len(args) == 0 {
 		o.kustomizationPath = filesys.SelfDir
 	} else {
 		o.kustomizationPath = args[0]
 	}
+	err = validateFlagBaseDir(o.kustomizationPath)
+	if err != nil {
+		return err
+	}
+	if o.kustomizationPath == stdinArg {
+		o.fromStdin = true
+		o.kustomizationPath = baseDir()
+	}
 	err = validateFlagLoadRestrictor()
 	if err != nil {
 		return err
 	}
+	err = validateFlagDocumentFormat()
+	if err != nil {
+		return err
+	}
+	err = validateFlagProfile()
+	if err != nil {
+		return err
+	}
+	o.setters, err = validateFlagSet()
+	if err != nil {
+		return err
+	}
+	o.applySet, err = validateFlagApplySet()
+	if err != nil {
+		return err
+	}
+	err = validateFlagSandbox(o.fnOptions)
+	if err != nil {
+		return err
+	}
+	err = validateFlagFnCache(&o.fnOptions)
+	if err != nil {
+		return err
+	}
+	err = validateFlagFnPlatform(&o.fnOptions)
+	if err != nil {
+		return err
+	}
+	err = validateFlagIncremental()
+	if err != nil {
+		return err
+	}
+	err = validateFlagExecSandbox(&o.fnOptions)
+	if err != nil {
+		return err
+	}
+	err = validateFlagCatalog()
+	if err != nil {
+		return err
+	}
+	o.pluginLimits, err = validateFlagPluginLimits()
+	if err != nil {
+		return err
+	}
+	err = validateFlagFailOn()
+	if err != nil {
+		return err
+	}
+	err = validateFlagEnableKyaml()
+	if err != nil {
+		return err
+	}
 	o.outOrder, err = validateFlagReorderOutput()
 	return
 }
@@ -132,27 +281,77 @@ func (o *Options) makeOptions() *krusty.Options {
 		c.FnpLoadingOptions = o.fnOptions
 		opts.PluginConfig = c
 	}
+	opts.PluginConfig.EnableGoTemplate = flagEnableGoTemplateValue
+	opts.PluginConfig.Limits = o.pluginLimits
 	opts.AddManagedbyLabel = isManagedbyLabelEnabled()
 	opts.UseKyaml = flagEnableKyamlValue
+	opts.DetectLossyConversions = flagDetectLossyConversionsValue
+	opts.StrictPatch = flagStrictPatchValue
+	o.profile = makeProfile()
+	opts.Profile = o.profile
+	o.trace = makeTrace()
+	opts.Trace = o.trace
+	o.validation = &types.ValidationReport{}
+	opts.ValidationReport = o.validation
+	opts.FailOn = types.Severity(flagFailOnValue)
+	opts.ApplySet = o.applySet
+	opts.PruneLabels = flagPruneLabelValue
+	opts.Sandbox = flagSandboxValue
+	opts.SandboxImage = flagSandboxImageValue
+	opts.RequireCatalog = flagRequireCatalogValue
+	opts.Catalogs = catalogSources()
+	opts.BuildCache = makeBuildCache()
 	return opts
 }
 
 func (o *Options) RunBuild(out io.Writer) error {
-	fSys := filesys.MakeFsOnDisk()
+	return o.runBuild(out, filesys.MakeFsOnDisk())
+}
+
+func (o *Options) runBuild(out io.Writer, fSys filesys.FileSystem) error {
+	if o.isBatch() {
+		return o.runBatch(fSys)
+	}
+	var err error
+	if o.fromStdin {
+		fSys, err = newFsWithKustomization(fSys, o.kustomizationPath, o.in)
+		if err != nil {
+			return err
+		}
+	}
+	if len(o.setters) > 0 {
+		var restore func()
+		fSys, restore, err = newFsWithSetters(fSys, o.kustomizationPath, o.setters)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
 	k := krusty.MakeKustomizer(fSys, o.makeOptions())
 	m, err := k.Run(o.kustomizationPath)
+	writeFindings(os.Stderr, o.validation)
 	if err != nil {
 		return err
 	}
-	return o.emitResources(out, fSys, m)
+	stop := o.profile.Start(profile.PhaseMarshal, "", "")
+	err = o.emitResources(out, fSys, m)
+	stop()
+	if err != nil {
+		return err
+	}
+	if err = writeTrace(os.Stderr, o.trace); err != nil {
+		return err
+	}
+	return writeProfile(os.Stderr, o.profile)
 }
 
 func (o *Options) emitResources(
 	out io.Writer, fSys filesys.FileSystem, m resmap.ResMap) error {
+	f := o.makeDocumentFormat(o.kustomizationPath)
 	if o.outputPath != "" && fSys.IsDir(o.outputPath) {
-		return writeIndividualFiles(fSys, o.outputPath, m)
+		return writeIndividualFiles(fSys, o.outputPath, m, f)
 	}
-	res, err := m.AsYaml()
+	res, err := f.asYaml(m)
 	if err != nil {
 		return err
 	}
@@ -163,23 +362,109 @@ func (o *Options) emitResources(
 	return err
 }
 
+// documentFormat controls how resources are
+// rendered into YAML documents.
+type documentFormat struct {
+	separator       string
+	headers         []string
+	trailingNewline bool
+	// crlf is true if lines end with CRLF, rather than LF.
+	crlf bool
+	// portableNames is true if the names of the files
+	// written are the same on all platforms.
+	portableNames bool
+	// root is the directory that 'source'
+	// headers are made relative to.
+	root string
+}
+
+// makeDocumentFormat returns the format of
+// the resources built from the given path.
+func (o *Options) makeDocumentFormat(path string) documentFormat {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		root = path
+	}
+	return documentFormat{
+		separator:       flagSeparatorValue,
+		headers:         flagHeaderValue,
+		trailingNewline: flagTrailingNewlineValue,
+		crlf:            crlfLineEndings(),
+		portableNames:   flagPortableNamesValue,
+		root:            root,
+	}
+}
+
+// header returns the comment lines to write above a resource.
+func (f documentFormat) header(res *resource.Resource) string {
+	var b strings.Builder
+	for _, h := range f.headers {
+		switch h {
+		case headerSource:
+			if origin := res.GetOrigin(); origin != "" {
+				b.WriteString("# Source: " + f.relativize(origin) + "\n")
+			}
+		case headerId:
+			b.WriteString("# Id: " + res.CurId().String() + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (f documentFormat) relativize(path string) string {
+	rel, err := filepath.Rel(f.root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (f documentFormat) asYaml(m resmap.ResMap) ([]byte, error) {
+	var b bytes.Buffer
+	for i, res := range m.Resources() {
+		out, err := res.AsYAML()
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteString(f.separator + "\n")
+		}
+		b.WriteString(f.header(res))
+		b.Write(out)
+	}
+	return f.finish(b.Bytes()), nil
+}
+
+func (f documentFormat) finish(out []byte) []byte {
+	if !f.trailingNewline {
+		out = bytes.TrimSuffix(out, []byte("\n"))
+	}
+	if f.crlf {
+		out = bytes.ReplaceAll(
+			bytes.ReplaceAll(out, []byte("\r\n"), []byte("\n")),
+			[]byte("\n"), []byte("\r\n"))
+	}
+	return out
+}
+
 func writeIndividualFiles(
-	fSys filesys.FileSystem, folderPath string, m resmap.ResMap) error {
+	fSys filesys.FileSystem, folderPath string,
+	m resmap.ResMap, f documentFormat) error {
 	byNamespace := m.GroupedByCurrentNamespace()
 	for namespace, resList := range byNamespace {
 		for _, res := range resList {
-			fName := fileName(res)
+			fName := f.fileName(res)
 			if len(byNamespace) > 1 {
 				fName = strings.ToLower(namespace) + "_" + fName
 			}
-			err := writeFile(fSys, folderPath, fName, res)
+			err := writeFile(fSys, folderPath, fName, res, f)
 			if err != nil {
 				return err
 			}
 		}
 	}
 	for _, res := range m.NonNamespaceable() {
-		err := writeFile(fSys, folderPath, fileName(res), res)
+		err := writeFile(fSys, folderPath, f.fileName(res), res, f)
 		if err != nil {
 			return err
 		}
@@ -187,16 +472,29 @@ func writeIndividualFiles(
 	return nil
 }
 
-func fileName(res *resource.Resource) string {
-	return strings.ToLower(res.GetGvk().String()) +
-		"_" + strings.ToLower(res.GetName()) + ".yaml"
+// unportableFileNameChars can't be used in file names
+// on some platform, e.g. ':' on Windows, or separate
+// directories, as '/' does everywhere.
+var unportableFileNameChars = regexp.MustCompile(`[\\/:*?"<>|]`)
+
+// fileName returns the name of the file the resource is
+// written to, the same on all platforms if portableNames.
+func (f documentFormat) fileName(res *resource.Resource) string {
+	name := strings.ToLower(res.GetGvk().String()) +
+		"_" + strings.ToLower(res.GetName())
+	if f.portableNames {
+		name = unportableFileNameChars.ReplaceAllString(name, "_")
+	}
+	return name + ".yaml"
 }
 
 func writeFile(
-	fSys filesys.FileSystem, path, fName string, res *resource.Resource) error {
-	out, err := yaml.Marshal(res.Map())
+	fSys filesys.FileSystem, path, fName string,
+	res *resource.Resource, f documentFormat) error {
+	out, err := res.AsYAML()
 	if err != nil {
 		return err
 	}
+	out = f.finish(append([]byte(f.header(res)), out...))
 	return fSys.WriteFile(filepath.Join(path, fName), out)
 }
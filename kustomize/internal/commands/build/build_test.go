@@ -4,10 +4,19 @@
 package build
 
 import (
+	"bytes"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"sigs.k8s.io/kustomize/api/applyset"
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
 )
 
 func TestNewOptionsToSilenceCodeInspectionError(t *testing.T) {
@@ -28,8 +37,11 @@ func TestBuildValidate(t *testing.T) {
 		{"path", []string{"a/b/c"}, "a/b/c", ""},
 		{"path", []string{"too", "many"},
 			"",
-			"specify one path to " +
-				konfig.DefaultKustomizationFileName()},
+			"building several paths requires --output naming a directory"},
+		{"stdinInBatch", []string{"a", "-"},
+			"",
+			"'-' may not be built with other paths"},
+		{"stdin", []string{"-"}, filesys.SelfDir, ""},
 	}
 	for _, mycase := range cases {
 		opts := Options{}
@@ -52,3 +64,772 @@ func TestBuildValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestNewCmdBuildDocumentFormatFlags(t *testing.T) {
+	defer func() {
+		flagSeparatorValue = "---"
+		flagHeaderValue = []string{}
+		flagTrailingNewlineValue = true
+	}()
+	cmd := NewCmdBuild(&bytes.Buffer{})
+	err := cmd.ParseFlags([]string{
+		"--separator", "--- # next",
+		"--header", "source,id",
+		"--trailing-newline=false",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flagSeparatorValue != "--- # next" {
+		t.Errorf("unexpected separator %q", flagSeparatorValue)
+	}
+	if !reflect.DeepEqual(flagHeaderValue, []string{"source", "id"}) {
+		t.Errorf("unexpected headers %v", flagHeaderValue)
+	}
+	if flagTrailingNewlineValue {
+		t.Errorf("expected no trailing newline")
+	}
+	if err = validateFlagDocumentFormat(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDocumentFormat(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- cm.yaml
+configMapGenerator:
+- name: gen
+  literals:
+  - a=b
+generatorOptions:
+  disableNameSuffixHash: true
+`))
+	fSys.WriteFile("/app/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+`))
+	defer func() {
+		flagSeparatorValue = "---"
+		flagHeaderValue = []string{}
+		flagTrailingNewlineValue = true
+		flagLineEndingsValue = lineEndingsLF
+	}()
+	var cases = []struct {
+		name            string
+		separator       string
+		headers         []string
+		trailingNewline bool
+		lineEndings     string
+		expected        string
+	}{
+		{"default", "---", nil, true, lineEndingsLF, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+---
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: gen
+`},
+		{"headers", "--- # next", []string{"source", "id"}, false, lineEndingsLF, `# Source: cm.yaml
+# Id: ~G_v1_ConfigMap|~X|plain
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+--- # next
+# Source: .
+# Id: ~G_v1_ConfigMap|~X|gen
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: gen`},
+		{"crlf", "---", nil, true, lineEndingsCRLF, "apiVersion: v1\r\n" +
+			"kind: ConfigMap\r\nmetadata:\r\n  name: plain\r\n---\r\n" +
+			"apiVersion: v1\r\ndata:\r\n  a: b\r\n" +
+			"kind: ConfigMap\r\nmetadata:\r\n  name: gen\r\n"},
+	}
+	for _, tc := range cases {
+		flagSeparatorValue = tc.separator
+		flagHeaderValue = tc.headers
+		flagTrailingNewlineValue = tc.trailingNewline
+		flagLineEndingsValue = tc.lineEndings
+		o := NewOptions("/app", "")
+		var out bytes.Buffer
+		if err := o.runBuild(&out, fSys); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if out.String() != tc.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tc.name, tc.expected, out.String())
+		}
+	}
+}
+
+func TestBuildKyamlKeepsFieldOrderAndComments(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+`))
+	d := `kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: app
+# the spec
+spec:
+  replicas: 1 # one
+  paused: false
+`
+	fSys.WriteFile("/app/deployment.yaml", []byte(d))
+	defer func() {
+		flagEnableKyamlValue = false
+		flagDetectLossyConversionsValue = false
+	}()
+	flagEnableKyamlValue = true
+	flagDetectLossyConversionsValue = true
+	o := NewOptions("/app", "")
+	var out bytes.Buffer
+	if err := o.runBuild(&out, fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != d {
+		t.Errorf("expected\n%s\ngot\n%s", d, out.String())
+	}
+
+	fSys.MkdirAll("/out")
+	o = NewOptions("/app", "/out")
+	if err := o.runBuild(&out, fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := fSys.ReadFile("/out/apps_v1_deployment_app.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != d {
+		t.Errorf("expected\n%s\ngot\n%s", d, b)
+	}
+}
+
+func TestIndividualFileNames(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- role.yaml
+`))
+	fSys.WriteFile("/app/role.yaml", []byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:Reader
+`))
+	defer func() { flagPortableNamesValue = false }()
+	flagPortableNamesValue = true
+	fSys.MkdirAll("/out")
+	o := NewOptions("/app", "/out")
+	var out bytes.Buffer
+	if err := o.runBuild(&out, fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	name := "/out/rbac.authorization.k8s.io_v1_clusterrole_system_reader.yaml"
+	if !fSys.Exists(name) {
+		t.Errorf("expected %s to be written", name)
+	}
+
+	// The in-memory file system doesn't allow
+	// the unportable name written by default.
+	res, err := resource.NewFactory(kunstruct.NewKunstructuredFactoryImpl()).
+		FromBytes([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:Reader
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "rbac.authorization.k8s.io_v1_clusterrole_system:reader.yaml"
+	if actual := (documentFormat{}).fileName(res); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestBuildFromStdin(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	// A kustomization file on disk is ignored.
+	fSys.WriteFile("/app/kustomization.yml", []byte(`
+namePrefix: disk-
+`))
+	fSys.WriteFile("/app/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+`))
+	defer func() { flagBaseDirValue = "" }()
+	flagBaseDirValue = "/app"
+	o := Options{}
+	if err := o.Validate([]string{"-"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	o.in = strings.NewReader(`
+namePrefix: stdin-
+resources:
+- cm.yaml
+`)
+	var out bytes.Buffer
+	if err := o.runBuild(&out, fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: stdin-plain
+`
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+
+	o = Options{}
+	err := o.Validate([]string{"/app"})
+	if err == nil || err.Error() != "flag --base-dir may only be used when building '-'" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBuildWithSetters(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	krm := []byte(`apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.replicas:
+      x-k8s-cli:
+        setter:
+          name: replicas
+          value: "3"
+`)
+	deployment := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 3 # {"$openapi":"replicas"}
+`)
+	fSys.WriteFile("/app/Krmfile", krm)
+	fSys.WriteFile("/app/deployment.yaml", deployment)
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+`))
+	defer func() { flagSetValue = nil }()
+	flagSetValue = []string{"replicas=5"}
+	o := Options{}
+	if err := o.Validate([]string{"/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := o.runBuild(&out, fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 5
+`
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+	// The setters don't outlive the build.
+	if _, found := openapi.Schema().Definitions["io.k8s.cli.setters.replicas"]; found {
+		t.Errorf("the setters are still in the schema")
+	}
+	// The files are unchanged.
+	for path, content := range map[string][]byte{
+		"/app/Krmfile": krm, "/app/deployment.yaml": deployment} {
+		if b, _ := fSys.ReadFile(path); !bytes.Equal(b, content) {
+			t.Errorf("%s changed to\n%s", path, b)
+		}
+	}
+
+	flagSetValue = []string{"image=nginx"}
+	o = Options{}
+	if err := o.Validate([]string{"/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := o.runBuild(&out, fSys)
+	if err == nil || !strings.Contains(err.Error(), "no setter image found") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	flagSetValue = []string{"replicas"}
+	o = Options{}
+	err = o.Validate([]string{"/app"})
+	if err == nil || err.Error() != "illegal flag value --set replicas; expected name=value" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBuildBatch(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte(`
+configMapGenerator:
+- name: config
+  literals:
+  - a=b
+`))
+	fSys.WriteFile("/app/overlays/dev/kustomization.yaml", []byte(`
+resources:
+- ../../base
+namePrefix: dev-
+`))
+	fSys.WriteFile("/app/overlays/prod/eu/kustomization.yaml", []byte(`
+resources:
+- ../../../base
+namePrefix: prod-
+`))
+	fSys.WriteFile("/app/overlays/component/kustomization.yaml", []byte(`
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+namePrefix: c-
+`))
+	defer func() { flagAllOverlaysValue = "" }()
+	flagAllOverlaysValue = "/app/overlays"
+	o := Options{outputPath: "/out"}
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := o.runBuild(&out, fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("unexpected output %s", out.String())
+	}
+	for path, expected := range map[string]string{
+		"/out/dev.yaml": `apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: dev-config-4h2mbtbbt6
+`,
+		"/out/prod_eu.yaml": `apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: prod-config-4h2mbtbbt6
+`,
+	} {
+		b, err := fSys.ReadFile(path)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if string(b) != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", path, expected, b)
+		}
+	}
+	if fSys.Exists("/out/component.yaml") {
+		t.Errorf("unexpected build of component")
+	}
+
+	flagAllOverlaysValue = ""
+	o = Options{outputPath: "/out"}
+	if err := o.Validate([]string{"/app/overlays/dev", "/app/base/../overlays/dev"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := o.runBuild(&out, fSys)
+	if err == nil || !strings.Contains(err.Error(), "would both be written to") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateFlagApplySet(t *testing.T) {
+	defer func() {
+		flagApplySetValue = ""
+		flagApplySetNamespaceValue = ""
+	}()
+	testCases := map[string]struct {
+		value     string
+		namespace string
+		expected  *applyset.Parent
+		errMsg    string
+	}{
+		"unset": {},
+		"secret": {
+			value:    "my-set",
+			expected: &applyset.Parent{Kind: "Secret", Name: "my-set"},
+		},
+		"configmap": {
+			value:     "configmaps/my-set",
+			namespace: "prod",
+			expected:  &applyset.Parent{Kind: "ConfigMap", Name: "my-set", Namespace: "prod"},
+		},
+		"badResource": {
+			value:  "deployments/my-set",
+			errMsg: "illegal flag value --applyset deployments/my-set; expected [secrets/|configmaps/]NAME",
+		},
+		"namespaceAlone": {
+			namespace: "prod",
+			errMsg:    "flag --applyset-namespace may only be used with --applyset",
+		},
+	}
+	for n, tc := range testCases {
+		flagApplySetValue = tc.value
+		flagApplySetNamespaceValue = tc.namespace
+		p, err := validateFlagApplySet()
+		if tc.errMsg != "" {
+			if err == nil || err.Error() != tc.errMsg {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if !reflect.DeepEqual(p, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", n, tc.expected, p)
+		}
+	}
+}
+
+func TestValidateFlagSandbox(t *testing.T) {
+	defer func() {
+		flagSandboxValue = false
+		flagSandboxImageValue = konfig.DefaultSandboxImage
+	}()
+	testCases := map[string]struct {
+		sandbox   bool
+		noImage   bool
+		fnOptions types.FnPluginLoadingOptions
+		errMsg    string
+	}{
+		"unset": {
+			fnOptions: types.FnPluginLoadingOptions{EnableExec: true},
+		},
+		"set": {
+			sandbox: true,
+		},
+		"star": {
+			sandbox:   true,
+			fnOptions: types.FnPluginLoadingOptions{EnableStar: true},
+			errMsg:    "--enable-star may not be used with --sandbox",
+		},
+		"exec": {
+			sandbox:   true,
+			fnOptions: types.FnPluginLoadingOptions{EnableExec: true},
+			errMsg:    "--enable-exec may not be used with --sandbox",
+		},
+		"network": {
+			sandbox:   true,
+			fnOptions: types.FnPluginLoadingOptions{Network: true},
+			errMsg:    "--network may not be used with --sandbox",
+		},
+		"mount": {
+			sandbox:   true,
+			fnOptions: types.FnPluginLoadingOptions{Mounts: []string{"type=bind,src=/,dst=/host"}},
+			errMsg:    "--mount may not be used with --sandbox",
+		},
+		"noImage": {
+			sandbox: true,
+			noImage: true,
+			errMsg:  "--sandbox-image may not be empty",
+		},
+	}
+	for n, tc := range testCases {
+		flagSandboxValue = tc.sandbox
+		flagSandboxImageValue = konfig.DefaultSandboxImage
+		if tc.noImage {
+			flagSandboxImageValue = ""
+		}
+		err := validateFlagSandbox(tc.fnOptions)
+		if tc.errMsg == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", n, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.errMsg {
+			t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+		}
+	}
+}
+
+func TestValidateFlagFnCache(t *testing.T) {
+	defer func() {
+		flagFnCacheValue = fnCacheOff
+		flagFnCacheDirValue = konfig.DefaultFnCacheDir()
+	}()
+	testCases := map[string]struct {
+		value    string
+		dir      string
+		expected types.FnPluginLoadingOptions
+		errMsg   string
+	}{
+		"off": {value: fnCacheOff, dir: "/cache"},
+		"on": {
+			value:    fnCacheOn,
+			dir:      "/cache",
+			expected: types.FnPluginLoadingOptions{CacheDir: "/cache"},
+		},
+		"refresh": {
+			value:    fnCacheRefresh,
+			dir:      "/cache",
+			expected: types.FnPluginLoadingOptions{CacheDir: "/cache", RefreshCache: true},
+		},
+		"noDir": {
+			value:  fnCacheOn,
+			errMsg: "--fn-cache-dir may not be empty",
+		},
+		"bad": {
+			value:  "sometimes",
+			dir:    "/cache",
+			errMsg: "illegal flag value --fn-cache sometimes; legal values: [off on refresh]",
+		},
+	}
+	for n, tc := range testCases {
+		flagFnCacheValue = tc.value
+		flagFnCacheDirValue = tc.dir
+		var actual types.FnPluginLoadingOptions
+		err := validateFlagFnCache(&actual)
+		if tc.errMsg != "" {
+			if err == nil || err.Error() != tc.errMsg {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestValidateFlagExecSandbox(t *testing.T) {
+	defer func() { flagExecSandboxValue = string(types.ExecSandboxNone) }()
+	for _, s := range types.ExecSandboxes {
+		flagExecSandboxValue = string(s)
+		var actual types.FnPluginLoadingOptions
+		if err := validateFlagExecSandbox(&actual); err != nil {
+			t.Errorf("%s: unexpected error: %v", s, err)
+		}
+		if actual.ExecSandbox != s {
+			t.Errorf("%s: expected %s, got %s", s, s, actual.ExecSandbox)
+		}
+	}
+	flagExecSandboxValue = "jail"
+	err := validateFlagExecSandbox(&types.FnPluginLoadingOptions{})
+	expected := "illegal flag value --exec-sandbox jail; legal values: [none workdir strict]"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestValidateFlagFnPlatform(t *testing.T) {
+	defer func() { flagFnPlatformValue = "" }()
+	for _, p := range []string{"", "linux/arm64", "linux/arm/v7"} {
+		flagFnPlatformValue = p
+		var actual types.FnPluginLoadingOptions
+		if err := validateFlagFnPlatform(&actual); err != nil {
+			t.Errorf("%s: unexpected error: %v", p, err)
+		}
+		if actual.Platform != p {
+			t.Errorf("%s: expected %s, got %s", p, p, actual.Platform)
+		}
+	}
+	flagFnPlatformValue = "arm64"
+	err := validateFlagFnPlatform(&types.FnPluginLoadingOptions{})
+	expected := "illegal flag value --fn-platform arm64; expected os/arch[/variant]"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestValidateFlagLineEndings(t *testing.T) {
+	defer func() { flagLineEndingsValue = lineEndingsLF }()
+	flagLineEndingsValue = lineEndingsCRLF
+	if err := validateFlagDocumentFormat(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !crlfLineEndings() {
+		t.Errorf("expected crlf line endings")
+	}
+	flagLineEndingsValue = "cr"
+	err := validateFlagDocumentFormat()
+	expected := "illegal flag value --line-endings cr; legal values: [lf crlf native]"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestValidateFlagPluginLimits(t *testing.T) {
+	defer func() {
+		flagPluginTimeoutValue = types.DefaultPluginLimits().Timeout
+		flagPluginMaxMemoryValue = "4Gi"
+		flagPluginMaxOutputValue = "256Mi"
+	}()
+	testCases := map[string]struct {
+		timeout   time.Duration
+		maxMemory string
+		maxOutput string
+		expected  types.PluginLimits
+		errMsg    string
+	}{
+		"defaults": {
+			timeout:   5 * time.Minute,
+			maxMemory: "4Gi",
+			maxOutput: "256Mi",
+			expected:  types.DefaultPluginLimits(),
+		},
+		"unbounded": {
+			maxMemory: "0",
+			maxOutput: "0",
+		},
+		"negativeTimeout": {
+			timeout:   -time.Second,
+			maxMemory: "0",
+			maxOutput: "0",
+			errMsg:    "illegal flag value --plugin-timeout -1s; legal values: durations of 0 or more",
+		},
+		"badSize": {
+			maxMemory: "0",
+			maxOutput: "lots",
+			errMsg:    "illegal flag value --plugin-max-output lots; legal values: sizes such as 512Mi",
+		},
+	}
+	for name, tc := range testCases {
+		flagPluginTimeoutValue = tc.timeout
+		flagPluginMaxMemoryValue = tc.maxMemory
+		flagPluginMaxOutputValue = tc.maxOutput
+		actual, err := validateFlagPluginLimits()
+		if tc.errMsg != "" {
+			if err == nil || err.Error() != tc.errMsg {
+				t.Errorf("%s: expected error %q, got %v", name, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if actual != tc.expected {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, actual)
+		}
+	}
+}
+
+func TestValidateFlagCatalog(t *testing.T) {
+	defer func() {
+		flagRequireCatalogValue = false
+		flagCatalogValue = nil
+		flagCatalogKeyValue = ""
+	}()
+	testCases := map[string]struct {
+		require  bool
+		catalogs []string
+		key      string
+		errMsg   string
+	}{
+		"off": {},
+		"on": {
+			require:  true,
+			catalogs: []string{"https://example.com/catalog.yaml"},
+			key:      "cosign.pub",
+		},
+		"noCatalog": {
+			require: true,
+			key:     "cosign.pub",
+			errMsg:  "flag --require-catalog needs --catalog and --catalog-key",
+		},
+		"noKey": {
+			require:  true,
+			catalogs: []string{"catalog.yaml"},
+			errMsg:   "flag --require-catalog needs --catalog and --catalog-key",
+		},
+		"notRequired": {
+			catalogs: []string{"catalog.yaml"},
+			errMsg:   "flags --catalog and --catalog-key may only be used with --require-catalog",
+		},
+	}
+	for n, tc := range testCases {
+		flagRequireCatalogValue = tc.require
+		flagCatalogValue = tc.catalogs
+		flagCatalogKeyValue = tc.key
+		err := validateFlagCatalog()
+		if tc.errMsg == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", n, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.errMsg {
+			t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+		}
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	defer func() { flagFailOnValue = string(types.SeverityError) }()
+	r := &types.ValidationReport{}
+	r.Add("limits", []types.Finding{
+		{Severity: types.SeverityError, Message: "no limits"},
+		{Severity: types.SeverityWarning, Message: "low limits"},
+		{Severity: types.SeverityInfo, Message: "fine"},
+	})
+	testCases := map[string]struct {
+		value    string
+		expected string
+		errMsg   string
+	}{
+		"error": {
+			value: "error",
+			expected: "warning: low limits (validator limits)\n" +
+				"info: fine (validator limits)\n",
+		},
+		"info": {value: "info"},
+		"none": {
+			value: "none",
+			expected: "error: no limits (validator limits)\n" +
+				"warning: low limits (validator limits)\n" +
+				"info: fine (validator limits)\n",
+		},
+		"bad": {
+			value:  "fatal",
+			errMsg: "illegal flag value --fail-on fatal; legal values: [none error warning info]",
+		},
+	}
+	for n, tc := range testCases {
+		flagFailOnValue = tc.value
+		err := validateFlagFailOn()
+		if tc.errMsg != "" {
+			if err == nil || err.Error() != tc.errMsg {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		var out bytes.Buffer
+		writeFindings(&out, r)
+		if out.String() != tc.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", n, tc.expected, out.String())
+		}
+	}
+}
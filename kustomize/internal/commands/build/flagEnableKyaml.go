@@ -4,18 +4,42 @@
 package build
 
 import (
+	"fmt"
+
 	"github.com/spf13/pflag"
 )
 
+const (
+	flagEnableKyamlName            = "enable_kyaml"
+	flagDetectLossyConversionsName = "detect-lossy-conversions"
+)
+
 var (
-	flagEnableKyamlValue = false
+	flagEnableKyamlValue            = false
+	flagDetectLossyConversionsValue = false
+	flagDetectLossyConversionsHelp  = "If set, fail the build if resources " +
+		"are converted to maps or JSON and back in a way losing the order of " +
+		"their fields, their comments or the style of their values, listing " +
+		"the conversions.  Requires --" + flagEnableKyamlName + "."
 )
 
 func addFlagEnableKyaml(set *pflag.FlagSet) {
 	set.BoolVar(
 		&flagEnableKyamlValue,
-		"enable_kyaml", // flag name
-		false,          // default value
+		flagEnableKyamlName, // flag name
+		false,               // default value
 		"enable dependence on kyaml instead of k8sdeps.", // help
 	)
+	set.BoolVar(
+		&flagDetectLossyConversionsValue, flagDetectLossyConversionsName,
+		false, flagDetectLossyConversionsHelp)
+}
+
+func validateFlagEnableKyaml() error {
+	if flagDetectLossyConversionsValue && !flagEnableKyamlValue {
+		return fmt.Errorf(
+			"--%s requires --%s",
+			flagDetectLossyConversionsName, flagEnableKyamlName)
+	}
+	return nil
 }
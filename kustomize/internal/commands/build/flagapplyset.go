@@ -0,0 +1,73 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/applyset"
+)
+
+const (
+	flagApplySetName          = "applyset"
+	flagApplySetNamespaceName = "applyset-namespace"
+	flagPruneLabelName        = "prune-label"
+)
+
+// applySetKinds maps the resources that may be
+// the parent of an ApplySet to their kinds.
+var applySetKinds = map[string]string{
+	"secrets":    "Secret",
+	"configmaps": "ConfigMap",
+}
+
+var (
+	flagApplySetValue          = ""
+	flagApplySetNamespaceValue = ""
+	flagPruneLabelValue        map[string]string
+)
+
+func addFlagApplySet(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagApplySetValue, flagApplySetName, "",
+		"If set, label the resources as members of the ApplySet of the given "+
+			"parent, [secrets/|configmaps/]NAME, and add the parent to the output, "+
+			"for pruning with 'kubectl apply --prune --applyset'.")
+	set.StringVar(
+		&flagApplySetNamespaceValue, flagApplySetNamespaceName, "",
+		"The namespace of the ApplySet parent.  Defaults to the namespace "+
+			"of the resources if they share one, else 'default'.")
+	set.StringToStringVar(
+		&flagPruneLabelValue, flagPruneLabelName, nil,
+		"Labels, as key=value, to add to all the resources, "+
+			"for pruning with 'kubectl apply --prune -l key=value'.")
+}
+
+func validateFlagApplySet() (*applyset.Parent, error) {
+	if flagApplySetValue == "" {
+		if flagApplySetNamespaceValue != "" {
+			return nil, fmt.Errorf(
+				"flag --%s may only be used with --%s",
+				flagApplySetNamespaceName, flagApplySetName)
+		}
+		return nil, nil
+	}
+	resource, name := "secrets", flagApplySetValue
+	if i := strings.Index(flagApplySetValue, "/"); i >= 0 {
+		resource, name = flagApplySetValue[:i], flagApplySetValue[i+1:]
+	}
+	kind, ok := applySetKinds[resource]
+	if !ok || name == "" {
+		return nil, fmt.Errorf(
+			"illegal flag value --%s %s; expected [secrets/|configmaps/]NAME",
+			flagApplySetName, flagApplySetValue)
+	}
+	return &applyset.Parent{
+		Kind:      kind,
+		Name:      name,
+		Namespace: flagApplySetNamespaceValue,
+	}, nil
+}
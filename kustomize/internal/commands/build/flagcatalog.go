@@ -0,0 +1,64 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/catalog"
+)
+
+const (
+	flagRequireCatalogName = "require-catalog"
+	flagCatalogName        = "catalog"
+	flagCatalogKeyName     = "catalog-key"
+)
+
+var (
+	flagRequireCatalogValue = false
+	flagCatalogValue        []string
+	flagCatalogKeyValue     = ""
+)
+
+func addFlagCatalog(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagRequireCatalogValue, flagRequireCatalogName, false,
+		"Refuse plugins and functions, other than builtins, that aren't "+
+			"approved by one of the catalogs given by --"+flagCatalogName+".")
+	set.StringArrayVar(
+		&flagCatalogValue, flagCatalogName, nil,
+		"URL or path of a catalog of approved plugins and functions, signed "+
+			"as by 'cosign sign-blob', its signature found at the same place "+
+			"suffixed with '"+catalog.SignatureSuffix+"'.")
+	set.StringVar(
+		&flagCatalogKeyValue, flagCatalogKeyName, "",
+		"Path of the PEM public key verifying the signatures of the catalogs.")
+}
+
+func validateFlagCatalog() error {
+	if !flagRequireCatalogValue {
+		if len(flagCatalogValue) > 0 || flagCatalogKeyValue != "" {
+			return fmt.Errorf(
+				"flags --%s and --%s may only be used with --%s",
+				flagCatalogName, flagCatalogKeyName, flagRequireCatalogName)
+		}
+		return nil
+	}
+	if len(flagCatalogValue) == 0 || flagCatalogKeyValue == "" {
+		return fmt.Errorf(
+			"flag --%s needs --%s and --%s",
+			flagRequireCatalogName, flagCatalogName, flagCatalogKeyName)
+	}
+	return nil
+}
+
+func catalogSources() []catalog.Source {
+	var result []catalog.Source
+	for _, url := range flagCatalogValue {
+		result = append(result, catalog.Source{
+			URL: url, PublicKey: flagCatalogKeyValue})
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagSeparatorName       = "separator"
+	flagHeaderName          = "header"
+	flagTrailingNewlineName = "trailing-newline"
+	flagLineEndingsName     = "line-endings"
+	flagPortableNamesName   = "portable-file-names"
+
+	headerSource = "source"
+	headerId     = "id"
+
+	lineEndingsLF     = "lf"
+	lineEndingsCRLF   = "crlf"
+	lineEndingsNative = "native"
+)
+
+var (
+	flagSeparatorValue       = "---"
+	flagHeaderValue          = []string{}
+	flagTrailingNewlineValue = true
+	flagLineEndingsValue     = lineEndingsLF
+	flagPortableNamesValue   = false
+)
+
+func addFlagDocumentFormat(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagSeparatorValue, flagSeparatorName, "---",
+		"The line written between resources in the build output.")
+	set.StringSliceVar(
+		&flagHeaderValue, flagHeaderName, []string{},
+		"Comment lines to write above each resource. "+
+			"Use '"+headerSource+"' for the file (or kustomization) the resource was read from, "+
+			"'"+headerId+"' for the resource id.")
+	set.BoolVar(
+		&flagTrailingNewlineValue, flagTrailingNewlineName, true,
+		"If false, omit the newline at the end of the build output.")
+	set.StringVar(
+		&flagLineEndingsValue, flagLineEndingsName, lineEndingsLF,
+		"The line endings of the build output: "+
+			"'"+lineEndingsLF+"', '"+lineEndingsCRLF+"', or '"+lineEndingsNative+
+			"' for those of the platform kustomize runs on.")
+	set.BoolVar(
+		&flagPortableNamesValue, flagPortableNamesName, false,
+		"If true, replace the characters some platform doesn't allow "+
+			"in file names, such as ':' on Windows, with '_' in the names "+
+			"of the files written to an --output directory.")
+}
+
+func validateFlagDocumentFormat() error {
+	if flagSeparatorValue == "" {
+		return fmt.Errorf("flag --%s may not be empty", flagSeparatorName)
+	}
+	for _, h := range flagHeaderValue {
+		switch h {
+		case headerSource, headerId:
+		default:
+			return fmt.Errorf(
+				"illegal flag value --%s %s; legal values: %v",
+				flagHeaderName, h, []string{headerSource, headerId})
+		}
+	}
+	switch flagLineEndingsValue {
+	case lineEndingsLF, lineEndingsCRLF, lineEndingsNative:
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagLineEndingsName, flagLineEndingsValue,
+			[]string{lineEndingsLF, lineEndingsCRLF, lineEndingsNative})
+	}
+	return nil
+}
+
+// crlfLineEndings returns whether the build output
+// ends its lines with CRLF, per --line-endings.
+func crlfLineEndings() bool {
+	switch flagLineEndingsValue {
+	case lineEndingsCRLF:
+		return true
+	case lineEndingsNative:
+		return runtime.GOOS == "windows"
+	default:
+		return false
+	}
+}
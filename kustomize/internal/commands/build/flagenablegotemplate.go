@@ -0,0 +1,23 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagEnableGoTemplateName = "enable-gotemplate"
+	flagEnableGoTemplateHelp = `enable the GoTemplateGenerator builtin, rendering Go templates into resources`
+)
+
+var (
+	flagEnableGoTemplateValue = false
+)
+
+func addFlagEnableGoTemplate(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagEnableGoTemplateValue, flagEnableGoTemplateName,
+		false, flagEnableGoTemplateHelp)
+}
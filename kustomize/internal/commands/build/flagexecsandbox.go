@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const flagExecSandboxName = "exec-sandbox"
+
+var (
+	flagExecSandboxValue = string(types.ExecSandboxNone)
+	flagExecSandboxHelp  = "Restrictions on exec and rpc plugins and exec functions (Linux only): '" +
+		string(types.ExecSandboxWorkdir) + "' confines them to their working directory, the " +
+		"directory of the executable and the temporary directory, with the system directories " +
+		"read-only, and '" + string(types.ExecSandboxStrict) + "' furthermore refuses them " +
+		"the network and the syscalls administering the host."
+)
+
+func addFlagExecSandbox(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagExecSandboxValue, flagExecSandboxName,
+		string(types.ExecSandboxNone), flagExecSandboxHelp)
+}
+
+// validateFlagExecSandbox sets the exec sandbox
+// of the functions and plugins per the flag.
+func validateFlagExecSandbox(fnOptions *types.FnPluginLoadingOptions) error {
+	if !types.IsExecSandbox(flagExecSandboxValue) {
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagExecSandboxName, flagExecSandboxValue, types.ExecSandboxes)
+	}
+	fnOptions.ExecSandbox = types.ExecSandbox(flagExecSandboxValue)
+	return nil
+}
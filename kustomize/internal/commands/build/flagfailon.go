@@ -0,0 +1,48 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const flagFailOnName = "fail-on"
+
+var (
+	flagFailOnValue = string(types.SeverityError)
+	flagFailOnHelp  = "Fail the build on findings of validators at least this severe " +
+		fmt.Sprintf("(one of %v); other findings are reported to stderr.", types.Severities)
+)
+
+func addFlagFailOn(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagFailOnValue, flagFailOnName,
+		string(types.SeverityError), flagFailOnHelp)
+}
+
+func validateFlagFailOn() error {
+	if types.IsSeverity(flagFailOnValue) {
+		return nil
+	}
+	return fmt.Errorf(
+		"illegal flag value --%s %s; legal values: %v",
+		flagFailOnName, flagFailOnValue, types.Severities)
+}
+
+// writeFindings writes the findings that didn't fail
+// the build; those that did are in its error.
+func writeFindings(w io.Writer, r *types.ValidationReport) {
+	if r == nil {
+		return
+	}
+	for _, f := range r.Findings {
+		if !f.Severity.AtLeast(types.Severity(flagFailOnValue)) {
+			fmt.Fprintln(w, f)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagFnCacheName    = "fn-cache"
+	flagFnCacheDirName = "fn-cache-dir"
+	fnCacheOff         = "off"
+	fnCacheOn          = "on"
+	fnCacheRefresh     = "refresh"
+)
+
+var (
+	flagFnCacheValue = fnCacheOff
+	flagFnCacheHelp  = "Whether to cache the output of container functions, keyed by " +
+		"image digest, config and input: '" + fnCacheOn + "' runs a function only if its " +
+		"output isn't cached, '" + fnCacheRefresh + "' always runs it, replacing the cached " +
+		"output, and '" + fnCacheOff + "' neither reads nor writes the cache.  Functions " +
+		"with network access or mounts aren't cached."
+	flagFnCacheDirValue = konfig.DefaultFnCacheDir()
+	flagFnCacheDirHelp  = "The directory caching the output of container functions."
+)
+
+func addFlagFnCache(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagFnCacheValue, flagFnCacheName, fnCacheOff, flagFnCacheHelp)
+	set.StringVar(
+		&flagFnCacheDirValue, flagFnCacheDirName,
+		konfig.DefaultFnCacheDir(), flagFnCacheDirHelp)
+}
+
+// validateFlagFnCache sets the cache options
+// of the functions per the flags.
+func validateFlagFnCache(fnOptions *types.FnPluginLoadingOptions) error {
+	switch flagFnCacheValue {
+	case fnCacheOff:
+		return nil
+	case fnCacheOn, fnCacheRefresh:
+		if flagFnCacheDirValue == "" {
+			return fmt.Errorf("--%s may not be empty", flagFnCacheDirName)
+		}
+		fnOptions.CacheDir = flagFnCacheDirValue
+		fnOptions.RefreshCache = flagFnCacheValue == fnCacheRefresh
+		return nil
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagFnCacheName, flagFnCacheValue,
+			[]string{fnCacheOff, fnCacheOn, fnCacheRefresh})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+)
+
+const flagFnPlatformName = "fn-platform"
+
+var (
+	flagFnPlatformValue = ""
+	flagFnPlatformHelp  = "The platform, os/arch[/variant], of the variant of the " +
+		"images of container functions to run, e.g. linux/amd64; the host's, " +
+		"'" + container.HostPlatform() + "', if empty.  A local image of another " +
+		"platform than the one given is replaced by the variant pulled from its " +
+		"manifest list; if none is given, running it fails."
+)
+
+func addFlagFnPlatform(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagFnPlatformValue, flagFnPlatformName, "", flagFnPlatformHelp)
+}
+
+// validateFlagFnPlatform sets the platform of
+// the images of the functions per the flag.
+func validateFlagFnPlatform(fnOptions *types.FnPluginLoadingOptions) error {
+	if flagFnPlatformValue != "" && !container.IsPlatform(flagFnPlatformValue) {
+		return fmt.Errorf(
+			"illegal flag value --%s %s; expected os/arch[/variant]",
+			flagFnPlatformName, flagFnPlatformValue)
+	}
+	fnOptions.Platform = flagFnPlatformValue
+	return nil
+}
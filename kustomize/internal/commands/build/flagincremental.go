@@ -0,0 +1,57 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/buildcache"
+	"sigs.k8s.io/kustomize/api/konfig"
+)
+
+const (
+	flagIncrementalName   = "incremental"
+	flagBuildCacheDirName = "build-cache-dir"
+)
+
+var (
+	flagIncrementalValue = false
+	flagIncrementalHelp  = "If set, reuse the output of earlier builds, and the " +
+		"bases they accumulated, as long as the files, remote bases, plugins " +
+		"and charts they were made from are unchanged.  Remote bases must be " +
+		"pinned to commits, charts to versions and container functions to " +
+		"image digests to be reused."
+	flagBuildCacheDirValue = konfig.DefaultBuildCacheDir()
+	flagBuildCacheDirHelp  = "The directory caching the output of incremental builds."
+)
+
+func addFlagIncremental(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagIncrementalValue, flagIncrementalName, false, flagIncrementalHelp)
+	set.StringVar(
+		&flagBuildCacheDirValue, flagBuildCacheDirName,
+		konfig.DefaultBuildCacheDir(), flagBuildCacheDirHelp)
+}
+
+func validateFlagIncremental() error {
+	if !flagIncrementalValue {
+		return nil
+	}
+	if flagTraceValue {
+		return fmt.Errorf(
+			"--%s may not be used with --%s", flagIncrementalName, flagTraceName)
+	}
+	if flagBuildCacheDirValue == "" {
+		return fmt.Errorf("--%s may not be empty", flagBuildCacheDirName)
+	}
+	return nil
+}
+
+func makeBuildCache() *buildcache.Cache {
+	if !flagIncrementalValue {
+		return nil
+	}
+	return buildcache.New(flagBuildCacheDirValue)
+}
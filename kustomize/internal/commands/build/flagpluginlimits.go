@@ -0,0 +1,69 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagPluginTimeoutName   = "plugin-timeout"
+	flagPluginMaxMemoryName = "plugin-max-memory"
+	flagPluginMaxOutputName = "plugin-max-output"
+)
+
+var (
+	flagPluginTimeoutValue   = types.DefaultPluginLimits().Timeout
+	flagPluginMaxMemoryValue = "4Gi"
+	flagPluginMaxOutputValue = "256Mi"
+)
+
+func addFlagPluginLimits(set *pflag.FlagSet) {
+	set.DurationVar(
+		&flagPluginTimeoutValue, flagPluginTimeoutName,
+		types.DefaultPluginLimits().Timeout,
+		"Time each exec, RPC or function plugin may run, 0 for no limit; "+
+			"the annotation "+types.PluginTimeoutAnnotation+" overrides it.")
+	set.StringVar(
+		&flagPluginMaxMemoryValue, flagPluginMaxMemoryName, "4Gi",
+		"Memory each exec, RPC or function plugin may use (Linux or "+
+			"containers only), 0 for no limit; the annotation "+
+			types.PluginMaxMemoryAnnotation+" overrides it.")
+	set.StringVar(
+		&flagPluginMaxOutputValue, flagPluginMaxOutputName, "256Mi",
+		"Output each exec, RPC or function plugin may write, 0 for no limit; "+
+			"the annotation "+types.PluginMaxOutputAnnotation+" overrides it.")
+}
+
+// validateFlagPluginLimits returns the limits
+// of plugins per the flags.
+func validateFlagPluginLimits() (types.PluginLimits, error) {
+	var l types.PluginLimits
+	if flagPluginTimeoutValue < 0 {
+		return l, fmt.Errorf(
+			"illegal flag value --%s %v; legal values: durations of 0 or more",
+			flagPluginTimeoutName, flagPluginTimeoutValue)
+	}
+	l.Timeout = flagPluginTimeoutValue
+	for _, x := range []struct {
+		name  string
+		value string
+		size  *int64
+	}{
+		{flagPluginMaxMemoryName, flagPluginMaxMemoryValue, &l.MaxMemory},
+		{flagPluginMaxOutputName, flagPluginMaxOutputValue, &l.MaxOutput},
+	} {
+		size, err := types.ParseSize(x.value)
+		if err != nil {
+			return l, fmt.Errorf(
+				"illegal flag value --%s %s; legal values: sizes such as 512Mi",
+				x.name, x.value)
+		}
+		*x.size = size
+	}
+	return l, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/profile"
+)
+
+const (
+	flagProfileName = "profile"
+	profileText     = "text"
+	profileJson     = "json"
+)
+
+var (
+	flagProfileValue = ""
+	flagProfileHelp  = "If set, report the time spent in each phase of the build " +
+		"to stderr, in the given format ('" + profileText + "' or '" + profileJson + "')."
+)
+
+func addFlagProfile(set *pflag.FlagSet) {
+	set.StringVar(&flagProfileValue, flagProfileName, "", flagProfileHelp)
+}
+
+func validateFlagProfile() error {
+	switch flagProfileValue {
+	case "", profileText, profileJson:
+		return nil
+	default:
+		return fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagProfileName, flagProfileValue,
+			[]string{profileText, profileJson})
+	}
+}
+
+func makeProfile() *profile.Profile {
+	if flagProfileValue == "" {
+		return nil
+	}
+	return profile.New()
+}
+
+func writeProfile(w io.Writer, p *profile.Profile) error {
+	switch flagProfileValue {
+	case profileText:
+		return p.WriteText(w)
+	case profileJson:
+		return p.WriteJSON(w)
+	default:
+		return nil
+	}
+}
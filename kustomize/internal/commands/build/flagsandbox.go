@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagSandboxName      = "sandbox"
+	flagSandboxImageName = "sandbox-image"
+)
+
+var (
+	flagSandboxValue = false
+	flagSandboxHelp  = "If set, clone remote bases in a container, with no access " +
+		"to the host beyond the clone, load no files over HTTP, and allow only " +
+		"builtins and container functions without network access or mounts as " +
+		"plugins.  For untrusted kustomizations."
+	flagSandboxImageValue = konfig.DefaultSandboxImage
+	flagSandboxImageHelp  = "The image, providing git, to clone remote bases in with --" +
+		flagSandboxName + "."
+)
+
+func addFlagSandbox(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagSandboxValue, flagSandboxName, false, flagSandboxHelp)
+	set.StringVar(
+		&flagSandboxImageValue, flagSandboxImageName,
+		konfig.DefaultSandboxImage, flagSandboxImageHelp)
+}
+
+// validateFlagSandbox rejects the function options
+// that would give functions access to the host.
+func validateFlagSandbox(fnOptions types.FnPluginLoadingOptions) error {
+	if !flagSandboxValue {
+		return nil
+	}
+	switch {
+	case fnOptions.EnableExec:
+		return fmt.Errorf("--enable-exec may not be used with --%s", flagSandboxName)
+	case fnOptions.EnableStar:
+		return fmt.Errorf("--enable-star may not be used with --%s", flagSandboxName)
+	case fnOptions.Network:
+		return fmt.Errorf("--network may not be used with --%s", flagSandboxName)
+	case len(fnOptions.Mounts) > 0:
+		return fmt.Errorf("--mount may not be used with --%s", flagSandboxName)
+	}
+	if flagSandboxImageValue == "" {
+		return fmt.Errorf("--%s may not be empty", flagSandboxImageName)
+	}
+	return nil
+}
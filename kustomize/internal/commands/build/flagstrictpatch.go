@@ -0,0 +1,23 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagStrictPatchName = "strict-patch"
+	flagStrictPatchHelp = `fail strategic merge patches setting fields unknown to the schema of what they patch, or patching kinds of unknown schema`
+)
+
+var (
+	flagStrictPatchValue = false
+)
+
+func addFlagStrictPatch(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flagStrictPatchValue, flagStrictPatchName,
+		false, flagStrictPatchHelp)
+}
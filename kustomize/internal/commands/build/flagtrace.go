@@ -0,0 +1,37 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"io"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/trace"
+)
+
+const flagTraceName = "trace"
+
+var (
+	flagTraceValue = false
+	flagTraceHelp  = "If set, report to stderr the fields each transformer " +
+		"changed in each resource."
+)
+
+func addFlagTrace(set *pflag.FlagSet) {
+	set.BoolVar(&flagTraceValue, flagTraceName, false, flagTraceHelp)
+}
+
+func makeTrace() *trace.Trace {
+	if !flagTraceValue {
+		return nil
+	}
+	return trace.New()
+}
+
+func writeTrace(w io.Writer, t *trace.Trace) error {
+	if t == nil {
+		return nil
+	}
+	return t.WriteText(w)
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/kustomize/kyaml/setters2"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const flagSetName = "set"
+
+var flagSetValue []string
+
+func addFlagSet(set *pflag.FlagSet) {
+	set.StringArrayVar(
+		&flagSetValue, flagSetName, []string{},
+		"Override the value of a setter declared in the "+krmfile.KrmfileName+
+			" of the target, as name=value, for this build only; "+
+			"no file is changed.  May be repeated.")
+}
+
+// validateFlagSet returns the setter values to override.
+func validateFlagSet() (map[string]string, error) {
+	if len(flagSetValue) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string)
+	for _, s := range flagSetValue {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf(
+				"illegal flag value --%s %s; expected name=value", flagSetName, s)
+		}
+		result[kv[0]] = kv[1]
+	}
+	return result, nil
+}
+
+// setterRefs are the strings in the comments of fields
+// referencing setters and substitutions, in full or short form.
+var setterRefs = [][]byte{[]byte("#/definitions/io.k8s.cli."), []byte(`"$openapi"`)}
+
+// fsWithSetters is a file system in which the files of a
+// package are read with some of its setters set to new values.
+type fsWithSetters struct {
+	filesys.FileSystem
+	dir   filesys.ConfirmedDir
+	names []string
+}
+
+// newFsWithSetters reads the setter definitions of the package in
+// dir, overrides the values of the named setters, and returns a
+// file system applying the new values to the package's files, with
+// a function removing the setter definitions from the global schema
+// once the build is done.
+func newFsWithSetters(
+	fSys filesys.FileSystem, dir string,
+	values map[string]string) (filesys.FileSystem, func(), error) {
+	if !fSys.IsDir(dir) {
+		return nil, nil, fmt.Errorf(
+			"flag --%s needs a local directory to build, not %s", flagSetName, dir)
+	}
+	d, _, err := fSys.CleanedAbs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := d.Join(krmfile.KrmfileName)
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"flag --%s needs the setters declared in %s: %v", flagSetName, path, err)
+	}
+	object, err := yaml.Parse(string(content))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse %s: %v", path, err)
+	}
+	var names []string
+	for name, value := range values {
+		_, err = setters2.SetOpenAPI{Name: name, Value: value}.Filter(object)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", path, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	definitions, err := object.Pipe(yaml.Lookup(openapi.SupplementaryOpenAPIFieldName))
+	if err != nil {
+		return nil, nil, err
+	}
+	j, err := definitions.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	restore, err := openapi.AddSchemaScoped(j)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load the setters of %s: %v", path, err)
+	}
+	return &fsWithSetters{FileSystem: fSys, dir: d, names: names}, restore, nil
+}
+
+// inPackage returns whether path is a yaml file of the package.
+func (fs *fsWithSetters) inPackage(path string) bool {
+	if !strings.HasPrefix(path, fs.dir.String()+string(filepath.Separator)) {
+		return false
+	}
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func (fs *fsWithSetters) ReadFile(path string) ([]byte, error) {
+	content, err := fs.FileSystem.ReadFile(path)
+	if err != nil || !fs.inPackage(path) || !referencesSetters(content) {
+		return content, err
+	}
+	result, err := fs.set(content)
+	if err != nil {
+		return nil, fmt.Errorf("applying --%s to %s: %v", flagSetName, path, err)
+	}
+	return result, nil
+}
+
+func referencesSetters(content []byte) bool {
+	for _, ref := range setterRefs {
+		if bytes.Contains(content, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// set applies the overridden setters to the content of a file,
+// returning the content unchanged if no field references them.
+func (fs *fsWithSetters) set(content []byte) ([]byte, error) {
+	nodes, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(content),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return nil, err
+	}
+	count := 0
+	for _, name := range fs.names {
+		s := &setters2.Set{Name: name}
+		for _, n := range nodes {
+			if _, err = s.Filter(n); err != nil {
+				return nil, err
+			}
+		}
+		count += s.Count
+	}
+	if count == 0 {
+		return content, nil
+	}
+	var b bytes.Buffer
+	if err = (kio.ByteWriter{Writer: &b}).Write(nodes); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
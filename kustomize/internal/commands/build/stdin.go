@@ -0,0 +1,117 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+)
+
+const (
+	// stdinArg, given as the path to build, means
+	// read the kustomization file from stdin.
+	stdinArg = "-"
+
+	flagBaseDirName = "base-dir"
+)
+
+var flagBaseDirValue = ""
+
+func addFlagBaseDir(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagBaseDirValue, flagBaseDirName, "",
+		"When building '"+stdinArg+"', the directory the kustomization read from stdin "+
+			"is treated as being in, against which its paths are resolved.  "+
+			"Defaults to the current directory.")
+}
+
+func validateFlagBaseDir(path string) error {
+	if flagBaseDirValue != "" && path != stdinArg {
+		return fmt.Errorf(
+			"flag --%s may only be used when building '%s'", flagBaseDirName, stdinArg)
+	}
+	return nil
+}
+
+// baseDir returns the directory holding
+// the kustomization read from stdin.
+func baseDir() string {
+	if flagBaseDirValue == "" {
+		return filesys.SelfDir
+	}
+	return flagBaseDirValue
+}
+
+// fsWithKustomization is a file system in which the
+// kustomization file of one directory is replaced.
+type fsWithKustomization struct {
+	filesys.FileSystem
+	dir     filesys.ConfirmedDir
+	content []byte
+}
+
+// newFsWithKustomization returns a file system in which dir
+// holds a kustomization file with content read from in,
+// hiding whatever kustomization file dir holds on disk.
+func newFsWithKustomization(
+	fSys filesys.FileSystem, dir string, in io.Reader) (filesys.FileSystem, error) {
+	if !fSys.IsDir(dir) {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	d, _, err := fSys.CleanedAbs(dir)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("reading kustomization from stdin: %v", err)
+	}
+	return &fsWithKustomization{FileSystem: fSys, dir: d, content: content}, nil
+}
+
+// kustomizationFile returns whether the path is one of the
+// recognized kustomization files of the replaced directory,
+// and whether it is the one with replaced content.
+func (fs *fsWithKustomization) kustomizationFile(path string) (recognized, replaced bool) {
+	if filepath.Dir(path) != fs.dir.String() {
+		return false, false
+	}
+	for _, n := range konfig.RecognizedKustomizationFileNames() {
+		if filepath.Base(path) == n {
+			return true, n == konfig.DefaultKustomizationFileName()
+		}
+	}
+	return false, false
+}
+
+func (fs *fsWithKustomization) Exists(path string) bool {
+	if recognized, replaced := fs.kustomizationFile(path); recognized {
+		return replaced
+	}
+	return fs.FileSystem.Exists(path)
+}
+
+func (fs *fsWithKustomization) CleanedAbs(path string) (filesys.ConfirmedDir, string, error) {
+	if _, replaced := fs.kustomizationFile(path); replaced {
+		return fs.dir, filepath.Base(path), nil
+	}
+	return fs.FileSystem.CleanedAbs(path)
+}
+
+func (fs *fsWithKustomization) ReadFile(path string) ([]byte, error) {
+	if recognized, replaced := fs.kustomizationFile(path); recognized {
+		if !replaced {
+			return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+		}
+		return fs.content, nil
+	}
+	return fs.FileSystem.ReadFile(path)
+}
@@ -16,9 +16,15 @@ import (
 	"sigs.k8s.io/kustomize/api/k8sdeps/validator"
 	"sigs.k8s.io/kustomize/api/konfig"
 	shell_complete "sigs.k8s.io/kustomize/cmd/config/complete"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/alpha"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/build"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/convert"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/create"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/doctor"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/explain"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/reorder"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/test"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/version"
 )
 
@@ -34,18 +40,30 @@ func NewDefaultCommand() *cobra.Command {
 Manages declarative configuration of Kubernetes.
 See https://sigs.k8s.io/kustomize
 `,
+		// Errors are written by WriteError, per --error-format.
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateFlagErrorFormat()
+		},
 	}
 	uf := kunstruct.NewKunstructuredFactoryImpl()
 	v := validator.NewKustValidator()
 	c.AddCommand(
 		shell_complete.NewCommand(),
+		alpha.NewCmdAlpha(stdOut, fSys),
 		build.NewCmdBuild(stdOut),
 		edit.NewCmdEdit(fSys, v, uf),
+		convert.NewCmdConvert(fSys),
 		create.NewCmdCreate(fSys, uf),
+		doctor.NewCmdDoctor(stdOut, fSys),
+		explain.NewCmdExplain(stdOut),
+		reorder.NewCmdReorder(stdOut, fSys, uf),
+		test.NewCmdTest(stdOut, fSys),
 		version.NewCmdVersion(stdOut),
 	)
 	configcobra.AddCommands(c, "kustomize")
 
+	addFlagErrorFormat(c.PersistentFlags())
 	c.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 
 	// Workaround for this issue:
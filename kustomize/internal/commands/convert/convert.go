@@ -0,0 +1,26 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package convert converts configuration packaged
+// for other tools into kustomizations.
+package convert
+
+import (
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+// NewCmdConvert returns an instance of 'convert' subcommand.
+func NewCmdConvert(fSys filesys.FileSystem) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "convert",
+		Short: "Converts configuration packaged for other tools into kustomizations",
+		Example: `
+	# Converts a helm chart into a base and an overlay
+	kustomize convert helm ./mychart --values prod-values.yaml
+`,
+		Args: cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(newCmdConvertHelm(fSys))
+	return c
+}
@@ -0,0 +1,370 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/krmfile"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const (
+	baseDir    = "base"
+	overlayDir = "overlays/default"
+)
+
+// helmLabels are added by charts to tell that helm manages
+// a resource, which stops being true once it's converted.
+var helmLabels = []string{
+	"helm.sh/chart",
+	"app.kubernetes.io/managed-by",
+	"heritage",
+	"chart",
+}
+
+type helmOptions struct {
+	chart     string
+	name      string
+	namespace string
+	values    []string
+	set       []string
+	output    string
+	helm      string
+	// render returns the output of "helm template"
+	// called with the arguments.
+	render func(args []string) ([]byte, error)
+}
+
+func newCmdConvertHelm(fSys filesys.FileSystem) *cobra.Command {
+	var o helmOptions
+	o.render = o.renderWithHelmExec
+	cmd := &cobra.Command{
+		Use:   "helm {chart}",
+		Short: "Converts a helm chart into a base and an overlay",
+		Long: `Renders a helm chart with 'helm template' and the given values, and
+writes the result as a kustomization:
+
+  OUTPUT/` + baseDir + `/             one file per resource
+  OUTPUT/` + baseDir + `/` + krmfile.KrmfileName + `     setters for the replicas and images found
+  OUTPUT/` + overlayDir + `/ an overlay of the base, in the release namespace
+
+Labels saying that helm manages the resources are dropped.  The setters
+may be overridden with 'kustomize build OUTPUT/` + baseDir + ` --set NAME=VALUE'.
+`,
+		Example: `
+	# Converts the chart in ./mychart, rendered with prod values
+	kustomize convert helm ./mychart --values prod-values.yaml --namespace prod
+
+	# Converts a chart from a repository
+	kustomize convert helm stable/minecraft --name mc --output minecraft
+`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.chart = args[0]
+			return o.run(fSys)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.name, "name", "",
+		"The release name to render the chart with.  Defaults to the base name of the chart.")
+	cmd.Flags().StringVar(
+		&o.namespace, "namespace", "",
+		"The release namespace, set in the overlay rather than in the base.")
+	cmd.Flags().StringArrayVarP(
+		&o.values, "values", "f", []string{},
+		"Values files to render the chart with.")
+	cmd.Flags().StringArrayVar(
+		&o.set, "set", []string{},
+		"Values, as key=value, to render the chart with.")
+	cmd.Flags().StringVarP(
+		&o.output, "output", "o", "",
+		"The directory to write to, which mustn't exist.  Defaults to the release name.")
+	cmd.Flags().StringVar(
+		&o.helm, "helm-command", "helm",
+		"The helm program to render the chart with.")
+	return cmd
+}
+
+func (o *helmOptions) renderWithHelmExec(args []string) ([]byte, error) {
+	helmProgram, err := exec.LookPath(o.helm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no '%s' program on path", o.helm)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(helmProgram, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "trouble rendering chart %s: %s", o.chart, stderr.String())
+	}
+	return out, nil
+}
+
+// templateArgs returns the arguments of "helm template".
+func (o *helmOptions) templateArgs() []string {
+	args := []string{"template", o.name, o.chart, "--include-crds"}
+	if o.namespace != "" {
+		args = append(args, "--namespace", o.namespace)
+	}
+	for _, v := range o.values {
+		args = append(args, "--values", v)
+	}
+	for _, s := range o.set {
+		args = append(args, "--set", s)
+	}
+	return args
+}
+
+func (o *helmOptions) run(fSys filesys.FileSystem) error {
+	if o.name == "" {
+		o.name = strings.TrimSuffix(filepath.Base(o.chart), ".tgz")
+	}
+	if o.output == "" {
+		o.output = o.name
+	}
+	if fSys.Exists(o.output) {
+		return fmt.Errorf("%s already exists", o.output)
+	}
+	out, err := o.render(o.templateArgs())
+	if err != nil {
+		return err
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(out),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return errors.Wrapf(err, "reading the rendered chart %s", o.chart)
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("chart %s renders no resources", o.chart)
+	}
+	s := newSetters()
+	files := make(map[string]*yaml.RNode)
+	var names []string
+	for _, n := range nodes {
+		if err = o.convertResource(n, s); err != nil {
+			return err
+		}
+		name, err := resourceFileName(n, files)
+		if err != nil {
+			return err
+		}
+		files[name] = n
+		names = append(names, name)
+	}
+
+	base := filepath.Join(o.output, baseDir)
+	if err = fSys.MkdirAll(base); err != nil {
+		return err
+	}
+	for _, name := range names {
+		content, err := files[name].String()
+		if err != nil {
+			return err
+		}
+		if err = fSys.WriteFile(filepath.Join(base, name), []byte(content)); err != nil {
+			return err
+		}
+	}
+	if err = writeKustomization(fSys, base, types.Kustomization{Resources: names}); err != nil {
+		return err
+	}
+	if len(s.values) > 0 {
+		content, err := s.krmfile()
+		if err != nil {
+			return err
+		}
+		err = fSys.WriteFile(filepath.Join(base, krmfile.KrmfileName), content)
+		if err != nil {
+			return err
+		}
+	}
+
+	overlay := filepath.Join(o.output, overlayDir)
+	if err = fSys.MkdirAll(overlay); err != nil {
+		return err
+	}
+	return writeKustomization(fSys, overlay, types.Kustomization{
+		Namespace: o.namespace,
+		Resources: []string{"../../" + baseDir},
+	})
+}
+
+// convertResource drops what ties the resource to the
+// release, and turns its replicas and images into setters.
+func (o *helmOptions) convertResource(n *yaml.RNode, s *setters) error {
+	// Drop the "# Source:" comment naming the template.
+	n.YNode().HeadComment = ""
+	if c := n.YNode().Content; len(c) > 0 {
+		c[0].HeadComment = ""
+	}
+	for _, path := range [][]string{
+		{yaml.MetadataField},
+		{"spec", "template", yaml.MetadataField},
+	} {
+		meta, err := n.Pipe(yaml.Lookup(path...))
+		if err != nil {
+			return err
+		}
+		if meta == nil {
+			continue
+		}
+		labels, err := meta.Pipe(yaml.Lookup(yaml.LabelsField))
+		if err != nil {
+			return err
+		}
+		if labels == nil {
+			continue
+		}
+		for _, l := range helmLabels {
+			if _, err = labels.Pipe(yaml.Clear(l)); err != nil {
+				return err
+			}
+		}
+		if len(labels.Content()) == 0 {
+			if _, err = meta.Pipe(yaml.Clear(yaml.LabelsField)); err != nil {
+				return err
+			}
+		}
+	}
+	meta, err := n.GetMeta()
+	if err != nil {
+		return err
+	}
+	if o.namespace != "" && meta.Namespace == o.namespace {
+		if _, err = n.Pipe(yaml.Lookup(yaml.MetadataField), yaml.Clear("namespace")); err != nil {
+			return err
+		}
+	}
+	replicas, err := n.Pipe(yaml.Lookup("spec", "replicas"))
+	if err != nil {
+		return err
+	}
+	if replicas != nil && replicas.YNode().Kind == yaml.ScalarNode {
+		s.mark(replicas, meta.Name+"-replicas")
+	}
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, err := n.Pipe(yaml.Lookup("spec", "template", "spec", field))
+		if err != nil {
+			return err
+		}
+		if containers == nil {
+			continue
+		}
+		err = containers.VisitElements(func(c *yaml.RNode) error {
+			image, err := c.Pipe(yaml.Lookup("image"))
+			if err != nil || image == nil {
+				return err
+			}
+			name, err := c.Pipe(yaml.Lookup("name"))
+			if err != nil || name == nil {
+				return err
+			}
+			s.mark(image, yaml.GetValue(name)+"-image")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// resourceFileName returns a name for the file holding
+// the resource that isn't among the names taken.
+func resourceFileName(n *yaml.RNode, taken map[string]*yaml.RNode) (string, error) {
+	meta, err := n.GetMeta()
+	if err != nil {
+		return "", err
+	}
+	stem := unsafeNameChars.ReplaceAllString(
+		strings.ToLower(meta.Kind+"_"+meta.Name), "_")
+	name := stem + ".yaml"
+	for i := 2; taken[name] != nil; i++ {
+		name = fmt.Sprintf("%s_%d.yaml", stem, i)
+	}
+	return name, nil
+}
+
+func writeKustomization(fSys filesys.FileSystem, dir string, k types.Kustomization) error {
+	k.FixKustomizationPostUnmarshalling()
+	content, err := k8syaml.Marshal(k)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(
+		filepath.Join(dir, konfig.DefaultKustomizationFileName()), content)
+}
+
+// setters are the setters found in a chart, by name.
+type setters struct {
+	values map[string]string
+}
+
+func newSetters() *setters {
+	return &setters{values: make(map[string]string)}
+}
+
+// mark makes the field a reference to a setter holding
+// its value, named after the given name.  Fields with
+// the same name and value share a setter.
+func (s *setters) mark(field *yaml.RNode, name string) {
+	value := yaml.GetValue(field)
+	name = unsafeNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	unique := name
+	for i := 2; ; i++ {
+		if v, ok := s.values[unique]; !ok || v == value {
+			break
+		}
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	s.values[unique] = value
+	field.YNode().LineComment = fmt.Sprintf(`{"$openapi":"%s"}`, unique)
+}
+
+// krmfile returns a Krmfile declaring the setters.
+func (s *setters) krmfile() ([]byte, error) {
+	var names []string
+	for name := range s.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	definitions := make(map[string]interface{})
+	for _, name := range names {
+		definitions["io.k8s.cli.setters."+name] = map[string]interface{}{
+			"x-k8s-cli": map[string]interface{}{
+				"setter": map[string]interface{}{
+					"name":  name,
+					"value": s.values[name],
+				},
+			},
+		}
+	}
+	return k8syaml.Marshal(map[string]interface{}{
+		"apiVersion": "config.k8s.io/v1alpha1",
+		"kind":       krmfile.KrmfileName,
+		"openAPI": map[string]interface{}{
+			"definitions": definitions,
+		},
+	})
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package convert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+const renderedChart = `---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: shop-web
+  namespace: prod
+  labels:
+    app.kubernetes.io/name: web
+    app.kubernetes.io/managed-by: Helm
+    helm.sh/chart: web-0.1.0
+spec:
+  ports:
+  - port: 80
+---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: shop-web
+  namespace: prod
+  labels:
+    helm.sh/chart: web-0.1.0
+spec:
+  replicas: 2
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19
+      - name: sidecar
+        image: envoy:1.16
+---
+# Source: web/templates/worker.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: shop-worker
+  namespace: prod
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19
+`
+
+func TestConvertHelm(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	var args []string
+	o := helmOptions{
+		chart:     "charts/web",
+		name:      "shop",
+		namespace: "prod",
+		values:    []string{"prod.yaml"},
+		set:       []string{"replicas=2"},
+		render: func(a []string) ([]byte, error) {
+			args = a
+			return []byte(renderedChart), nil
+		},
+	}
+	if err := o.run(fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedArgs := []string{
+		"template", "shop", "charts/web", "--include-crds",
+		"--namespace", "prod", "--values", "prod.yaml", "--set", "replicas=2"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, args)
+	}
+	for path, expected := range map[string]string{
+		"shop/base/kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- service_shop-web.yaml
+- deployment_shop-web.yaml
+- deployment_shop-worker.yaml
+`,
+		"shop/base/service_shop-web.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: shop-web
+  labels:
+    app.kubernetes.io/name: web
+spec:
+  ports:
+  - port: 80
+`,
+		"shop/base/deployment_shop-web.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: shop-web
+spec:
+  replicas: 2 # {"$openapi":"shop-web-replicas"}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: web
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19 # {"$openapi":"web-image"}
+      - name: sidecar
+        image: envoy:1.16 # {"$openapi":"sidecar-image"}
+`,
+		"shop/base/deployment_shop-worker.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: shop-worker
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19 # {"$openapi":"web-image"}
+`,
+		"shop/base/Krmfile": `apiVersion: config.k8s.io/v1alpha1
+kind: Krmfile
+openAPI:
+  definitions:
+    io.k8s.cli.setters.shop-web-replicas:
+      x-k8s-cli:
+        setter:
+          name: shop-web-replicas
+          value: "2"
+    io.k8s.cli.setters.sidecar-image:
+      x-k8s-cli:
+        setter:
+          name: sidecar-image
+          value: envoy:1.16
+    io.k8s.cli.setters.web-image:
+      x-k8s-cli:
+        setter:
+          name: web-image
+          value: nginx:1.19
+`,
+		"shop/overlays/default/kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: prod
+resources:
+- ../../base
+`,
+	} {
+		b, err := fSys.ReadFile(path)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if string(b) != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", path, expected, b)
+		}
+	}
+
+	err := o.run(fSys)
+	if err == nil || err.Error() != "shop already exists" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSettersMark(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	o := helmOptions{
+		chart: "web",
+		render: func([]string) ([]byte, error) {
+			return []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.19
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.20
+`), nil
+		},
+	}
+	if err := o.run(fSys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := fSys.ReadFile("web/base/deployment_b.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(b), `image: nginx:1.20 # {"$openapi":"web-image-2"}`) {
+		t.Errorf("expected a second setter, got\n%s", b)
+	}
+}
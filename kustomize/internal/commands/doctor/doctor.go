@@ -0,0 +1,350 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package doctor checks the environment kustomize
+// runs in, e.g. for problems with plugins.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+)
+
+// commandTimeout bounds the time taken by each
+// external program run to get its version.
+const commandTimeout = 10 * time.Second
+
+type status string
+
+const (
+	statusOk   status = "OK"
+	statusWarn status = "WARN"
+	statusFail status = "FAIL"
+)
+
+// finding is the result of one check.
+type finding struct {
+	status  status
+	message string
+	// hint says what to do about a problem.
+	hint string
+}
+
+// environment holds the means of inspecting the
+// machine, so that tests can replace them.
+type environment struct {
+	fSys     filesys.FileSystem
+	lookPath func(string) (string, error)
+	// run runs a program, returning its trimmed output.
+	run func(name string, args ...string) (string, error)
+}
+
+func makeEnvironment(fSys filesys.FileSystem) *environment {
+	return &environment{
+		fSys:     fSys,
+		lookPath: exec.LookPath,
+		run:      runCommand,
+	}
+}
+
+func runCommand(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// NewCmdDoctor returns a new doctor command.
+func NewCmdDoctor(out io.Writer, fSys filesys.FileSystem) *cobra.Command {
+	var listPlugins bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Checks the environment for problems with plugins and remote bases",
+		Long: `Checks the plugin directories and the plugins in them, and the
+programs that some features need: a container runtime for function
+plugins, helm for plugins that inflate charts, and git for remote bases.
+
+Plugins are searched for in these directories, and taken from the
+first one holding them:
+
+  $KUSTOMIZE_PLUGIN_HOME
+  ./kustomize-plugins, below the working directory
+  $XDG_CONFIG_HOME/kustomize/plugin (default ~/.config/kustomize/plugin)
+  $XDG_DATA_HOME/kustomize/plugin (default ~/.local/share/kustomize/plugin)
+  each of $XDG_DATA_DIRS, followed by kustomize/plugin
+  (default /usr/local/share and /usr/share)
+  ~/kustomize/plugin
+
+Exits with an error if a problem that will break builds is found.
+`,
+		Example: `
+	kustomize doctor
+
+	# Lists the plugins found, and where
+	kustomize doctor --list-plugins
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			e := makeEnvironment(fSys)
+			if listPlugins {
+				return e.listPlugins(out)
+			}
+			return report(out, e.check())
+		},
+	}
+	cmd.Flags().BoolVar(
+		&listPlugins, "list-plugins", false,
+		"List the plugins found in the plugin directories, in order of precedence, "+
+			"instead of checking the environment.")
+	return cmd
+}
+
+func report(out io.Writer, findings []finding) error {
+	failures := 0
+	for _, f := range findings {
+		fmt.Fprintf(out, "%-6s %s\n", "["+string(f.status)+"]", f.message)
+		if f.hint != "" {
+			fmt.Fprintf(out, "%-6s %s\n", "", f.hint)
+		}
+		if f.status == statusFail {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("found %d problem(s)", failures)
+	}
+	return nil
+}
+
+func (e *environment) check() []finding {
+	var result []finding
+	result = append(result, e.checkPluginHome()...)
+	result = append(result, e.checkContainerRuntime())
+	result = append(result, e.checkHelm())
+	result = append(result, e.checkGit())
+	return result
+}
+
+func (e *environment) checkPluginHome() []finding {
+	var result []finding
+	if h := os.Getenv(konfig.KustomizePluginHomeEnv); h != "" && !e.fSys.IsDir(h) {
+		result = append(result, finding{
+			status:  statusFail,
+			message: fmt.Sprintf("$%s is %s, which isn't a directory", konfig.KustomizePluginHomeEnv, h),
+			hint:    "create the directory, or unset the variable",
+		})
+	}
+	homes := konfig.PluginHomes(e.fSys)
+	if len(homes) == 0 {
+		_, err := konfig.DefaultAbsPluginHome(e.fSys)
+		return append(result, finding{
+			status:  statusWarn,
+			message: "no plugin home directory: " + err.Error(),
+			hint: fmt.Sprintf(
+				"only builtin plugins can be used; to add others, create $%s/%s/%s",
+				konfig.XdgConfigHomeEnv, konfig.ProgramName, konfig.RelPluginHome),
+		})
+	}
+	result = append(result, finding{
+		status:  statusOk,
+		message: "plugin home directory: " + homes[0],
+	})
+	for _, home := range homes[1:] {
+		result = append(result, finding{
+			status:  statusOk,
+			message: "plugin search directory: " + home,
+		})
+	}
+	for _, home := range homes {
+		result = append(result, e.checkPlugins(home)...)
+	}
+	return result
+}
+
+// pluginFile is a file in a plugin directory
+// that kustomize would load as a plugin.
+type pluginFile struct {
+	path string
+	// dir is the directory of the plugin,
+	// relative to the plugin directory.
+	dir  string
+	kind string
+	info os.FileInfo
+}
+
+// findPlugins returns the files in the plugin directory
+// that kustomize would load as plugins, i.e. those named
+// like their directory, apart from case and a ".so",
+// ".rpc" or ".wasm" suffix.
+func (e *environment) findPlugins(home string) ([]pluginFile, error) {
+	var result []pluginFile
+	err := e.fSys.Walk(home, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		kind := info.Name()
+		for _, suffix := range []string{
+			".so", konfig.RpcPluginSuffix, konfig.WasmPluginSuffix} {
+			kind = strings.TrimSuffix(kind, suffix)
+		}
+		dir := filepath.Dir(path)
+		if strings.ToLower(kind) != filepath.Base(dir) {
+			return nil
+		}
+		rel, err := filepath.Rel(home, dir)
+		if err != nil {
+			return err
+		}
+		result = append(result, pluginFile{
+			path: path, dir: rel, kind: kind, info: info})
+		return nil
+	})
+	return result, err
+}
+
+// checkPlugins checks the location and mode
+// of the plugins in the plugin directory.
+func (e *environment) checkPlugins(home string) []finding {
+	plugins, err := e.findPlugins(home)
+	if err != nil {
+		return []finding{{
+			status:  statusFail,
+			message: "unable to read plugin home directory: " + err.Error(),
+		}}
+	}
+	var result []finding
+	for _, p := range plugins {
+		// Either {version}/{kind} or {group}/{version}/{kind}.
+		if n := len(strings.Split(p.dir, string(filepath.Separator))); n < 2 || n > 3 {
+			result = append(result, finding{
+				status:  statusWarn,
+				message: "plugin " + p.path + " is in an unexpected directory",
+				hint: "plugins must be at {home}/{group}/{version}/{lowercase kind}/{kind}" +
+					" (the group may be omitted)",
+			})
+		}
+		if !strings.HasSuffix(p.path, ".so") &&
+			!strings.HasSuffix(p.path, konfig.WasmPluginSuffix) &&
+			p.info.Mode()&0111 == 0 {
+			result = append(result, finding{
+				status:  statusFail,
+				message: "exec plugin " + p.path + " isn't executable",
+				hint:    "run: chmod +x " + p.path,
+			})
+		}
+	}
+	result = append(result, finding{
+		status:  statusOk,
+		message: fmt.Sprintf("found %d plugin(s)", len(plugins)),
+	})
+	return result
+}
+
+// listPlugins writes the plugins found in the plugin
+// directories, in order of precedence, noting those
+// shadowed by a plugin of the same kind found earlier.
+func (e *environment) listPlugins(out io.Writer) error {
+	homes := konfig.PluginHomes(e.fSys)
+	if len(homes) == 0 {
+		_, err := konfig.DefaultAbsPluginHome(e.fSys)
+		return err
+	}
+	found := make(map[string]string)
+	for _, home := range homes {
+		plugins, err := e.findPlugins(home)
+		if err != nil {
+			return err
+		}
+		for _, p := range plugins {
+			name := filepath.ToSlash(filepath.Join(filepath.Dir(p.dir), p.kind))
+			if earlier, ok := found[p.dir]; ok && earlier != home {
+				fmt.Fprintf(out, "%s\t%s\t(shadowed by %s)\n", name, p.path, earlier)
+				continue
+			}
+			found[p.dir] = home
+			fmt.Fprintf(out, "%s\t%s\n", name, p.path)
+		}
+	}
+	return nil
+}
+
+func (e *environment) checkContainerRuntime() finding {
+	const program = "docker"
+	if _, err := e.lookPath(program); err != nil {
+		return finding{
+			status:  statusWarn,
+			message: program + " not found",
+			hint:    "function plugins run in containers, and need " + program,
+		}
+	}
+	v, err := e.run(program, "version", "--format", "{{.Server.Version}}")
+	if err != nil {
+		return finding{
+			status:  statusWarn,
+			message: program + " is installed, but the daemon can't be reached: " + firstLine(v, err),
+			hint:    "start the " + program + " daemon, or check your permissions to use it",
+		}
+	}
+	return finding{status: statusOk, message: program + " server version " + v}
+}
+
+func (e *environment) checkHelm() finding {
+	const program = "helm"
+	if _, err := e.lookPath(program); err != nil {
+		return finding{
+			status:  statusWarn,
+			message: program + " not found",
+			hint:    "plugins that inflate helm charts need " + program,
+		}
+	}
+	v, err := e.run(program, "version", "--short")
+	if err != nil {
+		return finding{
+			status:  statusWarn,
+			message: program + " is installed, but its version is unknown: " + firstLine(v, err),
+		}
+	}
+	return finding{status: statusOk, message: program + " version " + v}
+}
+
+func (e *environment) checkGit() finding {
+	const program = "git"
+	if _, err := e.lookPath(program); err != nil {
+		return finding{
+			status:  statusWarn,
+			message: program + " not found",
+			hint:    "remote bases (e.g. github.com/org/repo//dir) are cloned with " + program,
+		}
+	}
+	v, err := e.run(program, "version")
+	if err != nil {
+		return finding{
+			status:  statusWarn,
+			message: program + " is installed, but doesn't run: " + firstLine(v, err),
+		}
+	}
+	return finding{status: statusOk, message: v}
+}
+
+// firstLine returns the first line of the output
+// of a failed command, or else the error.
+func firstLine(out string, err error) string {
+	if out == "" {
+		return err.Error()
+	}
+	return strings.SplitN(out, "\n", 2)[0]
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+)
+
+func makeTestEnvironment(installed map[string]string) *environment {
+	return &environment{
+		fSys: filesys.MakeFsOnDisk(),
+		lookPath: func(name string) (string, error) {
+			if _, ok := installed[name]; ok {
+				return "/usr/bin/" + name, nil
+			}
+			return "", fmt.Errorf("%s not in PATH", name)
+		},
+		run: func(name string, args ...string) (string, error) {
+			return installed[name], nil
+		},
+	}
+}
+
+func setPluginHome(t *testing.T, dir string) {
+	old, wasSet := os.LookupEnv(konfig.KustomizePluginHomeEnv)
+	os.Setenv(konfig.KustomizePluginHomeEnv, dir)
+	t.Cleanup(func() {
+		if wasSet {
+			os.Setenv(konfig.KustomizePluginHomeEnv, old)
+		} else {
+			os.Unsetenv(konfig.KustomizePluginHomeEnv)
+		}
+	})
+}
+
+func writePlugin(t *testing.T, path string, mode os.FileMode) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	home, err := ioutil.TempDir("", "kustomize-doctor-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	setPluginHome(t, home)
+	writePlugin(t, filepath.Join(home, "someteam.example.com/v1/good/Good"), 0755)
+	writePlugin(t, filepath.Join(home, "someteam.example.com/v1/bad/Bad"), 0644)
+	writePlugin(t, filepath.Join(home, "someteam.example.com/v1/bad/README.md"), 0644)
+	writePlugin(t, filepath.Join(home, "misplaced/Misplaced"), 0755)
+
+	var out bytes.Buffer
+	err = report(&out, makeTestEnvironment(map[string]string{
+		"git":  "git version 2.28.0",
+		"helm": "v3.3.0+g8a4aeec",
+	}).check())
+	if err == nil || err.Error() != "found 1 problem(s)" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"[OK]   plugin home directory: " + home + "\n",
+		"[FAIL] exec plugin " + filepath.Join(home, "someteam.example.com/v1/bad/Bad") +
+			" isn't executable\n       run: chmod +x ",
+		"[WARN] plugin " + filepath.Join(home, "misplaced/Misplaced") + " is in an unexpected directory\n",
+		"[OK]   found 3 plugin(s)\n",
+		"[WARN] docker not found\n",
+		"[OK]   helm version v3.3.0+g8a4aeec\n",
+		"[OK]   git version 2.28.0\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestDoctorMissingPluginHome(t *testing.T) {
+	setPluginHome(t, "/no/such/dir")
+	findings := makeTestEnvironment(nil).checkPluginHome()
+	if len(findings) == 0 || findings[0].status != statusFail {
+		t.Fatalf("expected a failure, got %v", findings)
+	}
+}
+
+func TestListPlugins(t *testing.T) {
+	home, err := ioutil.TempDir("", "kustomize-doctor-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	setPluginHome(t, filepath.Join(home, "first"))
+	old, wasSet := os.LookupEnv(konfig.XdgDataHomeEnv)
+	os.Setenv(konfig.XdgDataHomeEnv, filepath.Join(home, "data"))
+	defer func() {
+		if wasSet {
+			os.Setenv(konfig.XdgDataHomeEnv, old)
+		} else {
+			os.Unsetenv(konfig.XdgDataHomeEnv)
+		}
+	}()
+	second := filepath.Join(home, "data", konfig.ProgramName, konfig.RelPluginHome)
+	writePlugin(t, filepath.Join(home, "first/someteam.example.com/v1/gen/Gen"), 0755)
+	writePlugin(t, filepath.Join(second, "someteam.example.com/v1/gen/Gen"), 0755)
+	writePlugin(t, filepath.Join(second, "v1/other/Other.rpc"), 0755)
+	writePlugin(t, filepath.Join(second, "v1/portable/Portable.wasm"), 0644)
+
+	var out bytes.Buffer
+	if err = makeTestEnvironment(nil).listPlugins(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "someteam.example.com/v1/Gen\t" +
+		filepath.Join(home, "first/someteam.example.com/v1/gen/Gen") + "\n" +
+		"someteam.example.com/v1/Gen\t" +
+		filepath.Join(second, "someteam.example.com/v1/gen/Gen") +
+		"\t(shadowed by " + filepath.Join(home, "first") + ")\n" +
+		"v1/Other\t" + filepath.Join(second, "v1/other/Other.rpc") + "\n" +
+		"v1/Portable\t" + filepath.Join(second, "v1/portable/Portable.wasm") + "\n"
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
@@ -10,22 +10,41 @@ import (
 
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/loader"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 )
 
 type addBaseOptions struct {
 	baseDirectoryPaths string
+	// pin remote bases to the commits their refs name.
+	pin bool
+	// keepTags leaves remote refs that are tags unpinned.
+	keepTags bool
+	// pinUrl returns the pinned url of a remote base.
+	pinUrl func(url string, keepTags bool) (string, error)
 }
 
 // newCmdAddBase adds the file path of the kustomize base to the kustomization file.
 func newCmdAddBase(fSys filesys.FileSystem) *cobra.Command {
-	var o addBaseOptions
+	o := addBaseOptions{pinUrl: loader.PinRemoteBase}
 
 	cmd := &cobra.Command{
 		Use:   "base",
 		Short: "Adds one or more bases to the kustomization.yaml in current directory",
+		Long: `Adds one or more bases to the kustomization.yaml in current directory.
+
+A base may be a directory, or the url of a directory in a git repository.
+The ref of a remote base (by default master) is checked to exist, and
+replaced by the commit it names, so that builds are reproducible.
+`,
 		Example: `
-		add base {filepath1},{filepath2}`,
+		add base {filepath1},{filepath2}
+
+		# Adds github.com/org/repo//base?ref={commit of the release branch}
+		add base github.com/org/repo//base?ref=release
+
+		# Adds github.com/org/repo//base?ref=v1.0.0 if v1.0.0 is a tag
+		add base github.com/org/repo//base?ref=v1.0.0 --keep-tags`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := o.Validate(args)
 			if err != nil {
@@ -34,6 +53,12 @@ func newCmdAddBase(fSys filesys.FileSystem) *cobra.Command {
 			return o.RunAddBase(fSys)
 		},
 	}
+	cmd.Flags().BoolVar(
+		&o.pin, "pin", true,
+		"Replace the ref of a remote base by the commit it names.")
+	cmd.Flags().BoolVar(
+		&o.keepTags, "keep-tags", false,
+		"Don't pin the ref of a remote base if it's a tag.")
 	return cmd
 }
 
@@ -62,7 +87,18 @@ func (o *addBaseOptions) RunAddBase(fSys filesys.FileSystem) error {
 	paths := strings.Split(o.baseDirectoryPaths, ",")
 	for _, path := range paths {
 		if !fSys.Exists(path) {
-			return errors.New(path + " does not exist")
+			if !loader.IsRemoteBase(path) {
+				return errors.New(path + " does not exist")
+			}
+			if kustfile.StringInSlice(path, m.Resources) {
+				return fmt.Errorf("base %s already in kustomization file", path)
+			}
+			if o.pin {
+				path, err = o.pinUrl(path, o.keepTags)
+				if err != nil {
+					return err
+				}
+			}
 		}
 		if kustfile.StringInSlice(path, m.Resources) {
 			return fmt.Errorf("base %s already in kustomization file", path)
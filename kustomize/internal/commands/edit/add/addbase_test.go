@@ -84,3 +84,58 @@ func TestAddBaseNoArgs(t *testing.T) {
 		t.Errorf("incorrect error: %v", err.Error())
 	}
 }
+
+func TestAddBaseRemote(t *testing.T) {
+	const sha = "0123456789012345678901234567890123456789"
+	testCases := map[string]struct {
+		pin      bool
+		keepTags bool
+		url      string
+		expected string
+	}{
+		"pinned": {
+			pin:      true,
+			url:      "github.com/org/repo//base?ref=release",
+			expected: "github.com/org/repo//base?ref=" + sha,
+		},
+		"tagKept": {
+			pin:      true,
+			keepTags: true,
+			url:      "github.com/org/repo//base?ref=v1.0.0",
+			expected: "github.com/org/repo//base?ref=v1.0.0",
+		},
+		"notPinned": {
+			url:      "github.com/org/repo//base?ref=release",
+			expected: "github.com/org/repo//base?ref=release",
+		},
+	}
+	for n, tc := range testCases {
+		fSys := filesys.MakeFsInMemory()
+		testutils_test.WriteTestKustomization(fSys)
+		o := addBaseOptions{
+			baseDirectoryPaths: tc.url,
+			pin:                tc.pin,
+			keepTags:           tc.keepTags,
+			pinUrl: func(url string, keepTags bool) (string, error) {
+				if keepTags && strings.HasSuffix(url, "v1.0.0") {
+					return url, nil
+				}
+				return strings.Split(url, "?")[0] + "?ref=" + sha, nil
+			},
+		}
+		if err := o.RunAddBase(fSys); err != nil {
+			t.Fatalf("%s: unexpected cmd error: %v", n, err)
+		}
+		content, err := testutils_test.ReadTestKustomization(fSys)
+		if err != nil {
+			t.Fatalf("%s: unexpected read error: %v", n, err)
+		}
+		if !strings.Contains(string(content), "- "+tc.expected+"\n") {
+			t.Errorf("%s: expected %s in kustomization:\n%s", n, tc.expected, content)
+		}
+		if err = o.RunAddBase(fSys); err == nil ||
+			!strings.Contains(err.Error(), "already in kustomization file") {
+			t.Errorf("%s: expected already there problem, got %v", n, err)
+		}
+	}
+}
@@ -30,6 +30,9 @@ func newCmdAddConfigMap(
 
 	# Adds a configmap from env-file
 	kustomize edit add configmap my-configmap --from-env-file=env/path.env
+
+	# Updates the value of a key of an existing configmap, merging with the base
+	kustomize edit add configmap my-configmap --from-literal=my-literal=67890 --behavior=merge
 `,
 		RunE: func(_ *cobra.Command, args []string) error {
 			err := flags.ExpandFileSource(fSys)
@@ -81,6 +84,12 @@ func newCmdAddConfigMap(
 		"from-env-file",
 		"",
 		"Specify the path to a file to read lines of key=val pairs to create a configmap (i.e. a Docker .env file).")
+	cmd.Flags().StringVar(
+		&flags.Behavior,
+		"behavior",
+		"",
+		"Specify the behavior of the generated configmap with respect to one of the same name in a base: "+
+			"create, merge or replace.")
 
 	return cmd
 }
@@ -93,7 +102,7 @@ func addConfigMap(
 	k *types.Kustomization,
 	flags flagsAndArgs, kf ifc.KunstructuredFactory) error {
 	args := findOrMakeConfigMapArgs(k, flags.Name)
-	mergeFlagsIntoCmArgs(args, flags)
+	mergeFlagsIntoGeneratorArgs(&args.GeneratorArgs, flags)
 	// Validate by trying to create corev1.configmap.
 	args.Options = types.MergeGlobalOptionsIntoLocal(
 		args.Options, k.GeneratorOptions)
@@ -112,18 +121,3 @@ func findOrMakeConfigMapArgs(m *types.Kustomization, name string) *types.ConfigM
 	m.ConfigMapGenerator = append(m.ConfigMapGenerator, *cm)
 	return &m.ConfigMapGenerator[len(m.ConfigMapGenerator)-1]
 }
-
-func mergeFlagsIntoCmArgs(args *types.ConfigMapArgs, flags flagsAndArgs) {
-	if len(flags.LiteralSources) > 0 {
-		args.LiteralSources = append(
-			args.LiteralSources, flags.LiteralSources...)
-	}
-	if len(flags.FileSources) > 0 {
-		args.FileSources = append(
-			args.FileSources, flags.FileSources...)
-	}
-	if flags.EnvFileSource != "" {
-		args.EnvSources = append(
-			args.EnvSources, flags.EnvFileSource)
-	}
-}
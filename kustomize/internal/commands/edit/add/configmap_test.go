@@ -4,13 +4,17 @@
 package add
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/api/kv"
 	"sigs.k8s.io/kustomize/api/loader"
 	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
 	"sigs.k8s.io/kustomize/api/types"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
 )
 
 func TestNewAddConfigMapIsNotNil(t *testing.T) {
@@ -25,6 +29,32 @@ func TestNewAddConfigMapIsNotNil(t *testing.T) {
 	}
 }
 
+func TestAddConfigMapWithBehavior(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomization(fSys)
+	cmd := newCmdAddConfigMap(
+		fSys,
+		kv.NewLoader(
+			loader.NewFileLoaderAtCwd(fSys),
+			valtest_test.MakeFakeValidator()),
+		kunstruct.NewKunstructuredFactoryImpl())
+	cmd.SetArgs([]string{
+		"my-configmap", "--from-literal=my-literal=67890", "--behavior=merge"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"name: my-configmap", "- my-literal=67890", "behavior: merge"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected %q in %s", expected, content)
+		}
+	}
+}
+
 func TestMakeConfigMapArgs(t *testing.T) {
 	cmName := "test-config-name"
 
@@ -110,3 +140,36 @@ func TestMergeFlagsIntoConfigMapArgs_EnvSource(t *testing.T) {
 		t.Fatalf("expected env2")
 	}
 }
+
+func TestMergeFlagsIntoConfigMapArgs_UpdatesExistingKeys(t *testing.T) {
+	k := &types.Kustomization{}
+	args := findOrMakeConfigMapArgs(k, "foo")
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{
+			LiteralSources: []string{"k1=v1", "k2=v2"},
+			FileSources:    []string{"dir/file1", "key=file2"},
+			EnvFileSource:  "env1",
+		})
+	mergeFlagsIntoGeneratorArgs(
+		&args.GeneratorArgs,
+		flagsAndArgs{
+			LiteralSources: []string{"k1=new", "k3=v3"},
+			FileSources:    []string{"other/file1", "key=file3"},
+			EnvFileSource:  "env1",
+			Behavior:       "merge",
+		})
+	cm := k.ConfigMapGenerator[0]
+	if !reflect.DeepEqual(cm.LiteralSources, []string{"k1=new", "k2=v2", "k3=v3"}) {
+		t.Errorf("unexpected literals %v", cm.LiteralSources)
+	}
+	if !reflect.DeepEqual(cm.FileSources, []string{"other/file1", "key=file3"}) {
+		t.Errorf("unexpected files %v", cm.FileSources)
+	}
+	if !reflect.DeepEqual(cm.EnvSources, []string{"env1"}) {
+		t.Errorf("unexpected envs %v", cm.EnvSources)
+	}
+	if cm.Behavior != "merge" {
+		t.Errorf("unexpected behavior %q", cm.Behavior)
+	}
+}
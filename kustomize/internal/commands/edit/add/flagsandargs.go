@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/util"
 )
 
@@ -26,6 +27,19 @@ type flagsAndArgs struct {
 	Type string
 	// Namespace of secret
 	Namespace string
+	// Behavior of the generated configMap/Secret with
+	// respect to one of the same name in a base.
+	Behavior string
+	// SopsFileSource is a sops encrypted file to derive
+	// the Secret from at build time (optional)
+	SopsFileSource string
+	// SopsKeys are the keys of the decrypted data to use
+	SopsKeys []string
+	// SopsProvider is the provider of a key the file
+	// must be decryptable with
+	SopsProvider string
+	// SopsApiVersion of the generator plugin decrypting the file
+	SopsApiVersion string
 }
 
 // Validate validates required fields are set to support structured generation.
@@ -34,12 +48,25 @@ func (a *flagsAndArgs) Validate(args []string) error {
 		return fmt.Errorf("name must be specified once")
 	}
 	a.Name = args[0]
+	if len(a.SopsFileSource) > 0 {
+		if len(a.EnvFileSource) > 0 || len(a.FileSources) > 0 || len(a.LiteralSources) > 0 {
+			return fmt.Errorf("from-sops-file cannot be combined with from-env-file, from-file or from-literal")
+		}
+		if a.Behavior != "" {
+			return fmt.Errorf("behavior cannot be set for a secret from-sops-file")
+		}
+		return nil
+	}
 	if len(a.EnvFileSource) == 0 && len(a.FileSources) == 0 && len(a.LiteralSources) == 0 {
 		return fmt.Errorf("at least from-env-file, or from-file or from-literal must be set")
 	}
 	if len(a.EnvFileSource) > 0 && (len(a.FileSources) > 0 || len(a.LiteralSources) > 0) {
 		return fmt.Errorf("from-env-file cannot be combined with from-file or from-literal")
 	}
+	if a.Behavior != "" &&
+		types.NewGenerationBehavior(a.Behavior) == types.BehaviorUnspecified {
+		return fmt.Errorf("behavior must be one of create, merge or replace")
+	}
 	// TODO: Should we check if the path exists? if it's valid, if it's within the same (sub-)directory?
 	return nil
 }
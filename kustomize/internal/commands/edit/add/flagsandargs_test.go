@@ -48,6 +48,19 @@ func TestDataConfigValidation_Flags(t *testing.T) {
 			},
 			shouldFail: true,
 		},
+		{
+			name: "from-sops-file and literal are both set",
+			fa: flagsAndArgs{
+				LiteralSources: []string{"one"},
+				SopsFileSource: "two",
+			},
+			shouldFail: true,
+		},
+		{
+			name:       "we have from-sops-file",
+			fa:         flagsAndArgs{SopsFileSource: "one"},
+			shouldFail: false,
+		},
 		{
 			name:       "we don't have any option set",
 			fa:         flagsAndArgs{},
@@ -61,6 +74,22 @@ func TestDataConfigValidation_Flags(t *testing.T) {
 			},
 			shouldFail: false,
 		},
+		{
+			name: "behavior is unknown",
+			fa: flagsAndArgs{
+				LiteralSources: []string{"one"},
+				Behavior:       "append",
+			},
+			shouldFail: true,
+		},
+		{
+			name: "behavior is merge",
+			fa: flagsAndArgs{
+				LiteralSources: []string{"one"},
+				Behavior:       "merge",
+			},
+			shouldFail: false,
+		},
 	}
 
 	for _, test := range tests {
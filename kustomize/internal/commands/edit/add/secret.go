@@ -4,6 +4,9 @@
 package add
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/api/filesys"
 	"sigs.k8s.io/kustomize/api/ifc"
@@ -30,6 +33,10 @@ func newCmdAddSecret(
 
 	# Adds a secret from env-file
 	kustomize edit add secret my-secret --from-env-file=env/path.env
+
+	# Adds a secret decrypted at build time from a sops encrypted file,
+	# taking two of its keys (needs the SopsEncodedSecrets generator plugin)
+	kustomize edit add secret my-secret --from-sops-file=secrets.enc.yaml --sops-keys=USER,PASSWORD
 `,
 		RunE: func(_ *cobra.Command, args []string) error {
 			err := flags.ExpandFileSource(fSys)
@@ -54,7 +61,11 @@ func newCmdAddSecret(
 			}
 
 			// Add the flagsAndArgs map to the kustomization file.
-			err = addSecret(ldr, kustomization, flags, kf)
+			if flags.SopsFileSource != "" {
+				err = addSopsSecret(fSys, kustomization, flags)
+			} else {
+				err = addSecret(ldr, kustomization, flags, kf)
+			}
 			if err != nil {
 				return err
 			}
@@ -91,6 +102,35 @@ func newCmdAddSecret(
 		"namespace",
 		"",
 		"Specify the namespace of the secret")
+	cmd.Flags().StringVar(
+		&flags.Behavior,
+		"behavior",
+		"",
+		"Specify the behavior of the generated secret with respect to one of the same name in a base: "+
+			"create, merge or replace.")
+	cmd.Flags().StringVar(
+		&flags.SopsFileSource,
+		"from-sops-file",
+		"",
+		"Specify the path to a sops encrypted file to decrypt into a secret at build time.  The secret is "+
+			"made by a "+sopsGeneratorKind+" generator, whose config is written to {NAME}-sops.yaml.")
+	cmd.Flags().StringSliceVar(
+		&flags.SopsKeys,
+		"sops-keys",
+		[]string{},
+		"Specify the keys of the decrypted data to put in the secret (default all).")
+	cmd.Flags().StringVar(
+		&flags.SopsProvider,
+		"sops-provider",
+		"",
+		"Check that the sops file is decryptable with a key of the provider: "+
+			strings.Join(sopsProviderNames(), ", ")+" (default any of them).  "+
+			"sops reads the key from the file when the plugin decrypts it.")
+	cmd.Flags().StringVar(
+		&flags.SopsApiVersion,
+		"sops-api-version",
+		sopsGeneratorApiVersion,
+		"Specify the apiVersion the "+sopsGeneratorKind+" plugin is installed under.")
 
 	return cmd
 }
@@ -126,17 +166,50 @@ func findOrMakeSecretArgs(m *types.Kustomization, name, namespace, secretType st
 	return &m.SecretGenerator[len(m.SecretGenerator)-1]
 }
 
+// mergeFlagsIntoGeneratorArgs adds the sources in the flags to
+// the args.  A literal or file source whose key is already
+// present replaces the existing source, so that repeated
+// invocations update a generator rather than duplicate keys.
 func mergeFlagsIntoGeneratorArgs(args *types.GeneratorArgs, flags flagsAndArgs) {
-	if len(flags.LiteralSources) > 0 {
-		args.LiteralSources = append(
-			args.LiteralSources, flags.LiteralSources...)
+	for _, s := range flags.LiteralSources {
+		args.LiteralSources = upsertSource(
+			args.LiteralSources, s, literalSourceKey)
 	}
-	if len(flags.FileSources) > 0 {
-		args.FileSources = append(
-			args.FileSources, flags.FileSources...)
+	for _, s := range flags.FileSources {
+		args.FileSources = upsertSource(
+			args.FileSources, s, fileSourceKey)
 	}
 	if flags.EnvFileSource != "" {
-		args.EnvSources = append(
-			args.EnvSources, flags.EnvFileSource)
+		args.EnvSources = upsertSource(
+			args.EnvSources, flags.EnvFileSource, func(s string) string { return s })
+	}
+	if flags.Behavior != "" {
+		args.Behavior = flags.Behavior
+	}
+}
+
+// upsertSource replaces the source in the list having the
+// same key as the given source, or appends it if none does.
+func upsertSource(list []string, source string, key func(string) string) []string {
+	k := key(source)
+	for i, s := range list {
+		if key(s) == k {
+			list[i] = source
+			return list
+		}
+	}
+	return append(list, source)
+}
+
+// literalSourceKey returns the key of a source like "key=value".
+func literalSourceKey(s string) string {
+	return strings.SplitN(s, "=", 2)[0]
+}
+
+// fileSourceKey returns the key of a source like "[key=]path".
+func fileSourceKey(s string) string {
+	if parts := strings.SplitN(s, "=", 2); len(parts) == 2 {
+		return parts[0]
 	}
+	return filepath.Base(s)
 }
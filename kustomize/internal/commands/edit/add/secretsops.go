@@ -0,0 +1,176 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// sopsGeneratorKind is the kind of the generator plugin
+	// that decrypts a sops file at build time, see
+	// docs/guides/plugins/gopluginguidedexample.
+	sopsGeneratorKind = "SopsEncodedSecrets"
+	// sopsGeneratorApiVersion is the apiVersion the
+	// plugin is installed under in that guide.
+	sopsGeneratorApiVersion = "mygenerators"
+)
+
+// sopsProviders are the sops key providers, with the field
+// holding their keys in the sops metadata of an encrypted
+// file, and the field that identifies a key.
+var sopsProviders = []struct {
+	name, field, id string
+}{
+	{"pgp", "pgp", "fp"},
+	{"gcp-kms", "gcp_kms", "resource_id"},
+	{"aws-kms", "kms", "arn"},
+	{"azure-kv", "azure_kv", "vault_url"},
+}
+
+// sopsGenerator is the config of the sops generator plugin.
+type sopsGenerator struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Metadata   types.ObjectMeta `json:"metadata"`
+	// Name and Namespace of the generated secret.
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Type      string `json:"type,omitempty"`
+	// File is the path to the sops encrypted file.
+	File string `json:"file"`
+	// Keys are the keys of the decrypted data put in
+	// the secret; all of them if empty.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// sopsGeneratorFileName returns the name of the
+// file holding the generator config of a secret.
+func sopsGeneratorFileName(name string) string {
+	return name + "-sops.yaml"
+}
+
+// addSopsSecret writes the config of a generator making the secret
+// from the sops encrypted file, and adds the config to the generators
+// of the kustomization if it's missing.  Running it again for the same
+// secret updates the config.
+func addSopsSecret(
+	fSys filesys.FileSystem, k *types.Kustomization, flags flagsAndArgs) error {
+	err := checkSopsFile(fSys, flags.SopsFileSource, flags.SopsProvider)
+	if err != nil {
+		return err
+	}
+	g := sopsGenerator{
+		APIVersion: flags.SopsApiVersion,
+		Kind:       sopsGeneratorKind,
+		Metadata:   types.ObjectMeta{Name: flags.Name},
+		Name:       flags.Name,
+		Namespace:  flags.Namespace,
+		File:       flags.SopsFileSource,
+		Keys:       flags.SopsKeys,
+	}
+	if flags.Type != "Opaque" {
+		g.Type = flags.Type
+	}
+	if g.APIVersion == "" {
+		g.APIVersion = sopsGeneratorApiVersion
+	}
+	fileName := sopsGeneratorFileName(flags.Name)
+	if err = checkSopsGeneratorFile(fSys, fileName); err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(g)
+	if err != nil {
+		return err
+	}
+	if err = fSys.WriteFile(fileName, content); err != nil {
+		return err
+	}
+	for _, v := range k.Generators {
+		if v == fileName {
+			return nil
+		}
+	}
+	k.Generators = append(k.Generators, fileName)
+	return nil
+}
+
+// checkSopsGeneratorFile refuses to overwrite an
+// existing file that isn't a sops generator config.
+func checkSopsGeneratorFile(fSys filesys.FileSystem, fileName string) error {
+	if !fSys.Exists(fileName) {
+		return nil
+	}
+	content, err := fSys.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	var g sopsGenerator
+	if err = yaml.Unmarshal(content, &g); err != nil || g.Kind != sopsGeneratorKind {
+		return fmt.Errorf(
+			"%s exists and isn't the config of a %s generator", fileName, sopsGeneratorKind)
+	}
+	return nil
+}
+
+// checkSopsFile checks that the file is encrypted by sops,
+// with a key of the requested provider, if any, else of any
+// provider.  The plugin needs nothing more to decrypt it,
+// as sops reads the keys from the sops metadata of the file.
+func checkSopsFile(fSys filesys.FileSystem, path, requested string) error {
+	if requested != "" && !isSopsProvider(requested) {
+		return fmt.Errorf(
+			"sops-provider must be one of %s", sopsProviderNames())
+	}
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var data map[string]interface{}
+	if err = yaml.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("unable to parse sops file %s: %v", path, err)
+	}
+	metadata, ok := data["sops"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s isn't encrypted by sops; it has no sops metadata", path)
+	}
+	for _, p := range sopsProviders {
+		if requested != "" && p.name != requested {
+			continue
+		}
+		keys, _ := metadata[p.field].([]interface{})
+		for _, k := range keys {
+			key, _ := k.(map[string]interface{})
+			if id, _ := key[p.id].(string); id != "" {
+				return nil
+			}
+		}
+	}
+	if requested != "" {
+		return fmt.Errorf("%s names no %s key", path, requested)
+	}
+	return fmt.Errorf(
+		"%s doesn't name a key from any of %s", path, sopsProviderNames())
+}
+
+func isSopsProvider(name string) bool {
+	for _, p := range sopsProviders {
+		if p.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sopsProviderNames() []string {
+	var result []string
+	for _, p := range sopsProviders {
+		result = append(result, p.name)
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const encryptedData = `
+ROCKET: ENC[AES256_GCM,data:wC8=,type:str]
+CAR: ENC[AES256_GCM,data:l5I=,type:str]
+sops:
+  gcp_kms:
+  - resource_id: projects/p/locations/global/keyRings/sops/cryptoKeys/sops-key
+  pgp:
+  - fp: 1022470DE3F0BC54BC6AB62DE05550BC07FB1A0A
+  version: 3.5.0
+`
+
+func TestAddSopsSecret(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("data.enc.yaml", []byte(encryptedData))
+	k := &types.Kustomization{}
+	flags := flagsAndArgs{
+		Name:           "forbiddenValues",
+		Namespace:      "production",
+		Type:           "Opaque",
+		SopsFileSource: "data.enc.yaml",
+		SopsKeys:       []string{"ROCKET", "CAR"},
+	}
+	if err := addSopsSecret(fSys, k, flags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A second run updates the config, without
+	// adding the generator twice.
+	flags.SopsProvider = "gcp-kms"
+	if err := addSopsSecret(fSys, k, flags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(k.Generators) != 1 || k.Generators[0] != "forbiddenValues-sops.yaml" {
+		t.Fatalf("unexpected generators: %v", k.Generators)
+	}
+	content, err := fSys.ReadFile("forbiddenValues-sops.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: mygenerators
+file: data.enc.yaml
+keys:
+- ROCKET
+- CAR
+kind: SopsEncodedSecrets
+metadata:
+  name: forbiddenValues
+name: forbiddenValues
+namespace: production
+`
+	if string(content) != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, content)
+	}
+}
+
+func TestCheckSopsFile(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("data.enc.yaml", []byte(encryptedData))
+	fSys.WriteFile("clear.yaml", []byte("ROCKET: saturn-v\n"))
+	fSys.WriteFile("keyless.enc.yaml", []byte("sops:\n  pgp: []\n"))
+	testCases := map[string]struct {
+		path      string
+		requested string
+		errMsg    string
+	}{
+		"any": {
+			path: "data.enc.yaml",
+		},
+		"requested": {
+			path:      "data.enc.yaml",
+			requested: "gcp-kms",
+		},
+		"requestedAbsent": {
+			path:      "data.enc.yaml",
+			requested: "aws-kms",
+			errMsg:    "data.enc.yaml names no aws-kms key",
+		},
+		"unknown": {
+			path:      "data.enc.yaml",
+			requested: "vault",
+			errMsg:    "sops-provider must be one of [pgp gcp-kms aws-kms azure-kv]",
+		},
+		"notEncrypted": {
+			path:   "clear.yaml",
+			errMsg: "clear.yaml isn't encrypted by sops",
+		},
+		"noKey": {
+			path:   "keyless.enc.yaml",
+			errMsg: "keyless.enc.yaml doesn't name a key",
+		},
+	}
+	for n, tc := range testCases {
+		err := checkSopsFile(fSys, tc.path, tc.requested)
+		if tc.errMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("%s: expected error %q, got %v", n, tc.errMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+		}
+	}
+}
+
+func TestAddSopsSecretKeepsOtherFiles(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("data.enc.yaml", []byte(encryptedData))
+	fSys.WriteFile("app-sops.yaml", []byte("kind: Deployment\n"))
+	err := addSopsSecret(fSys, &types.Kustomization{}, flagsAndArgs{
+		Name: "app", SopsFileSource: "data.enc.yaml"})
+	if err == nil || !strings.Contains(err.Error(), "app-sops.yaml exists") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
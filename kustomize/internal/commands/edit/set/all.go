@@ -21,6 +21,12 @@ func NewCmdSet(fSys filesys.FileSystem, v ifc.Validator) *cobra.Command {
 
 	# Sets the namesuffix field
 	kustomize edit set namesuffix <suffix-value>
+
+	# Sets the replica count of a resource
+	kustomize edit set replicas <name>=<count>
+
+	# Sets one or more commonAnnotations
+	kustomize edit set annotation <key>:<value>
 `,
 		Args: cobra.MinimumNArgs(1),
 	}
@@ -31,6 +37,7 @@ func NewCmdSet(fSys filesys.FileSystem, v ifc.Validator) *cobra.Command {
 		newCmdSetNamespace(fSys, v),
 		newCmdSetImage(fSys),
 		newCmdSetReplicas(fSys),
+		newCmdSetAnnotation(fSys, v.MakeAnnotationValidator()),
 	)
 	return c
 }
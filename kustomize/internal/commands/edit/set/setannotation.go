@@ -0,0 +1,89 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/util"
+)
+
+type setAnnotationOptions struct {
+	annotations  map[string]string
+	mapValidator func(map[string]string) error
+}
+
+var errAnnotationNoArgs = errors.New("no annotations specified")
+
+// newCmdSetAnnotation sets commonAnnotations in the kustomization,
+// adding those that are missing and overwriting those that exist.
+func newCmdSetAnnotation(fSys filesys.FileSystem, v func(map[string]string) error) *cobra.Command {
+	var o setAnnotationOptions
+	o.mapValidator = v
+
+	cmd := &cobra.Command{
+		Use:   "annotation",
+		Short: "Sets one or more commonAnnotations in the kustomization file",
+		Example: `
+The command
+  set annotation owner:team-a,tier:backend
+will add
+
+commonAnnotations:
+  owner: team-a
+  tier: backend
+
+to the kustomization file if they don't exist,
+and overwrite the previous values if the keys exist.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunSetAnnotation(fSys)
+		},
+	}
+	return cmd
+}
+
+// Validate validates setAnnotation command.
+func (o *setAnnotationOptions) Validate(args []string) error {
+	if len(args) == 0 {
+		return errAnnotationNoArgs
+	}
+	o.annotations = make(map[string]string)
+	for _, arg := range args {
+		m, err := util.ConvertToMap(arg, "annotation")
+		if err != nil {
+			return err
+		}
+		for k, v := range m {
+			o.annotations[k] = v
+		}
+	}
+	return o.mapValidator(o.annotations)
+}
+
+// RunSetAnnotation runs setAnnotation command.
+func (o *setAnnotationOptions) RunSetAnnotation(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+	if m.CommonAnnotations == nil {
+		m.CommonAnnotations = make(map[string]string)
+	}
+	for k, v := range o.annotations {
+		m.CommonAnnotations[k] = v
+	}
+	return mf.Write(m)
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package set
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+	testutils_test "sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+)
+
+func TestSetAnnotationCreatesAndOverwrites(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, []byte(`
+commonAnnotations:
+  owner: team-a
+  keep: me
+`))
+	v := valtest_test.MakeHappyMapValidator(t)
+	cmd := newCmdSetAnnotation(fSys, v.Validator)
+	err := cmd.RunE(cmd, []string{"owner:team-b,tier:backend"})
+	v.VerifyCall()
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	for _, expected := range []string{
+		"owner: team-b", "tier: backend", "keep: me"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected %q in kustomization file:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(string(content), "team-a") {
+		t.Errorf("expected old value to be overwritten:\n%s", content)
+	}
+}
+
+func TestSetAnnotationNoArgs(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	v := valtest_test.MakeHappyMapValidator(t)
+	cmd := newCmdSetAnnotation(fSys, v.Validator)
+	err := cmd.Execute()
+	v.VerifyNoCall()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if err != errAnnotationNoArgs {
+		t.Errorf("incorrect error: %v", err.Error())
+	}
+}
+
+func TestSetAnnotationInvalid(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	v := valtest_test.MakeSadMapValidator(t)
+	cmd := newCmdSetAnnotation(fSys, v.Validator)
+	err := cmd.RunE(cmd, []string{"whatever:whatever"})
+	v.VerifyCall()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if err.Error() != valtest_test.SAD {
+		t.Errorf("incorrect error: %v", err.Error())
+	}
+}
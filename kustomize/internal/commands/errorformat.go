@@ -0,0 +1,86 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	flagErrorFormatName = "error-format"
+	errorFormatText     = "text"
+	errorFormatJson     = "json"
+)
+
+var flagErrorFormatValue = errorFormatText
+
+func addFlagErrorFormat(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagErrorFormatValue, flagErrorFormatName, errorFormatText,
+		"The format of error messages, '"+errorFormatText+"' or '"+errorFormatJson+"'.")
+}
+
+func validateFlagErrorFormat() error {
+	switch flagErrorFormatValue {
+	case errorFormatText, errorFormatJson:
+		return nil
+	default:
+		err := fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagErrorFormatName, flagErrorFormatValue,
+			[]string{errorFormatText, errorFormatJson})
+		// The error about the flag can't be written per the flag.
+		flagErrorFormatValue = errorFormatText
+		return err
+	}
+}
+
+// jsonError is the JSON rendering of an error.
+type jsonError struct {
+	Message string `json:"message"`
+	// Kind is the class of build failure, if known.
+	Kind string `json:"kind,omitempty"`
+	types.ErrorLocation
+	// Errors are the individual errors of an aggregate.
+	Errors []jsonError `json:"errors,omitempty"`
+}
+
+type jsonErrorOutput struct {
+	Error    jsonError `json:"error"`
+	ExitCode int       `json:"exitCode"`
+}
+
+func makeJsonError(err error) jsonError {
+	result := jsonError{
+		Message:       err.Error(),
+		ErrorLocation: types.ErrorLocationOf(err),
+	}
+	if k := types.BuildErrorKindOf(err); k != types.BuildErrorUnknown {
+		result.Kind = strings.TrimPrefix(k.String(), "BuildError")
+	}
+	for _, e := range types.AggregatedErrors(err) {
+		result.Errors = append(result.Errors, makeJsonError(e))
+	}
+	return result
+}
+
+// WriteError writes the error returned by a command
+// in the format given by the --error-format flag.
+func WriteError(w io.Writer, err error) {
+	if flagErrorFormatValue == errorFormatJson {
+		b, errJ := json.MarshalIndent(
+			jsonErrorOutput{Error: makeJsonError(err), ExitCode: ExitCode(err)}, "", "  ")
+		if errJ == nil {
+			fmt.Fprintln(w, string(b))
+			return
+		}
+	}
+	fmt.Fprintln(w, "Error:", err.Error())
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestWriteError(t *testing.T) {
+	defer func() { flagErrorFormatValue = errorFormatText }()
+	err := types.NewErrLocated(
+		types.NewErrBuild(types.BuildErrorParse, types.NewErrAggregate("bad file", []error{
+			types.NewErrLocated(fmt.Errorf("kind is wrong"), types.ErrorLocation{
+				File: "/app/kustomization.yaml", FieldPath: "kind"}),
+		})),
+		types.ErrorLocation{File: "/app/kustomization.yaml"})
+
+	var b bytes.Buffer
+	WriteError(&b, err)
+	if b.String() != "Error: bad file:\nkind is wrong\n" {
+		t.Fatalf("unexpected text output %q", b.String())
+	}
+
+	flagErrorFormatValue = errorFormatJson
+	b.Reset()
+	WriteError(&b, err)
+	expected := `{
+  "error": {
+    "message": "bad file:\nkind is wrong",
+    "kind": "Parse",
+    "file": "/app/kustomization.yaml",
+    "errors": [
+      {
+        "message": "kind is wrong",
+        "file": "/app/kustomization.yaml",
+        "fieldPath": "kind"
+      }
+    ]
+  },
+  "exitCode": 2
+}
+`
+	if b.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func TestValidateFlagErrorFormat(t *testing.T) {
+	defer func() { flagErrorFormatValue = errorFormatText }()
+	flagErrorFormatValue = "xml"
+	err := validateFlagErrorFormat()
+	if err == nil || err.Error() !=
+		"illegal flag value --error-format xml; legal values: [text json]" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flagErrorFormatValue != errorFormatText {
+		t.Fatalf("expected fallback to text")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// Exit codes of the kustomize binary.  Scripts may rely
+// on these, so existing values must not change.
+const (
+	ExitOk = iota
+	ExitFailure
+	ExitParse
+	ExitMissingFile
+	ExitRemoteFetch
+	ExitPlugin
+	ExitValidation
+)
+
+var exitCodes = map[types.BuildErrorKind]int{
+	types.BuildErrorParse:       ExitParse,
+	types.BuildErrorMissingFile: ExitMissingFile,
+	types.BuildErrorRemoteFetch: ExitRemoteFetch,
+	types.BuildErrorPlugin:      ExitPlugin,
+	types.BuildErrorValidation:  ExitValidation,
+}
+
+// ExitCode returns the exit code for the error
+// returned by a command.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOk
+	}
+	if c, ok := exitCodes[types.BuildErrorKindOf(err)]; ok {
+		return c
+	}
+	return ExitFailure
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitOk},
+		{fmt.Errorf("unknown command"), ExitFailure},
+		{types.NewErrBuild(types.BuildErrorParse, fmt.Errorf("x")), ExitParse},
+		{errors.Wrap(types.NewErrBuild(
+			types.BuildErrorMissingFile, fmt.Errorf("x")), "y"), ExitMissingFile},
+		{types.NewErrBuild(types.BuildErrorRemoteFetch, fmt.Errorf("x")), ExitRemoteFetch},
+		{types.NewErrBuild(types.BuildErrorPlugin, fmt.Errorf("x")), ExitPlugin},
+		{types.NewErrBuild(types.BuildErrorValidation, fmt.Errorf("x")), ExitValidation},
+	}
+	for _, tc := range testCases {
+		if actual := ExitCode(tc.err); actual != tc.expected {
+			t.Errorf("for %v expected %d, got %d", tc.err, tc.expected, actual)
+		}
+	}
+}
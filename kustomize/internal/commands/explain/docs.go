@@ -0,0 +1,419 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package explain
+
+import (
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// doc holds the human readable part of a field or kind
+// explanation; the field names and types are obtained
+// by reflection on the structs that unmarshal the yaml.
+type doc struct {
+	description  string
+	defaultValue string
+	example      string
+}
+
+// typeDocs is keyed by kind.
+var typeDocs = map[string]doc{
+	types.KustomizationKind: {
+		description: "A kustomization file (" + konfig.DefaultKustomizationFileName() +
+			") declares resources and the\ncustomizations to apply to them.",
+		example: `
+apiVersion: ` + types.KustomizationVersion + `
+kind: Kustomization
+namePrefix: dev-
+resources:
+- deployment.yaml
+`,
+	},
+	"AnnotationsTransformer": {
+		description: "Adds annotations to the fields selected by fieldSpecs.",
+		example: `
+apiVersion: builtin
+kind: AnnotationsTransformer
+metadata:
+  name: notImportantHere
+annotations:
+  app: myApp
+fieldSpecs:
+- path: metadata/annotations
+  create: true
+`,
+	},
+	"ConfigMapGenerator": {
+		description: "Generates a ConfigMap from literals, files and env files.",
+		example: `
+apiVersion: builtin
+kind: ConfigMapGenerator
+metadata:
+  name: mymap
+literals:
+- FRUIT=apple
+`,
+	},
+	"GoTemplateGenerator": {
+		description: "Generates resources by rendering Go templates, given the values as dot.\n" +
+			"Needs --enable-gotemplate.  Templates may call the functions of Sprig, and\n" +
+			"toYaml and required of Helm, but not those of Sprig depending on more than\n" +
+			"their arguments, so that builds are reproducible: env, expandenv, now, ago,\n" +
+			"the date, rand, uuid and gen functions, shuffle, encryptAES and getHostByName.\n" +
+			"keys returns the keys sorted.",
+		example: `
+apiVersion: builtin
+kind: GoTemplateGenerator
+metadata:
+  name: app
+values:
+  name: app
+template: |
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: {{ .name }}-config
+`,
+	},
+	"HashTransformer": {
+		description: "Appends a content hash to the names of generated ConfigMaps and Secrets.\nTakes no configuration.",
+	},
+	"ImageTagTransformer": {
+		description: "Changes the name, tag or digest of container images.",
+		example: `
+apiVersion: builtin
+kind: ImageTagTransformer
+metadata:
+  name: notImportantHere
+imageTag:
+  name: nginx
+  newTag: v2
+`,
+	},
+	"LabelTransformer": {
+		description: "Adds labels to the fields selected by fieldSpecs.",
+		example: `
+apiVersion: builtin
+kind: LabelTransformer
+metadata:
+  name: notImportantHere
+labels:
+  app: myApp
+fieldSpecs:
+- path: metadata/labels
+  create: true
+`,
+	},
+	"LegacyOrderTransformer": {
+		description: "Sorts resources in the legacy order (Namespaces first, webhooks last, etc).\nTakes no configuration.",
+	},
+	"NamespaceTransformer": {
+		description: "Sets the namespace of namespaced resources, and of the fields selected by fieldSpecs.",
+		example: `
+apiVersion: builtin
+kind: NamespaceTransformer
+metadata:
+  name: notImportantHere
+  namespace: test
+fieldSpecs:
+- path: metadata/namespace
+  create: true
+`,
+	},
+	"PatchJson6902Transformer": {
+		description: "Applies a JSON patch (RFC 6902) to one target resource.",
+		example: `
+apiVersion: builtin
+kind: PatchJson6902Transformer
+metadata:
+  name: notImportantHere
+target:
+  group: apps
+  version: v1
+  kind: Deployment
+  name: my-deploy
+path: jsonpatch.json
+`,
+	},
+	"PatchStrategicMergeTransformer": {
+		description: "Applies strategic merge patches, read from files or inlined.\nWith strict, or --strict-patch, fails on fields unknown to the schema.",
+		example: `
+apiVersion: builtin
+kind: PatchStrategicMergeTransformer
+metadata:
+  name: notImportantHere
+paths:
+- patch.yaml
+`,
+	},
+	"PatchTransformer": {
+		description: "Applies a strategic merge or JSON patch to all resources matched by target.\nWith strict, or --strict-patch, a strategic merge patch fails on fields\nunknown to the schema.",
+		example: `
+apiVersion: builtin
+kind: PatchTransformer
+metadata:
+  name: notImportantHere
+patch: '[{"op": "replace", "path": "/spec/replicas", "value": 3}]'
+target:
+  kind: Deployment
+`,
+	},
+	"PrefixSuffixTransformer": {
+		description: "Adds a prefix and/or suffix to the fields selected by fieldSpecs.",
+		example: `
+apiVersion: builtin
+kind: PrefixSuffixTransformer
+metadata:
+  name: notImportantHere
+prefix: baked-
+fieldSpecs:
+- path: metadata/name
+`,
+	},
+	"ReplicaCountTransformer": {
+		description: "Sets the replica count of the named resource.",
+		example: `
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  name: my-deploy
+  count: 3
+fieldSpecs:
+- path: spec/replicas
+  kind: Deployment
+`,
+	},
+	"SecretGenerator": {
+		description: "Generates a Secret from literals, files and env files.",
+		example: `
+apiVersion: builtin
+kind: SecretGenerator
+metadata:
+  name: mysecret
+literals:
+- password=secret
+`,
+	},
+	"ValueAddTransformer": {
+		description: "Adds a value to the fields specified by targets.",
+	},
+}
+
+// fieldDocs is keyed by the name of the Go struct
+// declaring a field, a dot, and the yaml name of the field.
+var fieldDocs = map[string]doc{
+	"TypeMeta.apiVersion": {
+		description:  "The version of the file format.",
+		defaultValue: types.KustomizationVersion,
+	},
+	"TypeMeta.kind": {
+		description:  "Either " + types.KustomizationKind + " or " + types.ComponentKind + ".",
+		defaultValue: types.KustomizationKind,
+	},
+	"Kustomization.namePrefix": {
+		description: "Prepended to the names of all resources, including generated ones.",
+		example:     "namePrefix: dev-",
+	},
+	"Kustomization.nameSuffix": {
+		description: "Appended to the names of all resources, including generated ones.",
+		example:     "nameSuffix: -v2",
+	},
+	"Kustomization.namespace": {
+		description: "Namespace to add to all objects.",
+		example:     "namespace: my-namespace",
+	},
+	"Kustomization.commonLabels": {
+		description: "Labels to add to all objects and selectors.",
+		example:     "commonLabels:\n  app: bingo",
+	},
+	"Kustomization.commonAnnotations": {
+		description: "Annotations to add to all objects.",
+		example:     "commonAnnotations:\n  oncallPager: 800-555-1212",
+	},
+	"Kustomization.patchesStrategicMerge": {
+		description: "Relative paths to files holding strategic merge patches, or\ninlined patches.  URLs and globs are not supported.",
+		example:     "patchesStrategicMerge:\n- service_port_8888.yaml",
+	},
+	"Kustomization.patchesJson6902": {
+		description: "JSON patches (RFC 6902), each applied to one target.",
+		example:     "patchesJson6902:\n- target:\n    version: v1\n    kind: Service\n    name: my-service\n  path: patch.json",
+	},
+	"Kustomization.patches": {
+		description: "Patches, each either a strategic merge patch or a JSON patch,\napplied to all the resources matched by the target.",
+		example:     "patches:\n- path: patch.yaml\n  target:\n    kind: Deployment",
+	},
+	"Kustomization.images": {
+		description: "Changes to the names, tags or digests of container images.",
+		example:     "images:\n- name: nginx\n  newTag: 1.8.0",
+	},
+	"Kustomization.replicas": {
+		description: "Replica counts to set on resources, by name.",
+		example:     "replicas:\n- name: my-deployment\n  count: 5",
+	},
+	"Kustomization.vars": {
+		description: "Values taken from fields of resources, substituted for $(NAME)\nin other resources once all names are final.",
+	},
+	"Kustomization.resources": {
+		description: "Relative paths to files holding resources, or to directories\nand URLs holding other kustomizations.",
+		example:     "resources:\n- deployment.yaml\n- ../base",
+	},
+	"Kustomization.components": {
+		description: "Relative paths or URLs of components to apply to the resources.",
+	},
+	"Kustomization.crds": {
+		description: "Relative paths to CustomResourceDefinition files, used so that\ncustom resources can be name referenced.  CRDs aren't modified.",
+	},
+	"Kustomization.bases": {
+		description: "Deprecated; use resources instead.",
+	},
+	"Kustomization.configMapGenerator": {
+		description: "ConfigMaps to generate, one per list item.  By default the name of\neach map gets a suffix hash computed from its contents.",
+		example:     "configMapGenerator:\n- name: my-java-server-env-vars\n  literals:\n  - JAVA_HOME=/opt/java/jdk",
+	},
+	"Kustomization.secretGenerator": {
+		description: "Secrets to generate, one per list item.  By default the name of\neach secret gets a suffix hash computed from its contents.",
+		example:     "secretGenerator:\n- name: app-tls\n  files:\n  - secret/tls.crt\n  - secret/tls.key\n  type: kubernetes.io/tls",
+	},
+	"Kustomization.generatorOptions": {
+		description: "Options applied to all ConfigMap and Secret generators.",
+	},
+	"Kustomization.configurations": {
+		description: "Relative paths to transformer configuration files.",
+	},
+	"Kustomization.generators": {
+		description: "Relative paths to generator plugin configs, or to kustomizations\nproducing them.",
+	},
+	"Kustomization.transformers": {
+		description: "Relative paths to transformer plugin configs, or to kustomizations\nproducing them.",
+	},
+	"Kustomization.validators": {
+		description: "Relative paths to validator plugin configs.  Validators may not\nmodify resources.",
+	},
+	"Kustomization.inventory": {
+		description: "Appends an object recording all other objects, for use in\napply, prune and delete.",
+	},
+	"Kustomization.buildMetadata": {
+		description: "Options for recording how the resources were built.  With\noriginAnnotations, each resource is annotated with the file it was read\nfrom, or the generator that made it.  With transformerAnnotations, each\nresource is annotated with the generators and transformers that made or\nchanged it.",
+	},
+	"Kustomization.pipeline": {
+		description: "KRM functions run in containers on the resources after the\ntransformers: mutators change them, validators check them.  The\nfunctions are plugins, enabled like other plugins.",
+	},
+	"GeneratorArgs.namespace": {
+		description: "Namespace of the generated resource.",
+	},
+	"GeneratorArgs.name": {
+		description: "The name of the generated resource, before the addition of\nprefixes, suffixes and the content hash.",
+	},
+	"GeneratorArgs.behavior": {
+		description:  "One of 'create', 'replace' (an existing resource) or 'merge'\n(with an existing resource).",
+		defaultValue: "create",
+	},
+	"GeneratorArgs.options": {
+		description: "Local overrides to the global generatorOptions field.",
+	},
+	"KvPairSources.literals": {
+		description: "Literal key=value pairs.",
+		example:     "literals:\n- FRUIT=apple",
+	},
+	"KvPairSources.files": {
+		description: "File sources of the form [{key}=]{path}.  The key defaults to\nthe basename of the path; the value is the file contents.",
+		example:     "files:\n- application.properties\n- key=path/to/file",
+	},
+	"KvPairSources.envs": {
+		description: "Paths to files holding one key=value pair per line.",
+		example:     "envs:\n- foo.env",
+	},
+	"SecretArgs.type": {
+		description:  "The type of the Secret.  If 'kubernetes.io/tls', exactly the keys\n'tls.key' and 'tls.crt' must be given.",
+		defaultValue: "Opaque",
+	},
+	"GeneratorOptions.labels": {
+		description: "Labels to add to all generated resources.",
+	},
+	"GeneratorOptions.annotations": {
+		description: "Annotations to add to all generated resources.",
+	},
+	"GeneratorOptions.disableNameSuffixHash": {
+		description:  "If true, don't add a content hash suffix to generated names.",
+		defaultValue: "false",
+	},
+	"Image.name": {
+		description: "A tag-less image name to match.",
+	},
+	"Image.newName": {
+		description: "Replaces the original image name.",
+	},
+	"Image.newTag": {
+		description: "Replaces the original tag.",
+	},
+	"Image.digest": {
+		description: "Replaces the original tag; if present, newTag is ignored.",
+	},
+	"Replica.name": {
+		description: "The name of the resource whose replica count is changed.",
+	},
+	"Replica.count": {
+		description: "The number of replicas required.",
+	},
+	"Patch.path": {
+		description: "Relative path to a patch file.",
+	},
+	"Patch.patch": {
+		description: "The content of a patch.",
+	},
+	"Patch.target": {
+		description: "Selects the resources the patch is applied to.",
+	},
+	"PatchJson6902.target": {
+		description: "The resource to patch, identified by its name before the\naddition of prefixes and suffixes.",
+	},
+	"PatchJson6902.path": {
+		description: "Relative path to a JSON patch file.",
+	},
+	"PatchJson6902.patch": {
+		description: "An inlined JSON patch.",
+	},
+	"Selector.annotationSelector": {
+		description: "A label selection expression matched against resource annotations.",
+	},
+	"Selector.labelSelector": {
+		description: "A label selection expression matched against resource labels.",
+	},
+	"FieldSpec.path": {
+		description: "Slash separated path to the field.",
+		example:     "path: spec/template/metadata/labels",
+	},
+	"FieldSpec.create": {
+		description:  "If true, create the field if it's missing.",
+		defaultValue: "false",
+	},
+	"Var.name": {
+		description: "The name of the variable, e.g. FOO, referenced as $(FOO).",
+	},
+	"Var.objref": {
+		description: "The resource holding the value, identified by its name before\nthe addition of prefixes and suffixes.",
+	},
+	"Var.fieldref": {
+		description:  "The field of objref holding the value.",
+		defaultValue: "fieldPath: metadata.name",
+	},
+	"Pipeline.mutators": {
+		description: "Functions changing the resources, in order.",
+	},
+	"Pipeline.validators": {
+		description: "Functions checking the resources, in order, after the mutators.\nThey mustn't change the resources.",
+	},
+	"Function.image": {
+		description: "The container image of the function.",
+		example:     "image: gcr.io/kpt-functions/label-namespace",
+	},
+	"Function.configPath": {
+		description: "Relative path to a file holding the function config.",
+	},
+	"Function.configMap": {
+		description: "Data of a ConfigMap passed as the function config, instead of\na file.",
+	},
+}
@@ -0,0 +1,272 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package explain documents the fields of a kustomization
+// file and of the builtin generator and transformer configs.
+package explain
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// subject is a top level thing that can be explained.
+type subject struct {
+	kind       string
+	apiVersion string
+	t          reflect.Type
+}
+
+func subjects() []subject {
+	result := []subject{{
+		kind:       types.KustomizationKind,
+		apiVersion: types.KustomizationVersion,
+		t:          reflect.TypeOf(types.Kustomization{}),
+	}}
+	for _, p := range []interface{}{
+		builtins.NewAnnotationsTransformerPlugin(),
+		builtins.NewConfigMapGeneratorPlugin(),
+		builtins.NewGoTemplateGeneratorPlugin(),
+		builtins.NewHashTransformerPlugin(),
+		builtins.NewImageTagTransformerPlugin(),
+		builtins.NewLabelTransformerPlugin(),
+		builtins.NewLegacyOrderTransformerPlugin(),
+		builtins.NewNamespaceTransformerPlugin(),
+		builtins.NewPatchJson6902TransformerPlugin(),
+		builtins.NewPatchStrategicMergeTransformerPlugin(),
+		builtins.NewPatchTransformerPlugin(),
+		builtins.NewPrefixSuffixTransformerPlugin(),
+		builtins.NewReplicaCountTransformerPlugin(),
+		builtins.NewSecretGeneratorPlugin(),
+		builtins.NewValueAddTransformerPlugin(),
+	} {
+		t := reflect.TypeOf(p).Elem()
+		result = append(result, subject{
+			kind:       strings.TrimSuffix(t.Name(), "Plugin"),
+			apiVersion: konfig.BuiltinPluginApiVersion,
+			t:          t,
+		})
+	}
+	return result
+}
+
+// NewCmdExplain returns a new explain command.
+func NewCmdExplain(w io.Writer) *cobra.Command {
+	var recursive bool
+	cmd := &cobra.Command{
+		Use:   "explain [KIND[.FIELD]...]",
+		Short: "Documents the fields of kustomization files and builtin plugin configs",
+		Example: `
+	# List everything that can be explained
+	kustomize explain
+
+	# Document the top level fields of a kustomization file
+	kustomize explain kustomization
+
+	# Document a nested field
+	kustomize explain kustomization.configMapGenerator.options
+
+	# Document the config of a builtin transformer, including nested fields
+	kustomize explain PrefixSuffixTransformer --recursive
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return listSubjects(w)
+			}
+			return explain(w, args[0], recursive)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&recursive, "recursive", false,
+		"Print the fields of fields (currently limited to nested structures).")
+	return cmd
+}
+
+func listSubjects(w io.Writer) error {
+	fmt.Fprintln(w, "KINDS:")
+	for _, s := range subjects() {
+		fmt.Fprintf(w, "   %-32s%s\n", s.kind, s.apiVersion)
+	}
+	return nil
+}
+
+func findSubject(kind string) (subject, error) {
+	var kinds []string
+	for _, s := range subjects() {
+		if strings.EqualFold(s.kind, kind) {
+			return s, nil
+		}
+		kinds = append(kinds, s.kind)
+	}
+	return subject{}, fmt.Errorf(
+		"unknown kind %q; known kinds: %s", kind, strings.Join(kinds, ", "))
+}
+
+// field is a named field of a struct, as it
+// appears in yaml.
+type field struct {
+	name string
+	// owner is the name of the struct type declaring the field.
+	owner string
+	t     reflect.Type
+}
+
+// fieldsOf returns the yaml fields of the given type,
+// flattening inlined structs.  Returns nil for types
+// that aren't structs (after removing indirection).
+func fieldsOf(t reflect.Type) []field {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			// unexported
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" && f.Anonymous {
+			result = append(result, fieldsOf(f.Type)...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		result = append(result, field{name: name, owner: t.Name(), t: f.Type})
+	}
+	return result
+}
+
+// elemType strips pointers, slices and maps from a type,
+// returning the type of the contained values.
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeName(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return "[]" + typeName(t.Elem())
+	case reflect.Map:
+		return "map[" + typeName(t.Key()) + "]" + typeName(t.Elem())
+	case reflect.Struct:
+		return t.Name()
+	default:
+		return t.Kind().String()
+	}
+}
+
+func explain(w io.Writer, path string, recursive bool) error {
+	parts := strings.Split(path, ".")
+	s, err := findSubject(parts[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "KIND:     %s\n", s.kind)
+	fmt.Fprintf(w, "VERSION:  %s\n\n", s.apiVersion)
+	t := s.t
+	doc := typeDocs[s.kind]
+	if len(parts) > 1 {
+		var f *field
+		for _, name := range parts[1:] {
+			f = findField(t, name)
+			if f == nil {
+				return fmt.Errorf("field %q does not exist in %s", name, path)
+			}
+			t = f.t
+		}
+		fmt.Fprintf(w, "FIELD:    %s <%s>\n\n", f.name, typeName(f.t))
+		doc = fieldDocs[f.owner+"."+f.name]
+	}
+	printDoc(w, doc)
+	fields := fieldsOf(t)
+	if len(fields) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w, "FIELDS:")
+	printFields(w, fields, "   ", recursive)
+	return nil
+}
+
+func findField(t reflect.Type, name string) *field {
+	for _, f := range fieldsOf(t) {
+		if f.name == name {
+			return &f
+		}
+	}
+	return nil
+}
+
+func printDoc(w io.Writer, d doc) {
+	if d.description == "" {
+		d.description = "<empty>"
+	}
+	fmt.Fprintln(w, "DESCRIPTION:")
+	fmt.Fprintln(w, indent(d.description, "     "))
+	fmt.Fprintln(w)
+	if d.defaultValue != "" {
+		fmt.Fprintf(w, "DEFAULT:  %s\n\n", d.defaultValue)
+	}
+	if d.example != "" {
+		fmt.Fprintln(w, "EXAMPLE:")
+		fmt.Fprintln(w, indent(strings.TrimSpace(d.example), "     "))
+		fmt.Fprintln(w)
+	}
+}
+
+func printFields(w io.Writer, fields []field, prefix string, recursive bool) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].name < fields[j].name
+	})
+	for _, f := range fields {
+		fmt.Fprintf(w, "%s%s\t<%s>\n", prefix, f.name, typeName(f.t))
+		if recursive {
+			printFields(w, fieldsOf(f.t), prefix+"   ", recursive)
+			continue
+		}
+		if d := fieldDocs[f.owner+"."+f.name]; d.description != "" {
+			fmt.Fprintln(w, indent(firstSentence(d.description), prefix+"  "))
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+func firstSentence(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if i := strings.Index(s, ". "); i >= 0 {
+		return s[:i+1]
+	}
+	return s
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
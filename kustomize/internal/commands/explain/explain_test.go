@@ -0,0 +1,84 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package explain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExplainKustomizationField(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewCmdExplain(&out)
+	cmd.SetArgs([]string{"kustomization.configMapGenerator.options"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `KIND:     Kustomization
+VERSION:  kustomize.config.k8s.io/v1beta1
+
+FIELD:    options <GeneratorOptions>
+
+DESCRIPTION:
+     Local overrides to the global generatorOptions field.
+
+FIELDS:
+   annotations	<map[string]string>
+     Annotations to add to all generated resources.
+
+   disableNameSuffixHash	<bool>
+     If true, don't add a content hash suffix to generated names.
+
+   labels	<map[string]string>
+     Labels to add to all generated resources.
+
+`
+	if out.String() != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestExplainBuiltinRecursive(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewCmdExplain(&out)
+	cmd.SetArgs([]string{"replicacounttransformer", "--recursive"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		"KIND:     ReplicaCountTransformer\nVERSION:  builtin\n",
+		"EXAMPLE:\n     apiVersion: builtin\n",
+		"   fieldSpecs\t<[]FieldSpec>\n      create\t<bool>\n",
+		"   replica\t<Replica>\n      count\t<int64>\n      name\t<string>\n",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected output to contain\n%s\ngot\n%s", s, out.String())
+		}
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	for arg, msg := range map[string]string{
+		"nonsense":                 `unknown kind "nonsense"`,
+		"kustomization.nameprefix": `field "nameprefix" does not exist`,
+	} {
+		cmd := NewCmdExplain(&bytes.Buffer{})
+		cmd.SetArgs([]string{arg})
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%s: expected error containing %q, got %v", arg, msg, err)
+		}
+	}
+}
+
+func TestAllKindsDocumented(t *testing.T) {
+	for _, s := range subjects() {
+		if typeDocs[s.kind].description == "" {
+			t.Errorf("kind %s has no description", s.kind)
+		}
+	}
+}
@@ -60,6 +60,8 @@ func determineFieldOrder() []string {
 		"Transformers",
 		"Inventory",
 		"Components",
+		"BuildMetadata",
+		"Pipeline",
 	}
 
 	// Add deprecated fields here.
@@ -42,6 +42,8 @@ func TestFieldOrder(t *testing.T) {
 		"Transformers",
 		"Inventory",
 		"Components",
+		"BuildMetadata",
+		"Pipeline",
 	}
 	actual := determineFieldOrder()
 	if len(expected) != len(actual) {
@@ -0,0 +1,127 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package reorder sorts rendered resources
+// in the order build emits them.
+package reorder
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// stdinArg, given as the path, means read the resources from stdin.
+const stdinArg = "-"
+
+// NewCmdReorder returns a new reorder command.
+func NewCmdReorder(
+	out io.Writer, fSys filesys.FileSystem, uf ifc.KunstructuredFactory) *cobra.Command {
+	rf := resmap.NewFactory(resource.NewFactory(uf), nil)
+	return &cobra.Command{
+		Use:   "reorder {FILE|DIR|-}",
+		Short: "Prints resources in the order build emits them",
+		Long: `Reads resources, e.g. rendered by another tool, and prints them
+sorted as build sorts its output by default: Namespaces first, then
+other cluster-wide resources, then namespaced ones, with webhook
+configurations last.
+
+Given a directory, reads every YAML or JSON file under it.
+Given '` + stdinArg + `', reads stdin.
+`,
+		Example: `
+	# Sort the resources in a file
+	kustomize reorder manifests.yaml
+
+	# Sort the output of another tool before applying it
+	helm template chart | kustomize reorder - | kubectl apply -f -
+`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := read(rf, fSys, cmd.InOrStdin(), args[0])
+			if err != nil {
+				return err
+			}
+			return write(out, m)
+		},
+	}
+}
+
+// read returns the resources in the file or directory at path.
+func read(
+	rf *resmap.Factory, fSys filesys.FileSystem,
+	in io.Reader, path string) (resmap.ResMap, error) {
+	if path == stdinArg {
+		content, err := ioutil.ReadAll(in)
+		if err != nil {
+			return nil, fmt.Errorf("reading resources from stdin: %v", err)
+		}
+		return rf.NewResMapFromBytes(content)
+	}
+	if !fSys.IsDir(path) {
+		return readFile(rf, fSys, path)
+	}
+	result := resmap.New()
+	err := fSys.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isManifest(p) {
+			return nil
+		}
+		m, err := readFile(rf, fSys, p)
+		if err != nil {
+			return err
+		}
+		return result.AppendAll(m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func readFile(
+	rf *resmap.Factory, fSys filesys.FileSystem, path string) (resmap.ResMap, error) {
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := rf.NewResMapFromBytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("reading resources from %s: %v", path, err)
+	}
+	return m, nil
+}
+
+func isManifest(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// write sorts the resources with the transformer build
+// uses, and writes them as a YAML stream.
+func write(out io.Writer, m resmap.ResMap) error {
+	if err := builtins.NewLegacyOrderTransformerPlugin().Transform(m); err != nil {
+		return err
+	}
+	b, err := m.AsYaml()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package reorder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/k8sdeps/kunstruct"
+)
+
+const (
+	deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`
+	webhook = `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: hook
+`
+	namespace = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns
+`
+)
+
+func runReorder(fSys filesys.FileSystem, in string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := NewCmdReorder(&out, fSys, kunstruct.NewKunstructuredFactoryImpl())
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(in))
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestReorder(t *testing.T) {
+	expected := namespace + "---\n" + deployment + "---\n" + webhook
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/m/all.yaml", []byte(webhook+"---\n"+deployment+"---\n"+namespace))
+	fSys.WriteFile("/d/a/webhook.yaml", []byte(webhook))
+	fSys.WriteFile("/d/b/deployment.yml", []byte(deployment))
+	fSys.WriteFile("/d/namespace.yaml", []byte(namespace))
+	fSys.WriteFile("/d/README.md", []byte("not a manifest"))
+	testCases := map[string]struct {
+		in   string
+		args []string
+	}{
+		"file":  {args: []string{"/m/all.yaml"}},
+		"dir":   {args: []string{"/d"}},
+		"stdin": {in: deployment + "---\n" + webhook + "---\n" + namespace, args: []string{"-"}},
+	}
+	for n, tc := range testCases {
+		out, err := runReorder(fSys, tc.in, tc.args...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", n, err)
+			continue
+		}
+		if out != expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", n, expected, out)
+		}
+	}
+}
+
+func TestReorderErrors(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/d/one.yaml", []byte(deployment))
+	fSys.WriteFile("/d/two.yaml", []byte(deployment))
+	fSys.WriteFile("/bad.yaml", []byte("kind: [\n"))
+	testCases := map[string]struct {
+		args   []string
+		errMsg string
+	}{
+		"missing":   {args: []string{"/nope.yaml"}, errMsg: "/nope.yaml"},
+		"parse":     {args: []string{"/bad.yaml"}, errMsg: "reading resources from /bad.yaml"},
+		"duplicate": {args: []string{"/d"}, errMsg: "may not add resource with an already registered id"},
+	}
+	for n, tc := range testCases {
+		_, err := runReorder(fSys, "", tc.args...)
+		if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+			t.Errorf("%s: expected error containing %q, got %v", n, tc.errMsg, err)
+		}
+	}
+}
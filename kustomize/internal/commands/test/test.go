@@ -0,0 +1,271 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package test compares the build output of
+// kustomizations to checked-in golden files.
+package test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	// goldenOutputFile holds the expected build output
+	// of the kustomization in the same directory.
+	goldenOutputFile = "golden.yaml"
+	// goldenErrorFile holds text that is expected to
+	// appear in the error of a build that should fail.
+	goldenErrorFile = "golden.err"
+)
+
+type options struct {
+	update        bool
+	enablePlugins bool
+}
+
+// NewCmdTest returns a new test command.
+func NewCmdTest(out io.Writer, fSys filesys.FileSystem) *cobra.Command {
+	var o options
+	cmd := &cobra.Command{
+		Use: "test {dir}",
+		Short: "Compares the build output of each kustomization under a " +
+			"directory to its golden file",
+		Long: `Finds each kustomization under DIR, builds it, and compares
+the output to the file '` + goldenOutputFile + `' next to the kustomization file.
+
+If instead there's a file named '` + goldenErrorFile + `', the build is expected
+to fail, with an error containing the (trimmed) content of that file.
+
+Kustomizations without a golden file are skipped, unless --update is given.
+`,
+		Example: `
+	# Check all the overlays under the current directory
+	kustomize test .
+
+	# Record the current output of all kustomizations as the expectation
+	kustomize test . --update
+`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir := filesys.SelfDir
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			return o.run(out, fSys, dir)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&o.update, "update", false,
+		"Write the build output (or error) to the golden files instead of comparing.")
+	cmd.Flags().BoolVar(
+		&o.enablePlugins, "enable_alpha_plugins", false,
+		"enable plugins, an alpha feature.")
+	return cmd
+}
+
+func (o *options) makeOptions() (*krusty.Options, error) {
+	opts := krusty.MakeDefaultOptions()
+	opts.DoLegacyResourceSort = true
+	if o.enablePlugins {
+		c, err := konfig.EnabledPluginConfig(types.BploUseStaticallyLinked)
+		if err != nil {
+			return nil, err
+		}
+		opts.PluginConfig = c
+	}
+	return opts, nil
+}
+
+func (o *options) run(out io.Writer, fSys filesys.FileSystem, dir string) error {
+	targets, err := findKustomizationDirs(fSys, dir)
+	if err != nil {
+		return err
+	}
+	kOpts, err := o.makeOptions()
+	if err != nil {
+		return err
+	}
+	k := krusty.MakeKustomizer(fSys, kOpts)
+	failures := 0
+	tested := 0
+	for _, target := range targets {
+		result, err := o.check(fSys, k, target)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s %s\n", result.status, target)
+		if result.detail != "" {
+			fmt.Fprintln(out, indent(result.detail, "    "))
+		}
+		if result.status == statusFail {
+			failures++
+		}
+		if result.status != statusSkip {
+			tested++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d kustomizations failed", failures, tested)
+	}
+	return nil
+}
+
+const (
+	statusPass    = "PASS"
+	statusFail    = "FAIL"
+	statusSkip    = "SKIP"
+	statusUpdated = "UPDATED"
+)
+
+type result struct {
+	status string
+	detail string
+}
+
+// check builds one target, comparing or updating its golden files.
+// Errors returned are about the golden files, not the build.
+func (o *options) check(
+	fSys filesys.FileSystem, k *krusty.Kustomizer, target string) (result, error) {
+	outFile := filepath.Join(target, goldenOutputFile)
+	errFile := filepath.Join(target, goldenErrorFile)
+	var actual, actualErr string
+	m, err := k.Run(target)
+	if err == nil {
+		var b []byte
+		b, err = m.AsYaml()
+		actual = string(b)
+	}
+	if err != nil {
+		actualErr = err.Error()
+	}
+	if o.update {
+		if actualErr != "" {
+			return result{status: statusUpdated},
+				writeGolden(fSys, errFile, outFile, actualErr+"\n")
+		}
+		return result{status: statusUpdated},
+			writeGolden(fSys, outFile, errFile, actual)
+	}
+	switch {
+	case fSys.Exists(errFile):
+		b, err := fSys.ReadFile(errFile)
+		if err != nil {
+			return result{}, err
+		}
+		expected := strings.TrimSpace(string(b))
+		if actualErr == "" {
+			return result{statusFail, "expected an error containing: " + expected}, nil
+		}
+		if !strings.Contains(actualErr, expected) {
+			return result{statusFail, fmt.Sprintf(
+				"expected an error containing: %s\nactual error: %s", expected, actualErr)}, nil
+		}
+		return result{status: statusPass}, nil
+	case fSys.Exists(outFile):
+		b, err := fSys.ReadFile(outFile)
+		if err != nil {
+			return result{}, err
+		}
+		if actualErr != "" {
+			return result{statusFail, "unexpected error: " + actualErr}, nil
+		}
+		// Golden files checked out with CRLF line
+		// endings, e.g. on Windows, still match.
+		expected := strings.ReplaceAll(string(b), "\r\n", "\n")
+		if d := firstDifference(expected, actual); d != "" {
+			return result{statusFail, d}, nil
+		}
+		return result{status: statusPass}, nil
+	default:
+		return result{status: statusSkip, detail: "no " + goldenOutputFile + " or " + goldenErrorFile}, nil
+	}
+}
+
+// writeGolden writes the given golden file, and removes the
+// other kind of golden file, so the two never coexist.
+func writeGolden(fSys filesys.FileSystem, path, other, content string) error {
+	if fSys.Exists(other) {
+		if err := fSys.RemoveAll(other); err != nil {
+			return err
+		}
+	}
+	return fSys.WriteFile(path, []byte(content))
+}
+
+// firstDifference describes the first line at which
+// the two strings differ, or returns the empty string
+// if they're equal.
+func firstDifference(expected, actual string) string {
+	if expected == actual {
+		return ""
+	}
+	e := strings.Split(expected, "\n")
+	a := strings.Split(actual, "\n")
+	for i := 0; i < len(e) || i < len(a); i++ {
+		var el, al string
+		if i < len(e) {
+			el = e[i]
+		}
+		if i < len(a) {
+			al = a[i]
+		}
+		if el != al || i >= len(e) || i >= len(a) {
+			return fmt.Sprintf(
+				"output differs from %s at line %d\nexpected: %s\nactual:   %s",
+				goldenOutputFile, i+1, el, al)
+		}
+	}
+	return ""
+}
+
+// findKustomizationDirs returns the sorted list of
+// directories at or below dir holding a kustomization file.
+func findKustomizationDirs(fSys filesys.FileSystem, dir string) ([]string, error) {
+	if !fSys.IsDir(dir) {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	seen := make(map[string]bool)
+	err := fSys.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		for _, n := range konfig.RecognizedKustomizationFileNames() {
+			if info.Name() == n {
+				seen[filepath.Dir(path)] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for d := range seen {
+		result = append(result, d)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+)
+
+const cmYaml = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dev-cm
+`
+
+func writeTree(fSys filesys.FileSystem) {
+	fSys.WriteFile("/r/base/kustomization.yaml", []byte("resources:\n- cm.yaml\n"))
+	fSys.WriteFile("/r/base/cm.yaml", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"))
+	fSys.WriteFile("/r/dev/kustomization.yaml", []byte("namePrefix: dev-\nresources:\n- ../base\n"))
+	fSys.WriteFile("/r/broken/kustomization.yaml", []byte("resources:\n- missing.yaml\n"))
+}
+
+func runTest(t *testing.T, fSys filesys.FileSystem, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := NewCmdTest(&out, fSys)
+	cmd.SetArgs(args)
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestGoldenPassAndSkip(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	writeTree(fSys)
+	fSys.WriteFile("/r/dev/golden.yaml", []byte(cmYaml))
+	fSys.WriteFile("/r/broken/golden.err", []byte("missing.yaml\n"))
+	out, err := runTest(t, fSys, "/r")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	for _, s := range []string{"SKIP /r/base\n", "PASS /r/broken\n", "PASS /r/dev\n"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in output:\n%s", s, out)
+		}
+	}
+}
+
+func TestGoldenWithCRLF(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	writeTree(fSys)
+	fSys.WriteFile("/r/dev/golden.yaml",
+		[]byte(strings.ReplaceAll(cmYaml, "\n", "\r\n")))
+	out, err := runTest(t, fSys, "/r/dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "PASS /r/dev\n") {
+		t.Errorf("expected a pass in output:\n%s", out)
+	}
+}
+
+func TestGoldenFail(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	writeTree(fSys)
+	fSys.WriteFile("/r/dev/golden.yaml", []byte(strings.Replace(cmYaml, "dev-cm", "cm", 1)))
+	fSys.WriteFile("/r/broken/golden.yaml", []byte(cmYaml))
+	out, err := runTest(t, fSys, "/r")
+	if err == nil || err.Error() != "2 of 2 kustomizations failed" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		"FAIL /r/dev\n    output differs from golden.yaml at line 4\n    expected:   name: cm\n    actual:     name: dev-cm\n",
+		"FAIL /r/broken\n    unexpected error: ",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in output:\n%s", s, out)
+		}
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	writeTree(fSys)
+	fSys.WriteFile("/r/broken/golden.yaml", []byte(cmYaml))
+	if out, err := runTest(t, fSys, "/r", "--update"); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	b, _ := fSys.ReadFile("/r/dev/golden.yaml")
+	if string(b) != cmYaml {
+		t.Errorf("unexpected golden output:\n%s", b)
+	}
+	if fSys.Exists("/r/broken/golden.yaml") || !fSys.Exists("/r/broken/golden.err") {
+		t.Errorf("expected golden.yaml to be replaced by golden.err")
+	}
+	if out, err := runTest(t, fSys, "/r"); err != nil {
+		t.Fatalf("unexpected error after update: %v\n%s", err, out)
+	}
+}
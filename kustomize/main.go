@@ -20,8 +20,9 @@ func main() {
 	cmd := commands.NewDefaultCommand()
 	complete.Complete(cmd).Complete("kustomize")
 
-	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+	err := cmd.Execute()
+	if err != nil {
+		commands.WriteError(os.Stderr, err)
 	}
-	os.Exit(0)
+	os.Exit(commands.ExitCode(err))
 }
@@ -56,3 +56,50 @@ func (buff buffer) UnmarshalJSON(b []byte) error {
 func (buff buffer) MarshalJSON() ([]byte, error) {
 	return buff.Bytes(), nil
 }
+
+func TestApplyToJSON_heldRNode(t *testing.T) {
+	node := yaml.MustParse(`kind: Foo # the kind
+spec:
+  b: "1"
+  a: 2
+`)
+	obj := holder{RNode: node}
+	err := filtersutil.ApplyToJSON(kio.FilterAll(yaml.SetField(
+		"foo", yaml.NewScalarRNode("bar"))), obj)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `kind: Foo # the kind
+spec:
+  b: "1"
+  a: 2
+foo: bar
+`, node.MustString())
+
+	err = filtersutil.ApplyToJSON(kio.FilterFunc(
+		func(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+			return []*yaml.RNode{nil}, nil
+		}), obj)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "{}\n", node.MustString())
+}
+
+// holder holds its data as an RNode, which it
+// refuses to marshal to tell that it's not.
+type holder struct {
+	*yaml.RNode
+}
+
+func (h holder) HeldRNode() *yaml.RNode {
+	return h.RNode
+}
+
+func (h holder) UnmarshalJSON([]byte) error {
+	return fmt.Errorf("unexpected conversion")
+}
+
+func (h holder) MarshalJSON() ([]byte, error) {
+	return nil, fmt.Errorf("unexpected conversion")
+}
@@ -11,18 +11,37 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+// RNodeHolder is implemented by objects holding their
+// data as an RNode.
+type RNodeHolder interface {
+	// HeldRNode returns the node holding the data
+	// of the object, or nil if it holds none.
+	HeldRNode() *yaml.RNode
+}
+
 // ApplyToJSON applies the filter to the json objects.
 //
 // ApplyToJSON marshals the objects into a slice of yaml.RNodes, runs
 // the filter on the slice, and then unmarshals the values back.
+// Objects implementing RNodeHolder are filtered in place instead,
+// keeping the order of their fields, their comments and the style
+// of their values, which the conversions to and from JSON lose.
 //
 // The filter must not create or delete objects because the objects
 // are updated in place.
 func ApplyToJSON(filter kio.Filter, objs ...marshalerUnmarshaler) error {
 	var nodes []*yaml.RNode
+	held := make([]*yaml.RNode, len(objs))
 
 	// convert the json objects to rnodes
 	for i := range objs {
+		if h, ok := objs[i].(RNodeHolder); ok {
+			held[i] = h.HeldRNode()
+		}
+		if held[i] != nil {
+			nodes = append(nodes, held[i])
+			continue
+		}
 		node, err := GetRNode(objs[i])
 		if err != nil {
 			return err
@@ -41,6 +60,10 @@ func ApplyToJSON(filter kio.Filter, objs ...marshalerUnmarshaler) error {
 
 	// convert the rnodes to json objects
 	for i := range nodes {
+		if held[i] != nil {
+			setHeldRNode(held[i], nodes[i])
+			continue
+		}
 		err = setRNode(objs[i], nodes[i])
 		if err != nil {
 			return err
@@ -50,13 +73,31 @@ func ApplyToJSON(filter kio.Filter, objs ...marshalerUnmarshaler) error {
 	return nil
 }
 
+// setHeldRNode updates the held node to the filtered one,
+// emptying it if the filter deleted its content.
+func setHeldRNode(held, node *yaml.RNode) {
+	switch {
+	case node == held:
+	case node == nil || node.YNode() == nil:
+		held.SetYNode(&yaml.Node{Kind: yaml.MappingNode})
+	default:
+		held.SetYNode(node.YNode())
+	}
+}
+
 type marshalerUnmarshaler interface {
 	json.Unmarshaler
 	json.Marshaler
 }
 
-// GetRNode converts k into an RNode
+// GetRNode converts k into an RNode, or returns a copy of
+// the node it holds, if it implements RNodeHolder.
 func GetRNode(k json.Marshaler) (*yaml.RNode, error) {
+	if h, ok := k.(RNodeHolder); ok {
+		if node := h.HeldRNode(); node != nil {
+			return node.Copy(), nil
+		}
+	}
 	j, err := k.MarshalJSON()
 	if err != nil {
 		return nil, err
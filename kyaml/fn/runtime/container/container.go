@@ -5,6 +5,10 @@ package container
 
 import (
 	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
 
 	runtimeexec "sigs.k8s.io/kustomize/kyaml/fn/runtime/exec"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
@@ -123,9 +127,106 @@ import (
 type Filter struct {
 	runtimeutil.ContainerSpec `json:",inline" yaml:",inline"`
 
+	// MaxMemory, if positive, is the memory in bytes the container may use
+	MaxMemory int64 `json:"-" yaml:"-"`
+
+	// Platform, if set, is the platform, os/arch[/variant], of
+	// the variant of the image to run, e.g. linux/arm64.
+	Platform string `json:"-" yaml:"-"`
+
+	// PullPlatform, if true, replaces a local image of another
+	// platform with the variant pulled for Platform, which was
+	// asked for; otherwise running a local image of another
+	// platform fails, rather than replacing the local tag.
+	PullPlatform bool `json:"-" yaml:"-"`
+
 	Exec runtimeexec.Filter
 }
 
+// HostPlatform returns the platform of the images
+// matching the host, whose containers run unemulated.
+func HostPlatform() string {
+	return "linux/" + runtime.GOARCH
+}
+
+// IsPlatform returns true if p has the form os/arch[/variant].
+func IsPlatform(p string) bool {
+	parts := strings.Split(p, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// samePlatform returns true if the platforms have the same
+// os and arch, and the same variant if both have one.
+func samePlatform(p1, p2 string) bool {
+	s1 := strings.Split(p1, "/")
+	s2 := strings.Split(p2, "/")
+	if len(s1) < 2 || len(s2) < 2 || s1[0] != s2[0] || s1[1] != s2[1] {
+		return false
+	}
+	return len(s1) < 3 || len(s2) < 3 || s1[2] == s2[2]
+}
+
+// imagePlatform returns the platform of the local image,
+// or an error if there's none.  Tests replace it.
+var imagePlatform = func(image string) (string, error) {
+	out, err := exec.Command(
+		"docker", "image", "inspect", "--format",
+		"{{.Os}}/{{.Architecture}}{{if .Variant}}/{{.Variant}}{{end}}",
+		image).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// pullImage pulls the variant of the image
+// for the platform.  Tests replace it.
+var pullImage = func(image, platform string) error {
+	out, err := exec.Command(
+		"docker", "pull", "--quiet", "--platform", platform, image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("couldn't pull image %s for platform %s: %v: %s",
+			image, platform, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// checkPlatform makes sure the local image, if any, is the
+// variant of the image for the platform of the filter, pulling
+// it if PullPlatform.  Docker runs a local image of another
+// platform emulated, or not at all, rather than selecting the
+// variant from the manifest list of the image; it pulls a
+// missing image for the platform.
+func (c *Filter) checkPlatform() error {
+	if c.Platform == "" || c.Image == "" {
+		return nil
+	}
+	p, err := imagePlatform(c.Image)
+	if err == nil && samePlatform(p, c.Platform) {
+		return nil
+	}
+	if c.PullPlatform {
+		return pullImage(c.Image, c.Platform)
+	}
+	if err != nil || p == "" {
+		// no local image, or one of an unknown platform
+		return nil
+	}
+	return fmt.Errorf(
+		"local image %s is for platform %s, not %s; run it with "+
+			"--fn-platform %s, or replace it with the variant for %s "+
+			"with --fn-platform %s",
+		c.Image, p, c.Platform, p, c.Platform, c.Platform)
+}
+
 func (c Filter) String() string {
 	if c.Exec.DeferFailure {
 		return fmt.Sprintf("%s deferFailure: %v", c.Image, c.Exec.DeferFailure)
@@ -137,6 +238,11 @@ func (c Filter) GetExit() error {
 }
 
 func (c *Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	if c.Exec.Path == "" {
+		if err := c.checkPlatform(); err != nil {
+			return nil, err
+		}
+	}
 	c.setupExec()
 	return c.Exec.Filter(nodes)
 }
@@ -167,6 +273,12 @@ func (c *Filter) getCommand() (string, []string) {
 		"--security-opt=no-new-privileges", // don't allow the user to escalate privileges
 		// note: don't make fs readonly because things like heredoc rely on writing tmp files
 	}
+	if c.MaxMemory > 0 {
+		args = append(args, "--memory", strconv.FormatInt(c.MaxMemory, 10))
+	}
+	if c.Platform != "" {
+		args = append(args, "--platform", c.Platform)
+	}
 
 	// TODO(joncwong): Allow StorageMount fields to have default values.
 	for _, storageMount := range c.StorageMounts {
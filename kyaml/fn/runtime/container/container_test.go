@@ -102,6 +102,58 @@ metadata:
 				},
 			),
 		},
+		{
+			name: "max memory",
+			functionConfig: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+`,
+			expectedArgs: []string{
+				"run",
+				"--rm",
+				"-i", "-a", "STDIN", "-a", "STDOUT", "-a", "STDERR",
+				"--network", "none",
+				"--user", "nobody",
+				"--security-opt=no-new-privileges",
+				"--memory", "536870912",
+			},
+			instance: Filter{
+				ContainerSpec: runtimeutil.ContainerSpec{
+					Image:   "example.com:version",
+					Network: runtimeutil.ContainerNetwork{Name: "none"},
+					User:    "nobody",
+				},
+				MaxMemory: 512 << 20,
+			},
+		},
+		{
+			name: "platform",
+			functionConfig: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+`,
+			expectedArgs: []string{
+				"run",
+				"--rm",
+				"-i", "-a", "STDIN", "-a", "STDOUT", "-a", "STDERR",
+				"--network", "none",
+				"--user", "nobody",
+				"--security-opt=no-new-privileges",
+				"--memory", "536870912",
+				"--platform", "linux/arm64",
+			},
+			instance: Filter{
+				ContainerSpec: runtimeutil.ContainerSpec{
+					Image:   "example.com:version",
+					Network: runtimeutil.ContainerNetwork{Name: "none"},
+					User:    "nobody",
+				},
+				MaxMemory: 512 << 20,
+				Platform:  "linux/arm64",
+			},
+		},
 		{
 			name: "root user",
 			functionConfig: `apiVersion: apps/v1
@@ -210,6 +262,80 @@ metadata:
 		t.FailNow()
 	}
 }
+func TestFilter_checkPlatform(t *testing.T) {
+	defer func(ip func(string) (string, error), pi func(string, string) error) {
+		imagePlatform, pullImage = ip, pi
+	}(imagePlatform, pullImage)
+	var pulled []string
+	pullImage = func(image, platform string) error {
+		pulled = append(pulled, image+" "+platform)
+		return nil
+	}
+	local := map[string]string{
+		"amd64:v1": "linux/amd64",
+		"arm64:v1": "linux/arm64/v8",
+		"blank:v1": "",
+	}
+	imagePlatform = func(image string) (string, error) {
+		if p, ok := local[image]; ok {
+			return p, nil
+		}
+		return "", fmt.Errorf("no such image: %s", image)
+	}
+
+	// The platform asked for replaces local images of others.
+	for _, image := range []string{"amd64:v1", "arm64:v1", "missing:v1"} {
+		c := Filter{
+			ContainerSpec: runtimeutil.ContainerSpec{Image: image},
+			Platform:      "linux/arm64",
+			PullPlatform:  true,
+		}
+		if !assert.NoError(t, c.checkPlatform()) {
+			t.FailNow()
+		}
+	}
+	assert.Equal(t, []string{"amd64:v1 linux/arm64", "missing:v1 linux/arm64"}, pulled)
+
+	// The host's platform doesn't; docker pulls missing images,
+	// and runs those of unknown platforms.
+	pulled = nil
+	for _, image := range []string{"arm64:v1", "missing:v1", "blank:v1"} {
+		c := Filter{
+			ContainerSpec: runtimeutil.ContainerSpec{Image: image},
+			Platform:      "linux/arm64",
+		}
+		if !assert.NoError(t, c.checkPlatform()) {
+			t.FailNow()
+		}
+	}
+	c := Filter{
+		ContainerSpec: runtimeutil.ContainerSpec{Image: "amd64:v1"},
+		Platform:      "linux/arm64",
+	}
+	assert.EqualError(t, c.checkPlatform(),
+		"local image amd64:v1 is for platform linux/amd64, not linux/arm64; "+
+			"run it with --fn-platform linux/amd64, or replace it with the "+
+			"variant for linux/arm64 with --fn-platform linux/arm64")
+	assert.Empty(t, pulled)
+
+	c = Filter{ContainerSpec: runtimeutil.ContainerSpec{Image: "amd64:v1"}}
+	assert.NoError(t, c.checkPlatform())
+	assert.Empty(t, pulled)
+}
+
+func TestIsPlatform(t *testing.T) {
+	for p, expected := range map[string]bool{
+		"linux/amd64":    true,
+		"linux/arm64/v8": true,
+		"linux":          false,
+		"linux/":         false,
+		"/arm64":         false,
+		"linux/arm/v7/x": false,
+	} {
+		assert.Equal(t, expected, IsPlatform(p), p)
+	}
+}
+
 func TestFilter_String(t *testing.T) {
 	instance := Filter{ContainerSpec: runtimeutil.ContainerSpec{Image: "foo"}}
 	if !assert.Equal(t, "foo", instance.String()) {
@@ -12,6 +12,10 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+// StartFunc starts a command, in place of its Start
+// method, e.g. to restrict what the process may do.
+type StartFunc func(cmd *exec.Cmd) error
+
 type Filter struct {
 	// Path is the path to the executable to run
 	Path string `yaml:"path,omitempty"`
@@ -19,6 +23,9 @@ type Filter struct {
 	// Args are the arguments to the executable
 	Args []string `yaml:"args,omitempty"`
 
+	// Start starts the executable, if set
+	Start StartFunc `yaml:"-"`
+
 	runtimeutil.FunctionFilter
 }
 
@@ -32,5 +39,11 @@ func (c *Filter) Run(reader io.Reader, writer io.Writer) error {
 	cmd.Stdin = reader
 	cmd.Stdout = writer
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	if c.Start == nil {
+		return cmd.Run()
+	}
+	if err := c.Start(cmd); err != nil {
+		return err
+	}
+	return cmd.Wait()
 }
@@ -4,6 +4,9 @@
 package exec_test
 
 import (
+	"bytes"
+	"fmt"
+	osexec "os/exec"
 	"strings"
 	"testing"
 
@@ -110,3 +113,25 @@ metadata:
 		})
 	}
 }
+
+func TestFilter_Run_Start(t *testing.T) {
+	var started []string
+	instance := exec.Filter{
+		Path: "cat",
+		Start: func(cmd *osexec.Cmd) error {
+			started = append(started, cmd.Path)
+			return cmd.Start()
+		},
+	}
+	var out bytes.Buffer
+	if !assert.NoError(t, instance.Run(strings.NewReader("hello"), &out)) {
+		t.FailNow()
+	}
+	assert.Equal(t, "hello", out.String())
+	assert.Len(t, started, 1)
+
+	instance.Start = func(cmd *osexec.Cmd) error {
+		return fmt.Errorf("refused")
+	}
+	assert.EqualError(t, instance.Run(strings.NewReader("hello"), &out), "refused")
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package compiled_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/openapi/internal/compiled"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const schema = `{
+  "definitions": {
+    "io.k8s.api.apps.v1.Deployment": {
+      "properties": {
+        "spec": {"$ref": "#/definitions/io.k8s.api.apps.v1.DeploymentSpec"}
+      },
+      "x-kubernetes-group-version-kind": [
+        {"group": "apps", "kind": "Deployment", "version": "v1"}
+      ]
+    },
+    "io.k8s.api.apps.v1.DeploymentSpec": {
+      "properties": {
+        "replicas": {"type": "integer", "format": "int32"}
+      }
+    },
+    "io.k8s.api.core.v1.Container": {
+      "properties": {
+        "ports": {
+          "items": {"$ref": "#/definitions/io.k8s.api.core.v1.ContainerPort"},
+          "type": "array",
+          "x-kubernetes-patch-merge-key": "containerPort",
+          "x-kubernetes-patch-strategy": "merge"
+        }
+      }
+    }
+  }
+}`
+
+func TestCompile(t *testing.T) {
+	b, err := compiled.Compile([]byte(schema))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	again, err := compiled.Compile([]byte(schema))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.True(t, bytes.Equal(b, again), "compiling twice gave different bytes")
+
+	s, err := compiled.Read(b)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []string{
+		"io.k8s.api.apps.v1.Deployment",
+		"io.k8s.api.apps.v1.DeploymentSpec",
+		"io.k8s.api.core.v1.Container",
+	}, s.Names())
+	assert.Equal(t, map[yaml.TypeMeta]string{
+		{APIVersion: "apps/v1", Kind: "Deployment"}: "io.k8s.api.apps.v1.Deployment",
+	}, s.ResourceTypes())
+
+	var sc spec.Schema
+	if !assert.NoError(t, sc.UnmarshalJSON([]byte(schema))) {
+		t.FailNow()
+	}
+	for _, name := range s.Names() {
+		d, found, err := s.Definition(name)
+		if !assert.NoError(t, err) || !assert.True(t, found) {
+			t.FailNow()
+		}
+		assert.Equal(t, sc.Definitions[name], *d)
+	}
+	_, found, err := s.Definition("io.k8s.api.core.v1.Pod")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Command compileschema compiles the OpenAPI schema in
+// JSON at the first path to the binary form read by the
+// openapi package, writing it to the second path.
+//
+//	go run ./openapi/internal/compileschema swagger.json swagger.gob
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"sigs.k8s.io/kustomize/kyaml/openapi/internal/compiled"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: compileschema SCHEMA.json OUTPUT")
+		os.Exit(2)
+	}
+	if err := compile(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func compile(in, out string) error {
+	j, err := ioutil.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	b, err := compiled.Compile(j)
+	if err != nil {
+		return fmt.Errorf("compiling %s: %v", in, err)
+	}
+	return ioutil.WriteFile(out, b, 0644)
+}
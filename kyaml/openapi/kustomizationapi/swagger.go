@@ -3,7 +3,7 @@
 
 // Code generated for package kustomizationapi by go-bindata DO NOT EDIT. (@generated)
 // sources:
-// openapi/kustomizationapi/swagger.json
+// openapi/kustomizationapi/swagger.gob
 package kustomizationapi
 
 import (
@@ -80,22 +80,22 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _openapiKustomizationapiSwaggerJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdc\x54\xb1\x6e\xdb\x30\x10\xdd\xfd\x15\x04\xdb\xd1\x52\xe0\xad\xf0\x56\x74\xe8\x10\x04\x08\x90\x6e\x45\x86\xb3\x7c\x52\xae\x92\x49\xf6\x78\x12\xea\x16\xfe\xf7\x42\xac\xa5\x88\xb6\xd4\xb4\x46\x1c\x24\x1e\x0c\x18\xd4\xdd\x7b\xbc\x7b\x8f\xef\xd7\x4c\x29\xbd\xc6\x9c\x0c\x09\x59\xe3\xf5\x52\xb5\x47\x4a\x69\xb2\x69\xf9\xc1\xa7\xe0\x28\x05\xe7\x7c\xda\x2c\xd2\x4f\xd6\xe4\x54\xdc\x80\xfb\xc8\xc5\x63\xa5\x52\xda\xb1\x75\xc8\x42\x38\x3c\x55\x4a\x7f\x46\x83\x0c\x62\xf9\xa0\x21\x7c\x7c\xcf\x98\xeb\xa5\xd2\xef\xae\x06\xfc\x57\x23\xb4\x31\x4a\x0f\xb1\xdb\xff\xdb\xcd\xbb\x6b\xc0\x7a\x1d\x50\xa0\xba\x1d\x5e\x28\x87\xca\x63\x5f\x24\x5b\x87\x2d\xad\x5d\x7d\xc3\x4c\x74\x7f\xfe\x23\x29\xeb\x15\xb2\x41\x41\x9f\x14\x6c\x6b\x97\x34\xc8\x9e\xac\x49\x4a\x32\x6b\xbd\x54\x5f\x7b\xea\x68\x8e\x50\xdb\x22\x96\xb5\x17\xbb\xa1\x9f\x98\x66\x61\x51\x61\x10\xb2\x3d\x45\xa8\xde\x63\xe9\x78\x97\x51\xc9\x9e\xb6\xad\x6a\x16\x2b\x14\x58\x1c\x0f\x7d\x3f\x1b\x8c\x3e\xa6\xd5\x1d\x66\x8c\xf2\x3a\x84\x7a\x9c\xae\xdb\x7e\x84\xdf\x29\xe2\x85\xc9\x14\x97\x22\xf0\x40\x80\xe7\x57\x77\x4a\xaf\x49\x81\x0d\x6c\xd0\x3b\xc8\xfe\x7d\xf9\xf3\xb8\xf9\x94\xbe\x15\x3e\x40\x43\x96\x4f\xe9\xbd\x6e\x6e\x81\xf8\xce\xd6\x9c\xe1\xe9\x8e\x8c\x51\x2e\xc4\x59\xb1\xf8\xcf\x6f\xae\xeb\xfd\x65\x40\xfe\x40\xf5\xe6\x62\xfc\x5e\x13\x63\x3c\x90\xfe\xb2\x75\x78\x83\x02\x1d\xd3\xfd\xfc\x29\x33\x66\x5d\xf6\xf5\x93\x1c\x0a\x4c\x82\x9b\x43\xd5\xff\x47\xf7\x38\x5d\x07\x20\xbb\xf9\x98\x11\x81\x19\xb6\xf1\x26\x23\x4d\x1d\x48\xf6\x90\x6c\x90\x0b\x4c\x4a\xdc\xb6\x2d\xe1\x4d\x3c\xd5\xe1\x85\x41\xb0\x08\x0d\xa1\x7b\xdc\xeb\x3e\x44\xc5\xd9\x96\x31\x48\xa2\x57\xb9\x89\x37\xfd\x18\xe3\xc7\x72\x86\xc7\x38\x91\x83\x93\x8f\xab\x22\x41\x86\xea\x28\x33\x27\x5c\x34\x95\xc5\x7f\x37\xc8\xa8\x8d\x73\xaa\x8e\xa3\xfa\xfc\xb4\x68\x9a\x97\x62\x7d\xdb\x4e\x8d\x9c\x74\xaa\x53\x67\xed\x6f\xf7\x3b\x00\x00\xff\xff\xfe\x97\xce\xec\x37\x0c\x00\x00")
+var _openapiKustomizationapiSwaggerGob = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x55\xcd\x6b\x13\x41\x14\x9f\xd7\x0f\x28\x51\xc1\x8b\x47\x51\xa6\x3d\x49\x76\x43\xc0\x83\xe4\x56\x14\x8a\x86\xc6\x40\xc4\x4b\xc9\x61\xb2\x79\xd9\x8e\x49\x76\xd6\x99\xc9\x62\x0c\xc1\x5a\x3f\xf0\x6f\xf6\xd0\xf5\xcd\x36\x6d\xb3\x6d\xd2\x66\x41\xe3\x61\x61\x99\xf7\x7e\xef\xe3\xf7\x7b\x6f\x66\xf7\xcb\x26\xc0\xb6\x15\x9d\x01\x42\x7a\xc2\x00\xe0\xde\x2b\xec\xc9\x48\x5a\xa9\x22\x03\xe9\x4f\xc6\xd8\x5e\xfa\x63\x03\xe0\xd1\x51\x3b\x50\xc3\x58\x0e\xb0\xeb\x77\x2f\x5d\x9c\x07\xa4\xa7\x8c\x3d\x4f\xbf\x52\xa4\xd2\xbc\xe5\x94\xc1\x26\x6c\x35\xc4\x10\xe1\x3e\x83\xad\x77\xe3\x98\x72\x7c\xa7\xbf\x37\xad\xb7\x0d\x28\x51\x64\x3f\xfd\x46\xa0\x1d\x67\x39\x44\x2b\x32\xeb\x06\x94\xf6\x9b\xaf\xdf\xa3\x36\x2e\x88\x03\xd6\x65\xd4\xa5\x1f\xc6\xce\x76\x7e\xa5\x27\xb0\x0d\x4f\xa5\xf2\xfb\x2f\x8c\x2f\x62\x49\x5f\x6c\xfc\xa4\xea\xbf\x54\x51\x4f\x86\x87\x22\xde\xd7\xa1\x01\x78\xd2\x1f\x19\xab\x86\xf2\x33\xfa\x41\x66\xc9\x00\x52\x55\x92\x6a\x87\x32\x55\xe1\x41\x0e\x40\x3d\xfc\x9e\x70\x4b\x75\xf0\x1a\x57\x9d\x0f\x18\x58\x5e\xe6\xb1\x56\x31\x6a\x2b\xd1\xf0\xda\x84\x1f\x60\x84\x5a\x58\xa5\x1d\xc0\x1d\xec\x69\xec\x91\xfb\x6e\xe5\xaa\x69\x53\x59\x50\x5a\x1e\x38\x9d\x96\xb9\xe8\x76\x33\x77\x31\x68\xce\xa5\xe8\x89\x81\xc1\x32\xff\xe4\xf5\x47\x1d\xd4\x11\x5a\x34\x5e\xa8\xd5\x28\xf6\x92\x73\x36\xbc\x3e\x31\xc1\x6b\x47\x13\x9e\x1d\x53\xee\x25\x5d\x52\xed\xe7\xae\x3c\xd7\x25\x1d\xcf\x22\x91\x65\x46\x04\x9f\xb6\xa7\x6c\x21\xa3\xb9\xb2\x57\x61\x34\x07\x60\x70\x06\x8d\xbb\x28\xad\x27\x4d\x21\x75\x4b\x8d\x74\x80\x85\x28\xcd\x03\x89\xd1\x0e\x1e\x8b\x44\x2a\xed\x82\xcc\x72\x1a\xab\x65\x14\x3a\x63\x44\x23\xb8\xd4\x60\x62\x11\x2c\xb2\xae\x55\xa6\xfc\x88\x14\x92\xa9\x3e\x8b\x2d\xb2\xa5\x5b\x41\xa6\x1c\x80\x64\xda\x78\x76\x53\x26\x8d\x1f\x47\x52\xa3\xeb\x82\x5f\x6c\x27\x6f\x5f\x97\x2f\xb8\x18\xae\xcb\xf2\xe7\x78\x14\x5a\x8b\x31\x85\x92\x16\x87\x85\xb4\xcd\x8f\xec\xf4\x1a\xd3\xb1\xb0\xc1\xb1\x37\x44\x1d\xa2\xd7\xc7\x31\x05\xcc\xd4\x5d\xe8\x45\x52\x0a\x8b\xa1\x73\xca\x00\x2e\x98\xc1\x40\xa3\xfd\xab\x05\xb7\xb2\x90\xff\xa0\xda\xb5\x8e\x60\x6e\x2c\x0a\x8e\xe0\xfc\x36\xae\x34\x82\xf3\x00\x77\x53\xd4\xef\xba\x29\x30\x4a\xcc\x6d\x5a\xdd\x5c\xde\x1e\xbd\x54\x05\x21\x03\xb2\x69\xa2\xb6\x08\x6a\xbd\x1a\xe5\xae\xbd\xa5\x1a\x3d\xbe\x75\x48\x57\x10\xa8\x74\xe5\xed\xd4\x79\xb8\xae\xa7\xb1\x3c\xcb\xf3\x7f\x2f\xe3\xb9\x7d\x5e\x46\x31\xfb\x03\xf9\xaf\xea\xb8\x38\x09\x00\x00")
 
-func openapiKustomizationapiSwaggerJsonBytes() ([]byte, error) {
+func openapiKustomizationapiSwaggerGobBytes() ([]byte, error) {
 	return bindataRead(
-		_openapiKustomizationapiSwaggerJson,
-		"openapi/kustomizationapi/swagger.json",
+		_openapiKustomizationapiSwaggerGob,
+		"openapi/kustomizationapi/swagger.gob",
 	)
 }
 
-func openapiKustomizationapiSwaggerJson() (*asset, error) {
-	bytes, err := openapiKustomizationapiSwaggerJsonBytes()
+func openapiKustomizationapiSwaggerGob() (*asset, error) {
+	bytes, err := openapiKustomizationapiSwaggerGobBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "openapi/kustomizationapi/swagger.json", size: 3127, mode: os.FileMode(420), modTime: time.Unix(1586844916, 0)}
+	info := bindataFileInfo{name: "openapi/kustomizationapi/swagger.gob", size: 2360, mode: os.FileMode(420), modTime: time.Unix(1792030434, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -152,7 +152,7 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"openapi/kustomizationapi/swagger.json": openapiKustomizationapiSwaggerJson,
+	"openapi/kustomizationapi/swagger.gob": openapiKustomizationapiSwaggerGob,
 }
 
 // AssetDir returns the file names below a certain
@@ -198,7 +198,7 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"openapi": &bintree{nil, map[string]*bintree{
 		"kustomizationapi": &bintree{nil, map[string]*bintree{
-			"swagger.json": &bintree{openapiKustomizationapiSwaggerJson, map[string]*bintree{}},
+			"swagger.gob": &bintree{openapiKustomizationapiSwaggerGob, map[string]*bintree{}},
 		}},
 	}},
 }}
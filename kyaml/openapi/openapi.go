@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/go-openapi/spec"
 	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/openapi/internal/compiled"
 	"sigs.k8s.io/kustomize/kyaml/openapi/kubernetesapi"
 	"sigs.k8s.io/kustomize/kyaml/openapi/kustomizationapi"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
@@ -27,6 +29,15 @@ type openapiData struct {
 	schema               spec.Schema
 	schemaByResourceType map[yaml.TypeMeta]*spec.Schema
 	noUseBuiltInSchema   bool
+
+	// mu guards the decoding of built-in definitions.
+	mu sync.Mutex
+	// builtin holds the compiled schema of each built-in
+	// definition not yet decoded into schema, by name.
+	builtin map[string]*compiled.Schema
+	// builtinByResourceType holds the name of the built-in
+	// definition, not yet decoded, of each resource type.
+	builtinByResourceType map[yaml.TypeMeta]string
 }
 
 // ResourceSchema wraps the OpenAPI Schema.
@@ -49,6 +60,11 @@ func (rs *ResourceSchema) IsMissingOrNull() bool {
 // as metadata, replicas and spec.template.spec
 func SchemaForResourceType(t yaml.TypeMeta) *ResourceSchema {
 	initSchema()
+	globalSchema.mu.Lock()
+	defer globalSchema.mu.Unlock()
+	if name, found := globalSchema.builtinByResourceType[t]; found {
+		decodeBuiltin(name)
+	}
 	rs, found := globalSchema.schemaByResourceType[t]
 	if !found {
 		return nil
@@ -121,6 +137,17 @@ func AddSchema(s []byte) (*spec.Schema, error) {
 	return parse(s)
 }
 
+// AddSchemaScoped parses s, and adds definitions from s to the
+// global schema as AddDefinitionsScoped does, returning a function
+// restoring the definitions they replaced.
+func AddSchemaScoped(s []byte) (restore func(), err error) {
+	var sc spec.Schema
+	if err = sc.UnmarshalJSON(s); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return AddDefinitionsScoped(sc.Definitions)
+}
+
 // ResetOpenAPI resets the openapi data to empty
 func ResetOpenAPI() {
 	globalSchema = openapiData{}
@@ -128,6 +155,115 @@ func ResetOpenAPI() {
 
 // AddDefinitions adds the definitions to the global schema.
 func AddDefinitions(definitions spec.Definitions) {
+	globalSchema.mu.Lock()
+	defer globalSchema.mu.Unlock()
+	addDefinitions(definitions)
+}
+
+// AddDefinitionsScoped adds the definitions as AddDefinitions
+// does, returning a function restoring the definitions they
+// replaced.  Scopes nest: restore them in reverse order.
+// It fails, adding nothing, on a malformed GVK extension.
+func AddDefinitionsScoped(definitions spec.Definitions) (restore func(), err error) {
+	if err = checkDefinitions(definitions); err != nil {
+		return nil, err
+	}
+	// the built-in schema is read first, lest it replace
+	// the definitions when read later
+	initSchema()
+	globalSchema.mu.Lock()
+	defer globalSchema.mu.Unlock()
+	saved := saveDefinitions(definitions)
+	addDefinitions(definitions)
+	return func() {
+		globalSchema.mu.Lock()
+		defer globalSchema.mu.Unlock()
+		saved.restore()
+	}, nil
+}
+
+// checkDefinitions checks the GVK extensions of the definitions.
+func checkDefinitions(definitions spec.Definitions) error {
+	var names []string
+	for k := range definitions {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if _, _, err := compiled.ResourceType(definitions[k]); err != nil {
+			return errors.WrapPrefixf(err, "definition %s", k)
+		}
+	}
+	return nil
+}
+
+// savedDefinitions holds the global state of definitions,
+// by name, and of the resource types they describe.
+type savedDefinitions struct {
+	definitions           map[string]*spec.Schema
+	builtin               map[string]*compiled.Schema
+	schemaByResourceType  map[yaml.TypeMeta]*spec.Schema
+	builtinByResourceType map[yaml.TypeMeta]string
+}
+
+// saveDefinitions saves the state of the given definitions;
+// absent ones are saved as nil or empty, to be deleted.
+func saveDefinitions(definitions spec.Definitions) savedDefinitions {
+	s := savedDefinitions{
+		definitions:           map[string]*spec.Schema{},
+		builtin:               map[string]*compiled.Schema{},
+		schemaByResourceType:  map[yaml.TypeMeta]*spec.Schema{},
+		builtinByResourceType: map[yaml.TypeMeta]string{},
+	}
+	for k := range definitions {
+		s.definitions[k] = nil
+		if d, found := globalSchema.schema.Definitions[k]; found {
+			s.definitions[k] = &d
+		}
+		s.builtin[k] = globalSchema.builtin[k]
+		t, found, _ := compiled.ResourceType(definitions[k])
+		if !found {
+			continue
+		}
+		s.schemaByResourceType[t] = globalSchema.schemaByResourceType[t]
+		s.builtinByResourceType[t] = globalSchema.builtinByResourceType[t]
+	}
+	return s
+}
+
+// restore restores the saved state.
+func (s savedDefinitions) restore() {
+	for k, d := range s.definitions {
+		if d == nil {
+			delete(globalSchema.schema.Definitions, k)
+		} else {
+			globalSchema.schema.Definitions[k] = *d
+		}
+	}
+	for k, b := range s.builtin {
+		if b == nil {
+			delete(globalSchema.builtin, k)
+		} else {
+			globalSchema.builtin[k] = b
+		}
+	}
+	for t, rs := range s.schemaByResourceType {
+		if rs == nil {
+			delete(globalSchema.schemaByResourceType, t)
+		} else {
+			globalSchema.schemaByResourceType[t] = rs
+		}
+	}
+	for t, name := range s.builtinByResourceType {
+		if name == "" {
+			delete(globalSchema.builtinByResourceType, t)
+		} else {
+			globalSchema.builtinByResourceType[t] = name
+		}
+	}
+}
+
+func addDefinitions(definitions spec.Definitions) {
 	// initialize values if they have not yet been set
 	if globalSchema.schemaByResourceType == nil {
 		globalSchema.schemaByResourceType = map[yaml.TypeMeta]*spec.Schema{}
@@ -142,35 +278,87 @@ func AddDefinitions(definitions spec.Definitions) {
 		// of a Resource
 		d := definitions[k]
 
-		// copy definitions to the schema
+		// copy definitions to the schema, in place of any built-in one
 		globalSchema.schema.Definitions[k] = d
-		gvk, found := d.VendorExtensible.Extensions[kubernetesGVKExtensionKey]
+		delete(globalSchema.builtin, k)
+		// a malformed GVK only indexes the definition by name
+		t, found, _ := compiled.ResourceType(d)
 		if !found {
 			continue
 		}
-		// cast the extension to a []map[string]string
-		exts, ok := gvk.([]interface{})
-		if !ok || len(exts) != 1 {
-			continue
-		}
-		m, ok := exts[0].(map[string]interface{})
-		if !ok {
-			continue
-		}
+		globalSchema.schemaByResourceType[t] = &d
+		delete(globalSchema.builtinByResourceType, t)
+	}
+}
 
-		// build the index key and save it
-		g := m[groupKey].(string)
-		apiVersion := m[versionKey].(string)
-		if g != "" {
-			apiVersion = g + "/" + apiVersion
-		}
-		globalSchema.schemaByResourceType[yaml.TypeMeta{Kind: m[kindKey].(string), APIVersion: apiVersion}] = &d
+// addBuiltin adds the definitions of the compiled schema
+// to the global schema, in place of any added before,
+// leaving them to decode as they're looked up.
+func addBuiltin(s *compiled.Schema) {
+	if globalSchema.builtin == nil {
+		globalSchema.builtin = map[string]*compiled.Schema{}
+		globalSchema.builtinByResourceType = map[yaml.TypeMeta]string{}
+	}
+	for _, name := range s.Names() {
+		globalSchema.builtin[name] = s
+		delete(globalSchema.schema.Definitions, name)
+	}
+	for t, name := range s.ResourceTypes() {
+		globalSchema.builtinByResourceType[t] = name
+		delete(globalSchema.schemaByResourceType, t)
+	}
+}
+
+// decodeBuiltin decodes the built-in definition of
+// the given name into the global schema, if not yet.
+func decodeBuiltin(name string) {
+	s, found := globalSchema.builtin[name]
+	if !found {
+		return
+	}
+	d, _, err := s.Definition(name)
+	if err != nil {
+		// the built-in schema was compiled from
+		// a schema parsed, so this should never happen
+		panic(err)
+	}
+	addDefinitions(spec.Definitions{name: *d})
+}
+
+// decodeBuiltins decodes the built-in
+// definitions not yet decoded.
+func decodeBuiltins() {
+	for name := range globalSchema.builtin {
+		decodeBuiltin(name)
 	}
 }
 
 // Resolve resolves the reference against the global schema
 func Resolve(ref *spec.Ref) (*spec.Schema, error) {
-	return resolve(Schema(), ref)
+	initSchema()
+	globalSchema.mu.Lock()
+	defer globalSchema.mu.Unlock()
+	// decode only the definition referenced, if the
+	// reference is to one, as they nearly always are
+	if name, found := definitionName(ref); found {
+		decodeBuiltin(name)
+	} else {
+		decodeBuiltins()
+	}
+	return resolve(&globalSchema.schema, ref)
+}
+
+// definitionName returns the name of the
+// definition the reference is to, if any.
+func definitionName(ref *spec.Ref) (string, bool) {
+	if ref.HasFullURL || ref.HasURLPathOnly {
+		return "", false
+	}
+	tokens := ref.GetPointer().DecodedTokens()
+	if len(tokens) < 2 || tokens[0] != "definitions" {
+		return "", false
+	}
+	return tokens[1], true
 }
 
 // Schema returns the global schema
@@ -299,48 +487,41 @@ func (rs *ResourceSchema) PatchStrategyAndKey() (string, string) {
 
 const (
 	// kubernetesAPIAssetName is the name of the asset containing the statically compiled in
-	// OpenAPI definitions for Kubernetes built-in types
-	kubernetesAPIAssetName = "openapi/kubernetesapi/swagger.json"
+	// OpenAPI definitions for Kubernetes built-in types, compiled per the compiled package
+	kubernetesAPIAssetName = "openapi/kubernetesapi/swagger.gob"
 
 	// kustomizationAPIAssetName is the name of the asset containing the statically compiled in
-	// OpenAPI definitions for Kustomization built-in types
-	kustomizationAPIAssetName = "openapi/kustomizationapi/swagger.json"
+	// OpenAPI definitions for Kustomization built-in types, compiled per the compiled package
+	kustomizationAPIAssetName = "openapi/kustomizationapi/swagger.gob"
 
-	// kubernetesGVKExtensionKey is the key to lookup the kubernetes group version kind extension
-	// -- the extension is an array of objects containing a gvk
-	kubernetesGVKExtensionKey = "x-kubernetes-group-version-kind"
 	// kubernetesMergeKeyExtensionKey is the key to lookup the kubernetes merge key extension
 	// -- the extension is a string
 	kubernetesMergeKeyExtensionKey = "x-kubernetes-patch-merge-key"
 	// kubernetesPatchStrategyExtensionKey is the key to lookup the kubernetes patch strategy
 	// extension -- the extension is a string
 	kubernetesPatchStrategyExtensionKey = "x-kubernetes-patch-strategy"
-
-	// groupKey is the key to lookup the group from the GVK extension
-	groupKey = "group"
-	// versionKey is the key to lookup the version from the GVK extension
-	versionKey = "version"
-	// kindKey is the the to lookup the kind from the GVK extension
-	kindKey = "kind"
 )
 
-// initSchema parses the json schema
+// initSchema reads the compiled schema, leaving
+// its definitions to decode as they're looked up
 func initSchema() {
 	globalSchema.setup.Do(func() {
 		if globalSchema.noUseBuiltInSchema {
-			// don't parse the built in schema
+			// don't read the built in schema
 			return
 		}
-
-		// parse the swagger, this should never fail
-		if _, err := parse(kubernetesapi.MustAsset(kubernetesAPIAssetName)); err != nil {
-			// this should never happen
-			panic(err)
-		}
-
-		if _, err := parse(kustomizationapi.MustAsset(kustomizationAPIAssetName)); err != nil {
-			// this should never happen
-			panic(err)
+		globalSchema.mu.Lock()
+		defer globalSchema.mu.Unlock()
+		for _, b := range [][]byte{
+			kubernetesapi.MustAsset(kubernetesAPIAssetName),
+			kustomizationapi.MustAsset(kustomizationAPIAssetName),
+		} {
+			s, err := compiled.Read(b)
+			if err != nil {
+				// this should never happen
+				panic(err)
+			}
+			addBuiltin(s)
 		}
 	})
 }
@@ -352,6 +533,9 @@ func parse(b []byte) (*spec.Schema, error) {
 	if err := sc.UnmarshalJSON(b); err != nil {
 		return nil, errors.Wrap(err)
 	}
+	if err := checkDefinitions(sc.Definitions); err != nil {
+		return nil, err
+	}
 	AddDefinitions(sc.Definitions)
 	return &sc, nil
 }
@@ -383,5 +567,8 @@ func resolve(root interface{}, ref *spec.Ref) (*spec.Schema, error) {
 
 func rootSchema() *spec.Schema {
 	initSchema()
+	globalSchema.mu.Lock()
+	defer globalSchema.mu.Unlock()
+	decodeBuiltins()
 	return &globalSchema.schema
 }
@@ -8,7 +8,11 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/go-openapi/spec"
 	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/openapi/internal/compiled"
+	"sigs.k8s.io/kustomize/kyaml/openapi/kubernetesapi"
+	"sigs.k8s.io/kustomize/kyaml/openapi/kustomizationapi"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -21,7 +25,7 @@ func TestAddSchema(t *testing.T) {
 		t.FailNow()
 	}
 	s, err := GetSchema(`{"$ref": "#/definitions/io.k8s.config.setters.replicas"}`)
-	if !assert.Greater(t, len(globalSchema.schema.Definitions), 200) {
+	if !assert.Greater(t, len(Schema().Definitions), 200) {
 		t.FailNow()
 	}
 	if !assert.NoError(t, err) {
@@ -124,6 +128,158 @@ func TestSchemaForResourceType(t *testing.T) {
 	}
 }
 
+// TestBuiltinSchemaCompiled checks the compiled assets
+// are up to date with the schemas; make openapi updates them.
+func TestBuiltinSchemaCompiled(t *testing.T) {
+	for path, asset := range map[string][]byte{
+		"kubernetesapi/swagger.json":    kubernetesapi.MustAsset(kubernetesAPIAssetName),
+		"kustomizationapi/swagger.json": kustomizationapi.MustAsset(kustomizationAPIAssetName),
+	} {
+		j, err := ioutil.ReadFile(path)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		b, err := compiled.Compile(j)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, b, asset, "%s isn't compiled; run make openapi", path)
+	}
+}
+
+func TestBuiltinSchemaDecodedLazily(t *testing.T) {
+	// reset package vars
+	globalSchema = openapiData{}
+
+	s := SchemaForResourceType(
+		yaml.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"})
+	if !assert.NotNil(t, s) || !assert.NotNil(t, s.Field("spec")) {
+		t.FailNow()
+	}
+	assert.Len(t, globalSchema.schema.Definitions, 2)
+	assert.Greater(t, len(Schema().Definitions), 200)
+	assert.Empty(t, globalSchema.builtin)
+}
+
+func TestAddDefinitions_overridesBuiltin(t *testing.T) {
+	// reset package vars
+	globalSchema = openapiData{}
+
+	deployment := yaml.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	if !assert.NotNil(t, SchemaForResourceType(deployment)) {
+		t.FailNow()
+	}
+	AddDefinitions(spec.Definitions{
+		"io.k8s.api.apps.v1.DeploymentSpec": *spec.StringProperty(),
+	})
+	ref := spec.MustCreateRef("#/definitions/io.k8s.api.apps.v1.DeploymentSpec")
+	sc, err := Resolve(&ref)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, spec.StringOrArray{"string"}, sc.Type)
+	assert.Equal(t, spec.StringOrArray{"string"},
+		SchemaForResourceType(deployment).Field("spec").Schema.Type)
+}
+
+func TestAddDefinitionsScoped(t *testing.T) {
+	// reset package vars
+	globalSchema = openapiData{}
+
+	deployment := yaml.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	foo := yaml.TypeMeta{APIVersion: "example.com/v1", Kind: "Foo"}
+	fooSchema := *spec.MapProperty(nil)
+	fooSchema.Extensions = spec.Extensions{
+		"x-kubernetes-group-version-kind": []interface{}{map[string]interface{}{
+			"group": "example.com", "version": "v1", "kind": "Foo"}},
+	}
+	restore, err := AddDefinitionsScoped(spec.Definitions{
+		"io.k8s.api.apps.v1.DeploymentSpec": *spec.StringProperty(),
+		"com.example.v1.Foo":                fooSchema,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, spec.StringOrArray{"string"},
+		SchemaForResourceType(deployment).Field("spec").Schema.Type)
+	assert.NotNil(t, SchemaForResourceType(foo))
+
+	restore()
+	assert.Equal(t, spec.StringOrArray{"object"},
+		SchemaForResourceType(deployment).Field("spec").Schema.Type)
+	assert.Nil(t, SchemaForResourceType(foo))
+	_, found := Schema().Definitions["com.example.v1.Foo"]
+	assert.False(t, found)
+}
+
+func TestAddSchemaScoped(t *testing.T) {
+	// reset package vars
+	globalSchema = openapiData{}
+
+	restore, err := AddSchemaScoped([]byte(`{"definitions": {
+  "io.k8s.cli.setters.replicas": {"x-k8s-cli": {"setter": {"name": "replicas", "value": "3"}}}
+}}`))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, found := Schema().Definitions["io.k8s.cli.setters.replicas"]
+	assert.True(t, found)
+
+	restore()
+	_, found = Schema().Definitions["io.k8s.cli.setters.replicas"]
+	assert.False(t, found)
+
+	_, err = AddSchemaScoped([]byte(`{"definitions": []}`))
+	assert.Error(t, err)
+}
+
+func TestAddDefinitionsScopedGVK(t *testing.T) {
+	// reset package vars
+	globalSchema = openapiData{}
+
+	testCases := map[string]struct {
+		gvk      map[string]interface{}
+		expected string
+	}{
+		"noGroup": {
+			gvk: map[string]interface{}{"version": "v1", "kind": "Bar"},
+		},
+		"noVersion": {
+			gvk:      map[string]interface{}{"group": "example.com", "kind": "Bar"},
+			expected: "definition com.example.v1.Bar: x-kubernetes-group-version-kind version must be a string, got <nil>",
+		},
+		"noKind": {
+			gvk:      map[string]interface{}{"group": "example.com", "version": "v1"},
+			expected: "definition com.example.v1.Bar: x-kubernetes-group-version-kind kind must be a string, got <nil>",
+		},
+		"groupNotString": {
+			gvk:      map[string]interface{}{"group": 1, "version": "v1", "kind": "Bar"},
+			expected: "definition com.example.v1.Bar: x-kubernetes-group-version-kind group must be a string, got 1",
+		},
+	}
+	for name, tc := range testCases {
+		bar := *spec.MapProperty(nil)
+		bar.Extensions = spec.Extensions{
+			"x-kubernetes-group-version-kind": []interface{}{tc.gvk},
+		}
+		restore, err := AddDefinitionsScoped(spec.Definitions{"com.example.v1.Bar": bar})
+		if tc.expected != "" {
+			if assert.Error(t, err, name) {
+				assert.Equal(t, tc.expected, err.Error(), name)
+			}
+			_, found := Schema().Definitions["com.example.v1.Bar"]
+			assert.False(t, found, name)
+			continue
+		}
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.NotNil(t, SchemaForResourceType(
+			yaml.TypeMeta{APIVersion: "v1", Kind: "Bar"}), name)
+		restore()
+	}
+}
+
 func TestAddSchemaFromFile(t *testing.T) {
 	ResetOpenAPI()
 	inputyaml := `
@@ -153,7 +309,7 @@ openAPI:
 	if !assert.NoError(t, err) {
 		t.FailNow()
 	}
-	if !assert.Greater(t, len(globalSchema.schema.Definitions), 200) {
+	if !assert.Greater(t, len(Schema().Definitions), 200) {
 		t.FailNow()
 	}
 	assert.Equal(t, `map[x-k8s-cli:map[setter:map[name:image-name value:nginx]]]`,
@@ -204,7 +360,7 @@ openAPI:
 	if !assert.NoError(t, err) {
 		t.FailNow()
 	}
-	if !assert.Greater(t, len(globalSchema.schema.Definitions), 200) {
+	if !assert.Greater(t, len(Schema().Definitions), 200) {
 		t.FailNow()
 	}
 
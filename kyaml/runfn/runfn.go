@@ -68,6 +68,27 @@ type RunFns struct {
 	// EnableExec will enable exec functions
 	EnableExec bool
 
+	// StartExec, if set, starts the processes of exec functions
+	StartExec exec.StartFunc
+
+	// StartContainer, if set, starts the processes running
+	// the containers of container functions
+	StartContainer exec.StartFunc
+
+	// ContainerMaxMemory, if positive, is the memory in bytes
+	// the containers of container functions may use
+	ContainerMaxMemory int64
+
+	// ContainerPlatform, if set, is the platform, os/arch[/variant],
+	// of the images of container functions; if empty, the local
+	// images are run whatever their platform
+	ContainerPlatform string
+
+	// ContainerPullPlatform, if true, replaces local images of
+	// another platform than ContainerPlatform with the variants
+	// pulled for it; otherwise running them fails
+	ContainerPullPlatform bool
+
 	// DisableContainers will disable functions run as containers
 	DisableContainers bool
 
@@ -417,6 +438,12 @@ func (r *RunFns) ffp(spec runtimeutil.FunctionSpec, api *yaml.RNode) (kio.Filter
 			Env:           spec.Container.Env,
 		})
 		cf := &c
+		cf.MaxMemory = r.ContainerMaxMemory
+		if r.ContainerPlatform != "" {
+			cf.Platform = r.ContainerPlatform
+			cf.PullPlatform = r.ContainerPullPlatform
+		}
+		cf.Exec.Start = r.StartContainer
 		cf.Exec.FunctionConfig = api
 		cf.Exec.GlobalScope = r.GlobalScope
 		cf.Exec.ResultsFile = resultsFile
@@ -456,7 +483,7 @@ func (r *RunFns) ffp(spec runtimeutil.FunctionSpec, api *yaml.RNode) (kio.Filter
 	}
 
 	if r.EnableExec && spec.Exec.Path != "" {
-		ef := &exec.Filter{Path: spec.Exec.Path}
+		ef := &exec.Filter{Path: spec.Exec.Path, Start: r.StartExec}
 
 		ef.FunctionConfig = api
 		ef.GlobalScope = r.GlobalScope
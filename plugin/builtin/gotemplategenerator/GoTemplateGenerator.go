@@ -0,0 +1,67 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/gotemplate"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Render Go templates into resources.
+// The values are the dot of the templates.
+type plugin struct {
+	h                *resmap.PluginHelpers
+	types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	Template         string                 `json:"template,omitempty" yaml:"template,omitempty"`
+	TemplateFiles    []string               `json:"templateFiles,omitempty" yaml:"templateFiles,omitempty"`
+	Values           map[string]interface{} `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(h *resmap.PluginHelpers, config []byte) (err error) {
+	p.Template = ""
+	p.TemplateFiles = nil
+	p.Values = nil
+	err = yaml.Unmarshal(config, p)
+	if err != nil {
+		return err
+	}
+	if p.Template == "" && len(p.TemplateFiles) == 0 {
+		return fmt.Errorf("must specify template or templateFiles")
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	var b bytes.Buffer
+	if p.Template != "" {
+		out, err := gotemplate.Render(p.Name, p.Template, p.Values)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(out)
+	}
+	for _, f := range p.TemplateFiles {
+		text, err := p.h.Loader().Load(f)
+		if err != nil {
+			return nil, err
+		}
+		out, err := gotemplate.Render(f, string(text), p.Values)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("\n---\n")
+		b.Write(out)
+	}
+	return p.h.ResmapFactory().NewResMapFromBytes(b.Bytes())
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestGoTemplateGenerator(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("GoTemplateGenerator").
+		EnableGoTemplate()
+	defer th.Reset()
+
+	th.WriteF("service.yaml.tmpl", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .name }}
+spec:
+  ports:
+{{- range .ports }}
+  - port: {{ . }}
+{{- end }}
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: GoTemplateGenerator
+metadata:
+  name: app
+values:
+  name: app
+  replicas: 3
+  labels:
+    tier: web
+  ports:
+  - 80
+  - 443
+templateFiles:
+- service.yaml.tmpl
+template: |
+  apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: {{ .name }}
+    labels:{{ .labels | toYaml | nindent 6 }}
+  spec:
+    replicas: {{ .replicas | default 1 }}
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: web
+  name: app
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+  - port: 443
+`)
+}
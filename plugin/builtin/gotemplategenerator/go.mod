@@ -0,0 +1,12 @@
+module sigs.k8s.io/kustomize/plugin/builtin/gotemplategenerator
+
+go 1.14
+
+require (
+	sigs.k8s.io/kustomize/api v0.5.1
+	sigs.k8s.io/yaml v1.2.0
+)
+
+replace sigs.k8s.io/kustomize/api v0.5.1 => ../../../api
+
+replace sigs.k8s.io/kustomize/kyaml v0.6.1 => ../../../kyaml
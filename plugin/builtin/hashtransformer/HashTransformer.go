@@ -6,9 +6,12 @@ package main
 
 import (
 	"fmt"
+	"runtime"
+	"sync"
 
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
 )
 
 type plugin struct {
@@ -24,16 +27,41 @@ func (p *plugin) Config(
 	return nil
 }
 
-// Transform appends hash to generated resources.
+// Transform appends hash to generated resources,
+// hashing them concurrently, as hashing large
+// ConfigMaps and Secrets takes a while.
 func (p *plugin) Transform(m resmap.ResMap) error {
+	var rs []*resource.Resource
 	for _, res := range m.Resources() {
 		if res.NeedHashSuffix() {
-			h, err := p.hasher.Hash(res)
-			if err != nil {
-				return err
+			rs = append(rs, res)
+		}
+	}
+	hashes := make([]string, len(rs))
+	errs := make([]error, len(rs))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU() && w < len(rs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				hashes[i], errs[i] = p.hasher.Hash(rs[i])
 			}
-			res.SetName(fmt.Sprintf("%s-%s", res.GetName(), h))
+		}()
+	}
+	for i := range rs {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	// name the resources in order, once all are
+	// hashed, so the first error is always the same
+	for i, res := range rs {
+		if errs[i] != nil {
+			return errs[i]
 		}
+		res.SetName(fmt.Sprintf("%s-%s", res.GetName(), hashes[i]))
 	}
 	return nil
 }
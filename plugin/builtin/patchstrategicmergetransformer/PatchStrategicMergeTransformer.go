@@ -22,6 +22,7 @@ type plugin struct {
 	loadedPatches []*resource.Resource
 	Paths         []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
 	Patches       string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+	Strict        bool                        `json:"strict,omitempty" yaml:"strict,omitempty"`
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -81,12 +82,13 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 		patchCopy.SetName(target.GetName())
 		patchCopy.SetNamespace(target.GetNamespace())
 		patchCopy.SetGvk(target.GetGvk())
-		node, err := filtersutil.GetRNode(patchCopy)
+		node, err := patchCopy.CopyRNode()
 		if err != nil {
 			return err
 		}
 		err = filtersutil.ApplyToJSON(patchstrategicmerge.Filter{
-			Patch: node,
+			Patch:  node,
+			Strict: p.Strict,
 		}, target)
 		if err != nil {
 			// Check for an error string from UnmarshalJSON that's indicative
@@ -98,14 +100,14 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 				// Some unknown error, let it through.
 				return err
 			}
-			if len(target.Map()) != 0 {
+			if !target.IsEmpty() {
 				return errors.Wrapf(
 					err, "with unexpectedly non-empty object map of size %d",
 					len(target.Map()))
 			}
 			// Fall through to handle deleted object.
 		}
-		if len(target.Map()) == 0 {
+		if target.IsEmpty() {
 			// This means all fields have been removed from the object.
 			// This can happen if a patch required deletion of the
 			// entire resource (not just a part of it).  This means
@@ -24,6 +24,7 @@ type plugin struct {
 	Path         string          `json:"path,omitempty" yaml:"path,omitempty"`
 	Patch        string          `json:"patch,omitempty" yaml:"patch,omitempty"`
 	Target       *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+	Strict       bool            `json:"strict,omitempty" yaml:"strict,omitempty"`
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -114,12 +115,13 @@ func (p *plugin) transformStrategicMerge(m resmap.ResMap, patch *resource.Resour
 // applySMPatch applies the provided strategic merge patch to the
 // given resource.
 func (p *plugin) applySMPatch(resource, patch *resource.Resource) error {
-	node, err := filtersutil.GetRNode(patch)
+	node, err := patch.CopyRNode()
 	if err != nil {
 		return err
 	}
 	return filtersutil.ApplyToJSON(patchstrategicmerge.Filter{
-		Patch: node,
+		Patch:  node,
+		Strict: p.Strict,
 	}, resource)
 }
 
@@ -8,3 +8,5 @@ require (
 )
 
 replace sigs.k8s.io/kustomize/api v0.5.1 => ../../../api
+
+replace sigs.k8s.io/kustomize/kyaml v0.6.1 => ../../../kyaml
@@ -5,3 +5,5 @@ go 1.14
 require sigs.k8s.io/kustomize/api v0.5.1
 
 replace sigs.k8s.io/kustomize/api v0.5.1 => ../../../../api
+
+replace sigs.k8s.io/kustomize/kyaml v0.6.1 => ../../../../kyaml